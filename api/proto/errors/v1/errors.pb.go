@@ -42,15 +42,32 @@ const (
 	AppErrorCode_EMPTY_BATCH        AppErrorCode = 11
 	AppErrorCode_BATCH_TOO_LARGE    AppErrorCode = 12
 	AppErrorCode_DUPLICATE_TITLE    AppErrorCode = 13
+	AppErrorCode_INVALID_ARGUMENT   AppErrorCode = 14
+	AppErrorCode_INVALID_FILTER     AppErrorCode = 15
+	AppErrorCode_DUE_SOON           AppErrorCode = 16
+	AppErrorCode_LONG_TITLE         AppErrorCode = 17
+	AppErrorCode_DISALLOWED_VALUE   AppErrorCode = 18
+	AppErrorCode_INVALID_FIELD_MASK AppErrorCode = 19
+	AppErrorCode_DISALLOWED_CONTENT AppErrorCode = 20
 	// Resource errors
 	AppErrorCode_RESOURCE_NOT_FOUND AppErrorCode = 1001
 	AppErrorCode_RESOURCE_CONFLICT  AppErrorCode = 1002
+	AppErrorCode_METHOD_NOT_ALLOWED AppErrorCode = 1003
+	AppErrorCode_NOT_IMPLEMENTED    AppErrorCode = 1004
+	AppErrorCode_INCONSISTENT_STATE AppErrorCode = 1005
 	// Authentication and authorization
 	AppErrorCode_AUTHENTICATION_FAILED AppErrorCode = 2001
 	AppErrorCode_PERMISSION_DENIED     AppErrorCode = 2002
+	AppErrorCode_REPLAYED_REQUEST      AppErrorCode = 2003
 	// Rate limiting and service availability
-	AppErrorCode_RATE_LIMIT_EXCEEDED AppErrorCode = 3001
-	AppErrorCode_SERVICE_UNAVAILABLE AppErrorCode = 3002
+	AppErrorCode_RATE_LIMIT_EXCEEDED  AppErrorCode = 3001
+	AppErrorCode_SERVICE_UNAVAILABLE  AppErrorCode = 3002
+	AppErrorCode_UPSTREAM_UNAVAILABLE AppErrorCode = 3003
+	AppErrorCode_TIMEOUT              AppErrorCode = 3004
+	AppErrorCode_FEATURE_DISABLED     AppErrorCode = 3005
+	AppErrorCode_READ_ONLY            AppErrorCode = 3006
+	AppErrorCode_RESPONSE_TOO_LARGE   AppErrorCode = 3007
+	AppErrorCode_CANCELED             AppErrorCode = 3008
 	// Internal errors
 	AppErrorCode_INTERNAL_ERROR AppErrorCode = 9001
 )
@@ -72,12 +89,29 @@ var (
 		11:   "EMPTY_BATCH",
 		12:   "BATCH_TOO_LARGE",
 		13:   "DUPLICATE_TITLE",
+		14:   "INVALID_ARGUMENT",
+		15:   "INVALID_FILTER",
+		16:   "DUE_SOON",
+		17:   "LONG_TITLE",
+		18:   "DISALLOWED_VALUE",
+		19:   "INVALID_FIELD_MASK",
+		20:   "DISALLOWED_CONTENT",
 		1001: "RESOURCE_NOT_FOUND",
 		1002: "RESOURCE_CONFLICT",
+		1003: "METHOD_NOT_ALLOWED",
+		1004: "NOT_IMPLEMENTED",
+		1005: "INCONSISTENT_STATE",
 		2001: "AUTHENTICATION_FAILED",
 		2002: "PERMISSION_DENIED",
+		2003: "REPLAYED_REQUEST",
 		3001: "RATE_LIMIT_EXCEEDED",
 		3002: "SERVICE_UNAVAILABLE",
+		3003: "UPSTREAM_UNAVAILABLE",
+		3004: "TIMEOUT",
+		3005: "FEATURE_DISABLED",
+		3006: "READ_ONLY",
+		3007: "RESPONSE_TOO_LARGE",
+		3008: "CANCELED",
 		9001: "INTERNAL_ERROR",
 	}
 	AppErrorCode_value = map[string]int32{
@@ -95,12 +129,29 @@ var (
 		"EMPTY_BATCH":                11,
 		"BATCH_TOO_LARGE":            12,
 		"DUPLICATE_TITLE":            13,
+		"INVALID_ARGUMENT":           14,
+		"INVALID_FILTER":             15,
+		"DUE_SOON":                   16,
+		"LONG_TITLE":                 17,
+		"DISALLOWED_VALUE":           18,
+		"INVALID_FIELD_MASK":         19,
+		"DISALLOWED_CONTENT":         20,
 		"RESOURCE_NOT_FOUND":         1001,
 		"RESOURCE_CONFLICT":          1002,
+		"METHOD_NOT_ALLOWED":         1003,
+		"NOT_IMPLEMENTED":            1004,
+		"INCONSISTENT_STATE":         1005,
 		"AUTHENTICATION_FAILED":      2001,
 		"PERMISSION_DENIED":          2002,
+		"REPLAYED_REQUEST":           2003,
 		"RATE_LIMIT_EXCEEDED":        3001,
 		"SERVICE_UNAVAILABLE":        3002,
+		"UPSTREAM_UNAVAILABLE":       3003,
+		"TIMEOUT":                    3004,
+		"FEATURE_DISABLED":           3005,
+		"READ_ONLY":                  3006,
+		"RESPONSE_TOO_LARGE":         3007,
+		"CANCELED":                   3008,
 		"INTERNAL_ERROR":             9001,
 	}
 )
@@ -151,7 +202,9 @@ type ErrorDetail struct {
 	// Optional instance path where the error occurred
 	Instance string `protobuf:"bytes,7,opt,name=instance,proto3" json:"instance,omitempty"`
 	// Optional extensions for additional error context
-	Extensions    map[string]*anypb.Any `protobuf:"bytes,8,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Extensions map[string]*anypb.Any `protobuf:"bytes,8,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Version of the error envelope schema, so clients can branch on it
+	Version       string `protobuf:"bytes,9,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -242,6 +295,13 @@ func (x *ErrorDetail) GetExtensions() map[string]*anypb.Any {
 	return nil
 }
 
+func (x *ErrorDetail) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
 // Describes a single validation failure.
 type FieldViolation struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -250,7 +310,10 @@ type FieldViolation struct {
 	// A developer-facing description of the validation rule that failed.
 	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
 	// Application-specific error code for this validation failure
-	Code          string `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Code string `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	// The offending input, truncated. Only set for fields that never carry
+	// personal data, such as a task title; never for account fields.
+	RejectedValue string `protobuf:"bytes,4,opt,name=rejected_value,json=rejectedValue,proto3" json:"rejected_value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -306,11 +369,18 @@ func (x *FieldViolation) GetCode() string {
 	return ""
 }
 
+func (x *FieldViolation) GetRejectedValue() string {
+	if x != nil {
+		return x.RejectedValue
+	}
+	return ""
+}
+
 var File_api_proto_errors_v1_errors_proto protoreflect.FileDescriptor
 
 const file_api_proto_errors_v1_errors_proto_rawDesc = "" +
 	"\n" +
-	" api/proto/errors/v1/errors.proto\x12\terrors.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x19google/protobuf/any.proto\"\xa3\x03\n" +
+	" api/proto/errors/v1/errors.proto\x12\terrors.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x19google/protobuf/any.proto\"\xbd\x03\n" +
 	"\vErrorDetail\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\tR\x04code\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
@@ -321,14 +391,16 @@ const file_api_proto_errors_v1_errors_proto_rawDesc = "" +
 	"\binstance\x18\a \x01(\tR\binstance\x12F\n" +
 	"\n" +
 	"extensions\x18\b \x03(\v2&.errors.v1.ErrorDetail.ExtensionsEntryR\n" +
-	"extensions\x1aS\n" +
+	"extensions\x12\x18\n" +
+	"\aversion\x18\t \x01(\tR\aversion\x1aS\n" +
 	"\x0fExtensionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
-	"\x05value\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\x05value:\x028\x01\"\\\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\x05value:\x028\x01\"\x83\x01\n" +
 	"\x0eFieldViolation\x12\x14\n" +
 	"\x05field\x18\x01 \x01(\tR\x05field\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
-	"\x04code\x18\x03 \x01(\tR\x04code*\xdd\x03\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12%\n" +
+	"\x0erejected_value\x18\x04 \x01(\tR\rrejectedValue*\xc2\x06\n" +
 	"\fAppErrorCode\x12\x1e\n" +
 	"\x1aAPP_ERROR_CODE_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11VALIDATION_FAILED\x10\x01\x12\x12\n" +
@@ -344,13 +416,31 @@ const file_api_proto_errors_v1_errors_proto_rawDesc = "" +
 	"\x12\x0f\n" +
 	"\vEMPTY_BATCH\x10\v\x12\x13\n" +
 	"\x0fBATCH_TOO_LARGE\x10\f\x12\x13\n" +
-	"\x0fDUPLICATE_TITLE\x10\r\x12\x17\n" +
+	"\x0fDUPLICATE_TITLE\x10\r\x12\x14\n" +
+	"\x10INVALID_ARGUMENT\x10\x0e\x12\x12\n" +
+	"\x0eINVALID_FILTER\x10\x0f\x12\f\n" +
+	"\bDUE_SOON\x10\x10\x12\x0e\n" +
+	"\n" +
+	"LONG_TITLE\x10\x11\x12\x14\n" +
+	"\x10DISALLOWED_VALUE\x10\x12\x12\x16\n" +
+	"\x12INVALID_FIELD_MASK\x10\x13\x12\x16\n" +
+	"\x12DISALLOWED_CONTENT\x10\x14\x12\x17\n" +
 	"\x12RESOURCE_NOT_FOUND\x10\xe9\a\x12\x16\n" +
-	"\x11RESOURCE_CONFLICT\x10\xea\a\x12\x1a\n" +
+	"\x11RESOURCE_CONFLICT\x10\xea\a\x12\x17\n" +
+	"\x12METHOD_NOT_ALLOWED\x10\xeb\a\x12\x14\n" +
+	"\x0fNOT_IMPLEMENTED\x10\xec\a\x12\x17\n" +
+	"\x12INCONSISTENT_STATE\x10\xed\a\x12\x1a\n" +
 	"\x15AUTHENTICATION_FAILED\x10\xd1\x0f\x12\x16\n" +
-	"\x11PERMISSION_DENIED\x10\xd2\x0f\x12\x18\n" +
+	"\x11PERMISSION_DENIED\x10\xd2\x0f\x12\x15\n" +
+	"\x10REPLAYED_REQUEST\x10\xd3\x0f\x12\x18\n" +
 	"\x13RATE_LIMIT_EXCEEDED\x10\xb9\x17\x12\x18\n" +
-	"\x13SERVICE_UNAVAILABLE\x10\xba\x17\x12\x13\n" +
+	"\x13SERVICE_UNAVAILABLE\x10\xba\x17\x12\x19\n" +
+	"\x14UPSTREAM_UNAVAILABLE\x10\xbb\x17\x12\f\n" +
+	"\aTIMEOUT\x10\xbc\x17\x12\x15\n" +
+	"\x10FEATURE_DISABLED\x10\xbd\x17\x12\x0e\n" +
+	"\tREAD_ONLY\x10\xbe\x17\x12\x17\n" +
+	"\x12RESPONSE_TOO_LARGE\x10\xbf\x17\x12\r\n" +
+	"\bCANCELED\x10\xc0\x17\x12\x13\n" +
 	"\x0eINTERNAL_ERROR\x10\xa9FB\xa5\x01\n" +
 	"\rcom.errors.v1B\vErrorsProtoP\x01ZBgithub.com/bhatti/todo-api-errors/gen/api/proto/errors/v1;errorsv1\xa2\x02\x03EXX\xaa\x02\tErrors.V1\xca\x02\tErrors\\V1\xe2\x02\x15Errors\\V1\\GPBMetadata\xea\x02\n" +
 	"Errors::V1b\x06proto3"
@@ -746,8 +746,12 @@ type GetAccountRequest struct {
 	state                protoimpl.MessageState `protogen:"open.v1"`
 	Id                   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	IncludeSensitiveData bool                   `protobuf:"varint,2,opt,name=include_sensitive_data,json=includeSensitiveData,proto3" json:"include_sensitive_data,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+	// Why the caller needs this data: BILLING, SUPPORT or FRAUD
+	Purpose string `protobuf:"bytes,3,opt,name=purpose,proto3" json:"purpose,omitempty"`
+	// Fields to return; all fields when empty. Unknown paths are rejected.
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetAccountRequest) Reset() {
@@ -794,6 +798,20 @@ func (x *GetAccountRequest) GetIncludeSensitiveData() bool {
 	return false
 }
 
+func (x *GetAccountRequest) GetPurpose() string {
+	if x != nil {
+		return x.Purpose
+	}
+	return ""
+}
+
+func (x *GetAccountRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
 type UpdateAccountRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
@@ -1034,9 +1052,15 @@ type SearchAccountsRequest struct {
 	Phone       string `protobuf:"bytes,3,opt,name=phone,proto3" json:"phone,omitempty"`
 	Ssn         string `protobuf:"bytes,4,opt,name=ssn,proto3" json:"ssn,omitempty"`
 	DateOfBirth string `protobuf:"bytes,5,opt,name=date_of_birth,json=dateOfBirth,proto3" json:"date_of_birth,omitempty"`
+	// Last four digits of a credit card; full card numbers are never searchable
+	CreditCardLast4 string `protobuf:"bytes,6,opt,name=credit_card_last4,json=creditCardLast4,proto3" json:"credit_card_last4,omitempty"`
 	// Pagination
-	PageSize      int32  `protobuf:"varint,10,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageToken     string `protobuf:"bytes,11,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	PageSize  int32  `protobuf:"varint,10,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,11,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Why the caller needs this data: BILLING, SUPPORT or FRAUD
+	Purpose string `protobuf:"bytes,12,opt,name=purpose,proto3" json:"purpose,omitempty"`
+	// Fields that must all match, e.g. "name=Smith AND status=ACTIVE"
+	Filter        string `protobuf:"bytes,13,opt,name=filter,proto3" json:"filter,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1106,6 +1130,13 @@ func (x *SearchAccountsRequest) GetDateOfBirth() string {
 	return ""
 }
 
+func (x *SearchAccountsRequest) GetCreditCardLast4() string {
+	if x != nil {
+		return x.CreditCardLast4
+	}
+	return ""
+}
+
 func (x *SearchAccountsRequest) GetPageSize() int32 {
 	if x != nil {
 		return x.PageSize
@@ -1120,6 +1151,20 @@ func (x *SearchAccountsRequest) GetPageToken() string {
 	return ""
 }
 
+func (x *SearchAccountsRequest) GetPurpose() string {
+	if x != nil {
+		return x.Purpose
+	}
+	return ""
+}
+
+func (x *SearchAccountsRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
 type SearchAccountsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Accounts      []*Account             `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`
@@ -1180,6 +1225,449 @@ func (x *SearchAccountsResponse) GetTotalMatches() int32 {
 	return 0
 }
 
+type ExportAccountDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportAccountDataRequest) Reset() {
+	*x = ExportAccountDataRequest{}
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportAccountDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportAccountDataRequest) ProtoMessage() {}
+
+func (x *ExportAccountDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportAccountDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportAccountDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_pii_v1_account_without_annotations_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExportAccountDataRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ExportAccountDataResponse is the unmasked bundle handed to the data subject
+type ExportAccountDataResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Account    *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	ExportedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=exported_at,json=exportedAt,proto3" json:"exported_at,omitempty"`
+	// Accesses to this account recorded by the audit log, oldest first
+	AuditEvents   []*AccountAuditEvent `protobuf:"bytes,3,rep,name=audit_events,json=auditEvents,proto3" json:"audit_events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportAccountDataResponse) Reset() {
+	*x = ExportAccountDataResponse{}
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportAccountDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportAccountDataResponse) ProtoMessage() {}
+
+func (x *ExportAccountDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportAccountDataResponse.ProtoReflect.Descriptor instead.
+func (*ExportAccountDataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_pii_v1_account_without_annotations_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ExportAccountDataResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *ExportAccountDataResponse) GetExportedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExportedAt
+	}
+	return nil
+}
+
+func (x *ExportAccountDataResponse) GetAuditEvents() []*AccountAuditEvent {
+	if x != nil {
+		return x.AuditEvents
+	}
+	return nil
+}
+
+type EraseAccountDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseAccountDataRequest) Reset() {
+	*x = EraseAccountDataRequest{}
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseAccountDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseAccountDataRequest) ProtoMessage() {}
+
+func (x *EraseAccountDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseAccountDataRequest.ProtoReflect.Descriptor instead.
+func (*EraseAccountDataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_pii_v1_account_without_annotations_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EraseAccountDataRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// BatchCreateAccountsRequest creates up to 100 accounts in one call
+type BatchCreateAccountsRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Requests      []*CreateAccountRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateAccountsRequest) Reset() {
+	*x = BatchCreateAccountsRequest{}
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateAccountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateAccountsRequest) ProtoMessage() {}
+
+func (x *BatchCreateAccountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateAccountsRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateAccountsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_pii_v1_account_without_annotations_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchCreateAccountsRequest) GetRequests() []*CreateAccountRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// BatchGetAccountsRequest fetches up to 100 accounts in one call
+type BatchGetAccountsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ids   []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// Why the caller needs this data: BILLING, SUPPORT or FRAUD
+	Purpose       string `protobuf:"bytes,2,opt,name=purpose,proto3" json:"purpose,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetAccountsRequest) Reset() {
+	*x = BatchGetAccountsRequest{}
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetAccountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetAccountsRequest) ProtoMessage() {}
+
+func (x *BatchGetAccountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetAccountsRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetAccountsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_pii_v1_account_without_annotations_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchGetAccountsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *BatchGetAccountsRequest) GetPurpose() string {
+	if x != nil {
+		return x.Purpose
+	}
+	return ""
+}
+
+// BatchAccountsResponse holds one result per requested item, in request order
+type BatchAccountsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchAccountResult  `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAccountsResponse) Reset() {
+	*x = BatchAccountsResponse{}
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAccountsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAccountsResponse) ProtoMessage() {}
+
+func (x *BatchAccountsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAccountsResponse.ProtoReflect.Descriptor instead.
+func (*BatchAccountsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_pii_v1_account_without_annotations_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BatchAccountsResponse) GetResults() []*BatchAccountResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// BatchAccountResult is the outcome for one item of a batch
+type BatchAccountResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Account ID; for a create, the ID of the new account if it succeeded
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The account, set only when the item succeeded
+	Account *Account `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	// Why the item failed, e.g. NOT_FOUND or VALIDATION_FAILED; empty on success
+	ErrorCode     string `protobuf:"bytes,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage  string `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAccountResult) Reset() {
+	*x = BatchAccountResult{}
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAccountResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAccountResult) ProtoMessage() {}
+
+func (x *BatchAccountResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAccountResult.ProtoReflect.Descriptor instead.
+func (*BatchAccountResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_pii_v1_account_without_annotations_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BatchAccountResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BatchAccountResult) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *BatchAccountResult) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *BatchAccountResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// AccountAuditEvent is one recorded access to an account's PII
+type AccountAuditEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Sensitivity   string                 `protobuf:"bytes,3,opt,name=sensitivity,proto3" json:"sensitivity,omitempty"`
+	Purpose       string                 `protobuf:"bytes,4,opt,name=purpose,proto3" json:"purpose,omitempty"`
+	Caller        string                 `protobuf:"bytes,5,opt,name=caller,proto3" json:"caller,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountAuditEvent) Reset() {
+	*x = AccountAuditEvent{}
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountAuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountAuditEvent) ProtoMessage() {}
+
+func (x *AccountAuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pii_v1_account_without_annotations_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountAuditEvent.ProtoReflect.Descriptor instead.
+func (*AccountAuditEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_pii_v1_account_without_annotations_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AccountAuditEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *AccountAuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AccountAuditEvent) GetSensitivity() string {
+	if x != nil {
+		return x.Sensitivity
+	}
+	return ""
+}
+
+func (x *AccountAuditEvent) GetPurpose() string {
+	if x != nil {
+		return x.Purpose
+	}
+	return ""
+}
+
+func (x *AccountAuditEvent) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
 var File_api_proto_pii_v1_account_without_annotations_proto protoreflect.FileDescriptor
 
 const file_api_proto_pii_v1_account_without_annotations_proto_rawDesc = "" +
@@ -1266,10 +1754,12 @@ const file_api_proto_pii_v1_account_without_annotations_proto_rawDesc = "" +
 	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"j\n" +
 	"\x14CreateAccountRequest\x12)\n" +
 	"\aaccount\x18\x01 \x01(\v2\x0f.pii.v1.AccountR\aaccount\x12'\n" +
-	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\"Y\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\"\xac\x01\n" +
 	"\x11GetAccountRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x124\n" +
-	"\x16include_sensitive_data\x18\x02 \x01(\bR\x14includeSensitiveData\"~\n" +
+	"\x16include_sensitive_data\x18\x02 \x01(\bR\x14includeSensitiveData\x12\x18\n" +
+	"\apurpose\x18\x03 \x01(\tR\apurpose\x127\n" +
+	"\tread_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"~\n" +
 	"\x14UpdateAccountRequest\x12)\n" +
 	"\aaccount\x18\x01 \x01(\v2\x0f.pii.v1.AccountR\aaccount\x12;\n" +
 	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
@@ -1288,21 +1778,52 @@ const file_api_proto_pii_v1_account_without_annotations_proto_rawDesc = "" +
 	"\baccounts\x18\x01 \x03(\v2\x0f.pii.v1.AccountR\baccounts\x12&\n" +
 	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1f\n" +
 	"\vtotal_count\x18\x03 \x01(\x05R\n" +
-	"totalCount\"\xc9\x01\n" +
+	"totalCount\"\xa7\x02\n" +
 	"\x15SearchAccountsRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x14\n" +
 	"\x05phone\x18\x03 \x01(\tR\x05phone\x12\x10\n" +
 	"\x03ssn\x18\x04 \x01(\tR\x03ssn\x12\"\n" +
-	"\rdate_of_birth\x18\x05 \x01(\tR\vdateOfBirth\x12\x1b\n" +
+	"\rdate_of_birth\x18\x05 \x01(\tR\vdateOfBirth\x12*\n" +
+	"\x11credit_card_last4\x18\x06 \x01(\tR\x0fcreditCardLast4\x12\x1b\n" +
 	"\tpage_size\x18\n" +
 	" \x01(\x05R\bpageSize\x12\x1d\n" +
 	"\n" +
-	"page_token\x18\v \x01(\tR\tpageToken\"\x92\x01\n" +
+	"page_token\x18\v \x01(\tR\tpageToken\x12\x18\n" +
+	"\apurpose\x18\f \x01(\tR\apurpose\x12\x16\n" +
+	"\x06filter\x18\r \x01(\tR\x06filter\"\x92\x01\n" +
 	"\x16SearchAccountsResponse\x12+\n" +
 	"\baccounts\x18\x01 \x03(\v2\x0f.pii.v1.AccountR\baccounts\x12&\n" +
 	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12#\n" +
-	"\rtotal_matches\x18\x03 \x01(\x05R\ftotalMatches*c\n" +
+	"\rtotal_matches\x18\x03 \x01(\x05R\ftotalMatches\"*\n" +
+	"\x18ExportAccountDataRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xc1\x01\n" +
+	"\x19ExportAccountDataResponse\x12)\n" +
+	"\aaccount\x18\x01 \x01(\v2\x0f.pii.v1.AccountR\aaccount\x12;\n" +
+	"\vexported_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"exportedAt\x12<\n" +
+	"\faudit_events\x18\x03 \x03(\v2\x19.pii.v1.AccountAuditEventR\vauditEvents\")\n" +
+	"\x17EraseAccountDataRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"V\n" +
+	"\x1aBatchCreateAccountsRequest\x128\n" +
+	"\brequests\x18\x01 \x03(\v2\x1c.pii.v1.CreateAccountRequestR\brequests\"E\n" +
+	"\x17BatchGetAccountsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x18\n" +
+	"\apurpose\x18\x02 \x01(\tR\apurpose\"M\n" +
+	"\x15BatchAccountsResponse\x124\n" +
+	"\aresults\x18\x01 \x03(\v2\x1a.pii.v1.BatchAccountResultR\aresults\"\x93\x01\n" +
+	"\x12BatchAccountResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\aaccount\x18\x02 \x01(\v2\x0f.pii.v1.AccountR\aaccount\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x03 \x01(\tR\terrorCode\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"\xb9\x01\n" +
+	"\x11AccountAuditEvent\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12 \n" +
+	"\vsensitivity\x18\x03 \x01(\tR\vsensitivity\x12\x18\n" +
+	"\apurpose\x18\x04 \x01(\tR\apurpose\x12\x16\n" +
+	"\x06caller\x18\x05 \x01(\tR\x06caller*c\n" +
 	"\rAccountStatus\x12\x1e\n" +
 	"\x1aACCOUNT_STATUS_UNSPECIFIED\x10\x00\x12\n" +
 	"\n" +
@@ -1310,7 +1831,7 @@ const file_api_proto_pii_v1_account_without_annotations_proto_rawDesc = "" +
 	"\tSUSPENDED\x10\x02\x12\n" +
 	"\n" +
 	"\x06CLOSED\x10\x03\x12\v\n" +
-	"\aPENDING\x10\x042\xad\x03\n" +
+	"\aPENDING\x10\x042\xfb\x05\n" +
 	"\x0eAccountService\x12>\n" +
 	"\rCreateAccount\x12\x1c.pii.v1.CreateAccountRequest\x1a\x0f.pii.v1.Account\x128\n" +
 	"\n" +
@@ -1318,7 +1839,11 @@ const file_api_proto_pii_v1_account_without_annotations_proto_rawDesc = "" +
 	"\rUpdateAccount\x12\x1c.pii.v1.UpdateAccountRequest\x1a\x0f.pii.v1.Account\x12E\n" +
 	"\rDeleteAccount\x12\x1c.pii.v1.DeleteAccountRequest\x1a\x16.google.protobuf.Empty\x12I\n" +
 	"\fListAccounts\x12\x1b.pii.v1.ListAccountsRequest\x1a\x1c.pii.v1.ListAccountsResponse\x12O\n" +
-	"\x0eSearchAccounts\x12\x1d.pii.v1.SearchAccountsRequest\x1a\x1e.pii.v1.SearchAccountsResponseB\xa3\x01\n" +
+	"\x0eSearchAccounts\x12\x1d.pii.v1.SearchAccountsRequest\x1a\x1e.pii.v1.SearchAccountsResponse\x12X\n" +
+	"\x11ExportAccountData\x12 .pii.v1.ExportAccountDataRequest\x1a!.pii.v1.ExportAccountDataResponse\x12D\n" +
+	"\x10EraseAccountData\x12\x1f.pii.v1.EraseAccountDataRequest\x1a\x0f.pii.v1.Account\x12X\n" +
+	"\x13BatchCreateAccounts\x12\".pii.v1.BatchCreateAccountsRequest\x1a\x1d.pii.v1.BatchAccountsResponse\x12R\n" +
+	"\x10BatchGetAccounts\x12\x1f.pii.v1.BatchGetAccountsRequest\x1a\x1d.pii.v1.BatchAccountsResponseB\xa3\x01\n" +
 	"\n" +
 	"com.pii.v1B\x1eAccountWithoutAnnotationsProtoP\x01Z<github.com/bhatti/todo-api-errors/gen/api/proto/pii/v1;piiv1\xa2\x02\x03PXX\xaa\x02\x06Pii.V1\xca\x02\x06Pii\\V1\xe2\x02\x12Pii\\V1\\GPBMetadata\xea\x02\aPii::V1b\x06proto3"
 
@@ -1335,57 +1860,81 @@ func file_api_proto_pii_v1_account_without_annotations_proto_rawDescGZIP() []byt
 }
 
 var file_api_proto_pii_v1_account_without_annotations_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_api_proto_pii_v1_account_without_annotations_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_api_proto_pii_v1_account_without_annotations_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_api_proto_pii_v1_account_without_annotations_proto_goTypes = []any{
-	(AccountStatus)(0),             // 0: pii.v1.AccountStatus
-	(*Account)(nil),                // 1: pii.v1.Account
-	(*Address)(nil),                // 2: pii.v1.Address
-	(*Location)(nil),               // 3: pii.v1.Location
-	(*CreateAccountRequest)(nil),   // 4: pii.v1.CreateAccountRequest
-	(*GetAccountRequest)(nil),      // 5: pii.v1.GetAccountRequest
-	(*UpdateAccountRequest)(nil),   // 6: pii.v1.UpdateAccountRequest
-	(*DeleteAccountRequest)(nil),   // 7: pii.v1.DeleteAccountRequest
-	(*ListAccountsRequest)(nil),    // 8: pii.v1.ListAccountsRequest
-	(*ListAccountsResponse)(nil),   // 9: pii.v1.ListAccountsResponse
-	(*SearchAccountsRequest)(nil),  // 10: pii.v1.SearchAccountsRequest
-	(*SearchAccountsResponse)(nil), // 11: pii.v1.SearchAccountsResponse
-	nil,                            // 12: pii.v1.Account.MetadataEntry
-	(*timestamppb.Timestamp)(nil),  // 13: google.protobuf.Timestamp
-	(*fieldmaskpb.FieldMask)(nil),  // 14: google.protobuf.FieldMask
-	(*emptypb.Empty)(nil),          // 15: google.protobuf.Empty
+	(AccountStatus)(0),                 // 0: pii.v1.AccountStatus
+	(*Account)(nil),                    // 1: pii.v1.Account
+	(*Address)(nil),                    // 2: pii.v1.Address
+	(*Location)(nil),                   // 3: pii.v1.Location
+	(*CreateAccountRequest)(nil),       // 4: pii.v1.CreateAccountRequest
+	(*GetAccountRequest)(nil),          // 5: pii.v1.GetAccountRequest
+	(*UpdateAccountRequest)(nil),       // 6: pii.v1.UpdateAccountRequest
+	(*DeleteAccountRequest)(nil),       // 7: pii.v1.DeleteAccountRequest
+	(*ListAccountsRequest)(nil),        // 8: pii.v1.ListAccountsRequest
+	(*ListAccountsResponse)(nil),       // 9: pii.v1.ListAccountsResponse
+	(*SearchAccountsRequest)(nil),      // 10: pii.v1.SearchAccountsRequest
+	(*SearchAccountsResponse)(nil),     // 11: pii.v1.SearchAccountsResponse
+	(*ExportAccountDataRequest)(nil),   // 12: pii.v1.ExportAccountDataRequest
+	(*ExportAccountDataResponse)(nil),  // 13: pii.v1.ExportAccountDataResponse
+	(*EraseAccountDataRequest)(nil),    // 14: pii.v1.EraseAccountDataRequest
+	(*BatchCreateAccountsRequest)(nil), // 15: pii.v1.BatchCreateAccountsRequest
+	(*BatchGetAccountsRequest)(nil),    // 16: pii.v1.BatchGetAccountsRequest
+	(*BatchAccountsResponse)(nil),      // 17: pii.v1.BatchAccountsResponse
+	(*BatchAccountResult)(nil),         // 18: pii.v1.BatchAccountResult
+	(*AccountAuditEvent)(nil),          // 19: pii.v1.AccountAuditEvent
+	nil,                                // 20: pii.v1.Account.MetadataEntry
+	(*timestamppb.Timestamp)(nil),      // 21: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),      // 22: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),              // 23: google.protobuf.Empty
 }
 var file_api_proto_pii_v1_account_without_annotations_proto_depIdxs = []int32{
 	0,  // 0: pii.v1.Account.status:type_name -> pii.v1.AccountStatus
-	13, // 1: pii.v1.Account.created_at:type_name -> google.protobuf.Timestamp
-	13, // 2: pii.v1.Account.updated_at:type_name -> google.protobuf.Timestamp
+	21, // 1: pii.v1.Account.created_at:type_name -> google.protobuf.Timestamp
+	21, // 2: pii.v1.Account.updated_at:type_name -> google.protobuf.Timestamp
 	2,  // 3: pii.v1.Account.home_address:type_name -> pii.v1.Address
 	2,  // 4: pii.v1.Account.work_address:type_name -> pii.v1.Address
 	2,  // 5: pii.v1.Account.mailing_address:type_name -> pii.v1.Address
 	3,  // 6: pii.v1.Account.last_location:type_name -> pii.v1.Location
-	12, // 7: pii.v1.Account.metadata:type_name -> pii.v1.Account.MetadataEntry
-	13, // 8: pii.v1.Location.timestamp:type_name -> google.protobuf.Timestamp
+	20, // 7: pii.v1.Account.metadata:type_name -> pii.v1.Account.MetadataEntry
+	21, // 8: pii.v1.Location.timestamp:type_name -> google.protobuf.Timestamp
 	1,  // 9: pii.v1.CreateAccountRequest.account:type_name -> pii.v1.Account
-	1,  // 10: pii.v1.UpdateAccountRequest.account:type_name -> pii.v1.Account
-	14, // 11: pii.v1.UpdateAccountRequest.update_mask:type_name -> google.protobuf.FieldMask
-	1,  // 12: pii.v1.ListAccountsResponse.accounts:type_name -> pii.v1.Account
-	1,  // 13: pii.v1.SearchAccountsResponse.accounts:type_name -> pii.v1.Account
-	4,  // 14: pii.v1.AccountService.CreateAccount:input_type -> pii.v1.CreateAccountRequest
-	5,  // 15: pii.v1.AccountService.GetAccount:input_type -> pii.v1.GetAccountRequest
-	6,  // 16: pii.v1.AccountService.UpdateAccount:input_type -> pii.v1.UpdateAccountRequest
-	7,  // 17: pii.v1.AccountService.DeleteAccount:input_type -> pii.v1.DeleteAccountRequest
-	8,  // 18: pii.v1.AccountService.ListAccounts:input_type -> pii.v1.ListAccountsRequest
-	10, // 19: pii.v1.AccountService.SearchAccounts:input_type -> pii.v1.SearchAccountsRequest
-	1,  // 20: pii.v1.AccountService.CreateAccount:output_type -> pii.v1.Account
-	1,  // 21: pii.v1.AccountService.GetAccount:output_type -> pii.v1.Account
-	1,  // 22: pii.v1.AccountService.UpdateAccount:output_type -> pii.v1.Account
-	15, // 23: pii.v1.AccountService.DeleteAccount:output_type -> google.protobuf.Empty
-	9,  // 24: pii.v1.AccountService.ListAccounts:output_type -> pii.v1.ListAccountsResponse
-	11, // 25: pii.v1.AccountService.SearchAccounts:output_type -> pii.v1.SearchAccountsResponse
-	20, // [20:26] is the sub-list for method output_type
-	14, // [14:20] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	22, // 10: pii.v1.GetAccountRequest.read_mask:type_name -> google.protobuf.FieldMask
+	1,  // 11: pii.v1.UpdateAccountRequest.account:type_name -> pii.v1.Account
+	22, // 12: pii.v1.UpdateAccountRequest.update_mask:type_name -> google.protobuf.FieldMask
+	1,  // 13: pii.v1.ListAccountsResponse.accounts:type_name -> pii.v1.Account
+	1,  // 14: pii.v1.SearchAccountsResponse.accounts:type_name -> pii.v1.Account
+	1,  // 15: pii.v1.ExportAccountDataResponse.account:type_name -> pii.v1.Account
+	21, // 16: pii.v1.ExportAccountDataResponse.exported_at:type_name -> google.protobuf.Timestamp
+	19, // 17: pii.v1.ExportAccountDataResponse.audit_events:type_name -> pii.v1.AccountAuditEvent
+	4,  // 18: pii.v1.BatchCreateAccountsRequest.requests:type_name -> pii.v1.CreateAccountRequest
+	18, // 19: pii.v1.BatchAccountsResponse.results:type_name -> pii.v1.BatchAccountResult
+	1,  // 20: pii.v1.BatchAccountResult.account:type_name -> pii.v1.Account
+	21, // 21: pii.v1.AccountAuditEvent.timestamp:type_name -> google.protobuf.Timestamp
+	4,  // 22: pii.v1.AccountService.CreateAccount:input_type -> pii.v1.CreateAccountRequest
+	5,  // 23: pii.v1.AccountService.GetAccount:input_type -> pii.v1.GetAccountRequest
+	6,  // 24: pii.v1.AccountService.UpdateAccount:input_type -> pii.v1.UpdateAccountRequest
+	7,  // 25: pii.v1.AccountService.DeleteAccount:input_type -> pii.v1.DeleteAccountRequest
+	8,  // 26: pii.v1.AccountService.ListAccounts:input_type -> pii.v1.ListAccountsRequest
+	10, // 27: pii.v1.AccountService.SearchAccounts:input_type -> pii.v1.SearchAccountsRequest
+	12, // 28: pii.v1.AccountService.ExportAccountData:input_type -> pii.v1.ExportAccountDataRequest
+	14, // 29: pii.v1.AccountService.EraseAccountData:input_type -> pii.v1.EraseAccountDataRequest
+	15, // 30: pii.v1.AccountService.BatchCreateAccounts:input_type -> pii.v1.BatchCreateAccountsRequest
+	16, // 31: pii.v1.AccountService.BatchGetAccounts:input_type -> pii.v1.BatchGetAccountsRequest
+	1,  // 32: pii.v1.AccountService.CreateAccount:output_type -> pii.v1.Account
+	1,  // 33: pii.v1.AccountService.GetAccount:output_type -> pii.v1.Account
+	1,  // 34: pii.v1.AccountService.UpdateAccount:output_type -> pii.v1.Account
+	23, // 35: pii.v1.AccountService.DeleteAccount:output_type -> google.protobuf.Empty
+	9,  // 36: pii.v1.AccountService.ListAccounts:output_type -> pii.v1.ListAccountsResponse
+	11, // 37: pii.v1.AccountService.SearchAccounts:output_type -> pii.v1.SearchAccountsResponse
+	13, // 38: pii.v1.AccountService.ExportAccountData:output_type -> pii.v1.ExportAccountDataResponse
+	1,  // 39: pii.v1.AccountService.EraseAccountData:output_type -> pii.v1.Account
+	17, // 40: pii.v1.AccountService.BatchCreateAccounts:output_type -> pii.v1.BatchAccountsResponse
+	17, // 41: pii.v1.AccountService.BatchGetAccounts:output_type -> pii.v1.BatchAccountsResponse
+	32, // [32:42] is the sub-list for method output_type
+	22, // [22:32] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_pii_v1_account_without_annotations_proto_init() }
@@ -1399,7 +1948,7 @@ func file_api_proto_pii_v1_account_without_annotations_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_pii_v1_account_without_annotations_proto_rawDesc), len(file_api_proto_pii_v1_account_without_annotations_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   12,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
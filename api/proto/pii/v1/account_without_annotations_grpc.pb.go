@@ -20,12 +20,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AccountService_CreateAccount_FullMethodName  = "/pii.v1.AccountService/CreateAccount"
-	AccountService_GetAccount_FullMethodName     = "/pii.v1.AccountService/GetAccount"
-	AccountService_UpdateAccount_FullMethodName  = "/pii.v1.AccountService/UpdateAccount"
-	AccountService_DeleteAccount_FullMethodName  = "/pii.v1.AccountService/DeleteAccount"
-	AccountService_ListAccounts_FullMethodName   = "/pii.v1.AccountService/ListAccounts"
-	AccountService_SearchAccounts_FullMethodName = "/pii.v1.AccountService/SearchAccounts"
+	AccountService_CreateAccount_FullMethodName       = "/pii.v1.AccountService/CreateAccount"
+	AccountService_GetAccount_FullMethodName          = "/pii.v1.AccountService/GetAccount"
+	AccountService_UpdateAccount_FullMethodName       = "/pii.v1.AccountService/UpdateAccount"
+	AccountService_DeleteAccount_FullMethodName       = "/pii.v1.AccountService/DeleteAccount"
+	AccountService_ListAccounts_FullMethodName        = "/pii.v1.AccountService/ListAccounts"
+	AccountService_SearchAccounts_FullMethodName      = "/pii.v1.AccountService/SearchAccounts"
+	AccountService_ExportAccountData_FullMethodName   = "/pii.v1.AccountService/ExportAccountData"
+	AccountService_EraseAccountData_FullMethodName    = "/pii.v1.AccountService/EraseAccountData"
+	AccountService_BatchCreateAccounts_FullMethodName = "/pii.v1.AccountService/BatchCreateAccounts"
+	AccountService_BatchGetAccounts_FullMethodName    = "/pii.v1.AccountService/BatchGetAccounts"
 )
 
 // AccountServiceClient is the client API for AccountService service.
@@ -47,6 +51,14 @@ type AccountServiceClient interface {
 	ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
 	// Search accounts by various fields
 	SearchAccounts(ctx context.Context, in *SearchAccountsRequest, opts ...grpc.CallOption) (*SearchAccountsResponse, error)
+	// Export all data held about an account (GDPR data portability)
+	ExportAccountData(ctx context.Context, in *ExportAccountDataRequest, opts ...grpc.CallOption) (*ExportAccountDataResponse, error)
+	// Erase all PII on an account, keeping a tombstone record
+	EraseAccountData(ctx context.Context, in *EraseAccountDataRequest, opts ...grpc.CallOption) (*Account, error)
+	// Create several accounts, reporting success or failure per account
+	BatchCreateAccounts(ctx context.Context, in *BatchCreateAccountsRequest, opts ...grpc.CallOption) (*BatchAccountsResponse, error)
+	// Get several accounts, reporting success or failure per ID
+	BatchGetAccounts(ctx context.Context, in *BatchGetAccountsRequest, opts ...grpc.CallOption) (*BatchAccountsResponse, error)
 }
 
 type accountServiceClient struct {
@@ -117,6 +129,46 @@ func (c *accountServiceClient) SearchAccounts(ctx context.Context, in *SearchAcc
 	return out, nil
 }
 
+func (c *accountServiceClient) ExportAccountData(ctx context.Context, in *ExportAccountDataRequest, opts ...grpc.CallOption) (*ExportAccountDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportAccountDataResponse)
+	err := c.cc.Invoke(ctx, AccountService_ExportAccountData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) EraseAccountData(ctx context.Context, in *EraseAccountDataRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, AccountService_EraseAccountData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) BatchCreateAccounts(ctx context.Context, in *BatchCreateAccountsRequest, opts ...grpc.CallOption) (*BatchAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchAccountsResponse)
+	err := c.cc.Invoke(ctx, AccountService_BatchCreateAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) BatchGetAccounts(ctx context.Context, in *BatchGetAccountsRequest, opts ...grpc.CallOption) (*BatchAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchAccountsResponse)
+	err := c.cc.Invoke(ctx, AccountService_BatchGetAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AccountServiceServer is the server API for AccountService service.
 // All implementations must embed UnimplementedAccountServiceServer
 // for forward compatibility.
@@ -136,6 +188,14 @@ type AccountServiceServer interface {
 	ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
 	// Search accounts by various fields
 	SearchAccounts(context.Context, *SearchAccountsRequest) (*SearchAccountsResponse, error)
+	// Export all data held about an account (GDPR data portability)
+	ExportAccountData(context.Context, *ExportAccountDataRequest) (*ExportAccountDataResponse, error)
+	// Erase all PII on an account, keeping a tombstone record
+	EraseAccountData(context.Context, *EraseAccountDataRequest) (*Account, error)
+	// Create several accounts, reporting success or failure per account
+	BatchCreateAccounts(context.Context, *BatchCreateAccountsRequest) (*BatchAccountsResponse, error)
+	// Get several accounts, reporting success or failure per ID
+	BatchGetAccounts(context.Context, *BatchGetAccountsRequest) (*BatchAccountsResponse, error)
 	mustEmbedUnimplementedAccountServiceServer()
 }
 
@@ -164,6 +224,18 @@ func (UnimplementedAccountServiceServer) ListAccounts(context.Context, *ListAcco
 func (UnimplementedAccountServiceServer) SearchAccounts(context.Context, *SearchAccountsRequest) (*SearchAccountsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchAccounts not implemented")
 }
+func (UnimplementedAccountServiceServer) ExportAccountData(context.Context, *ExportAccountDataRequest) (*ExportAccountDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportAccountData not implemented")
+}
+func (UnimplementedAccountServiceServer) EraseAccountData(context.Context, *EraseAccountDataRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseAccountData not implemented")
+}
+func (UnimplementedAccountServiceServer) BatchCreateAccounts(context.Context, *BatchCreateAccountsRequest) (*BatchAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateAccounts not implemented")
+}
+func (UnimplementedAccountServiceServer) BatchGetAccounts(context.Context, *BatchGetAccountsRequest) (*BatchAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetAccounts not implemented")
+}
 func (UnimplementedAccountServiceServer) mustEmbedUnimplementedAccountServiceServer() {}
 func (UnimplementedAccountServiceServer) testEmbeddedByValue()                        {}
 
@@ -293,6 +365,78 @@ func _AccountService_SearchAccounts_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AccountService_ExportAccountData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportAccountDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ExportAccountData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_ExportAccountData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ExportAccountData(ctx, req.(*ExportAccountDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_EraseAccountData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseAccountDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).EraseAccountData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_EraseAccountData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).EraseAccountData(ctx, req.(*EraseAccountDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_BatchCreateAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).BatchCreateAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_BatchCreateAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).BatchCreateAccounts(ctx, req.(*BatchCreateAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_BatchGetAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).BatchGetAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_BatchGetAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).BatchGetAccounts(ctx, req.(*BatchGetAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AccountService_ServiceDesc is the grpc.ServiceDesc for AccountService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -324,6 +468,22 @@ var AccountService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchAccounts",
 			Handler:    _AccountService_SearchAccounts_Handler,
 		},
+		{
+			MethodName: "ExportAccountData",
+			Handler:    _AccountService_ExportAccountData_Handler,
+		},
+		{
+			MethodName: "EraseAccountData",
+			Handler:    _AccountService_EraseAccountData_Handler,
+		},
+		{
+			MethodName: "BatchCreateAccounts",
+			Handler:    _AccountService_BatchCreateAccounts_Handler,
+		},
+		{
+			MethodName: "BatchGetAccounts",
+			Handler:    _AccountService_BatchGetAccounts_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/pii/v1/account_without_annotations.proto",
@@ -137,6 +137,59 @@ func (Priority) EnumDescriptor() ([]byte, []int) {
 	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{1}
 }
 
+// BatchGetStatus is the outcome of looking up one name in BatchGetTasks
+type BatchGetStatus int32
+
+const (
+	BatchGetStatus_BATCH_GET_STATUS_UNSPECIFIED BatchGetStatus = 0
+	BatchGetStatus_BATCH_GET_STATUS_FOUND       BatchGetStatus = 1
+	BatchGetStatus_BATCH_GET_STATUS_NOT_FOUND   BatchGetStatus = 2
+	BatchGetStatus_BATCH_GET_STATUS_FORBIDDEN   BatchGetStatus = 3
+)
+
+// Enum value maps for BatchGetStatus.
+var (
+	BatchGetStatus_name = map[int32]string{
+		0: "BATCH_GET_STATUS_UNSPECIFIED",
+		1: "BATCH_GET_STATUS_FOUND",
+		2: "BATCH_GET_STATUS_NOT_FOUND",
+		3: "BATCH_GET_STATUS_FORBIDDEN",
+	}
+	BatchGetStatus_value = map[string]int32{
+		"BATCH_GET_STATUS_UNSPECIFIED": 0,
+		"BATCH_GET_STATUS_FOUND":       1,
+		"BATCH_GET_STATUS_NOT_FOUND":   2,
+		"BATCH_GET_STATUS_FORBIDDEN":   3,
+	}
+)
+
+func (x BatchGetStatus) Enum() *BatchGetStatus {
+	p := new(BatchGetStatus)
+	*p = x
+	return p
+}
+
+func (x BatchGetStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchGetStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_todo_v1_todo_proto_enumTypes[2].Descriptor()
+}
+
+func (BatchGetStatus) Type() protoreflect.EnumType {
+	return &file_api_proto_todo_v1_todo_proto_enumTypes[2]
+}
+
+func (x BatchGetStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchGetStatus.Descriptor instead.
+func (BatchGetStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{2}
+}
+
 // Task represents a TODO item
 type Task struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -159,7 +212,12 @@ type Task struct {
 	// User who created the task
 	CreatedBy string `protobuf:"bytes,9,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
 	// Tags associated with the task
-	Tags          []string `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	Tags []string `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Tenant that owns the task
+	TenantId string `protobuf:"bytes,11,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// IANA time zone of the task, e.g. America/Los_Angeles. A due date at
+	// midnight UTC is then read as a date, due at the end of that day in this zone.
+	TimeZone      string `protobuf:"bytes,12,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -264,13 +322,32 @@ func (x *Task) GetTags() []string {
 	return nil
 }
 
+func (x *Task) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Task) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
 // CreateTaskRequest message
 type CreateTaskRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Task to create
-	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Task *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// Optional client-chosen ID for the task. When omitted the server generates one.
+	TaskId string `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Optional due date relative to now, e.g. "+72h", "+3d" or "tomorrow".
+	// Mutually exclusive with task.due_date.
+	RelativeDueDate string `protobuf:"bytes,3,opt,name=relative_due_date,json=relativeDueDate,proto3" json:"relative_due_date,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *CreateTaskRequest) Reset() {
@@ -310,6 +387,20 @@ func (x *CreateTaskRequest) GetTask() *Task {
 	return nil
 }
 
+func (x *CreateTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetRelativeDueDate() string {
+	if x != nil {
+		return x.RelativeDueDate
+	}
+	return ""
+}
+
 // GetTaskRequest message
 type GetTaskRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -365,8 +456,12 @@ type ListTasksRequest struct {
 	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	// Filter expression
 	Filter string `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
-	// Order by expression
-	OrderBy       string `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// Order by expression: title, create_time, -create_time, due_date, priority
+	// or -priority (most urgent first). Empty uses the server default, newest first
+	// unless configured otherwise
+	OrderBy string `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// Skip computing total_size, which is then returned as -1
+	SkipTotal     bool `protobuf:"varint,5,opt,name=skip_total,json=skipTotal,proto3" json:"skip_total,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -429,6 +524,13 @@ func (x *ListTasksRequest) GetOrderBy() string {
 	return ""
 }
 
+func (x *ListTasksRequest) GetSkipTotal() bool {
+	if x != nil {
+		return x.SkipTotal
+	}
+	return false
+}
+
 // ListTasksResponse message
 type ListTasksResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -436,7 +538,7 @@ type ListTasksResponse struct {
 	Tasks []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
 	// Token for next page
 	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
-	// Total number of tasks
+	// Total number of tasks, or -1 if it was skipped or could not be computed
 	TotalSize     int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -732,40 +834,1147 @@ func (x *BatchCreateTasksResponse) GetTasks() []*Task {
 	return nil
 }
 
-var File_api_proto_todo_v1_todo_proto protoreflect.FileDescriptor
+// UndoLastOperationRequest message
+type UndoLastOperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_api_proto_todo_v1_todo_proto_rawDesc = "" +
-	"\n" +
-	"\x1capi/proto/todo/v1/todo.proto\x12\atodo.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x19google/api/resource.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a google/protobuf/field_mask.proto\x1a\x1bbuf/validate/validate.proto\"\xac\x04\n" +
-	"\x04Task\x12\x1a\n" +
-	"\x04name\x18\x01 \x01(\tB\x06\xe0A\b\xe0A\x03R\x04name\x12#\n" +
-	"\x05title\x18\x02 \x01(\tB\r\xe0A\x02\xbaH\ar\x05\x10\x01\x18\xc8\x01R\x05title\x12,\n" +
-	"\vdescription\x18\x03 \x01(\tB\n" +
-	"\xe0A\x01\xbaH\x04r\x02\x18dR\vdescription\x12,\n" +
-	"\x06status\x18\x04 \x01(\x0e2\x0f.todo.v1.StatusB\x03\xe0A\x02R\x06status\x122\n" +
-	"\bpriority\x18\x05 \x01(\x0e2\x11.todo.v1.PriorityB\x03\xe0A\x01R\bpriority\x12B\n" +
-	"\bdue_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampB\v\xe0A\x01\xbaH\x05\xb2\x01\x02@\x01R\adueDate\x12@\n" +
-	"\vcreate_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
-	"createTime\x12@\n" +
-	"\vupdate_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
-	"updateTime\x12\"\n" +
-	"\n" +
-	"created_by\x18\t \x01(\tB\x03\xe0A\x03R\tcreatedBy\x120\n" +
-	"\x04tags\x18\n" +
-	" \x03(\tB\x1c\xbaH\x19\x92\x01\x16\x10\n" +
-	"\"\x12r\x10\x1822\f^[a-z0-9-]+$R\x04tags:5\xeaA2\n" +
-	"\x15todo.example.com/Task\x12\ftasks/{task}*\x05tasks2\x04task\"A\n" +
-	"\x11CreateTaskRequest\x12,\n" +
-	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskB\t\xe0A\x02\xbaH\x03\xc8\x01\x01R\x04task\"_\n" +
-	"\x0eGetTaskRequest\x12M\n" +
-	"\x04name\x18\x01 \x01(\tB9\xe0A\x02\xfaA\x17\n" +
-	"\x15todo.example.com/Task\xbaH\x19r\x172\x15^tasks/[a-zA-Z0-9-]+$R\x04name\"\x8c\x01\n" +
-	"\x10ListTasksRequest\x12&\n" +
-	"\tpage_size\x18\x01 \x01(\x05B\t\xbaH\x06\x1a\x04\x18d(\x00R\bpageSize\x12\x1d\n" +
-	"\n" +
-	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x16\n" +
-	"\x06filter\x18\x03 \x01(\tR\x06filter\x12\x19\n" +
-	"\border_by\x18\x04 \x01(\tR\aorderBy\"\x7f\n" +
+func (x *UndoLastOperationRequest) Reset() {
+	*x = UndoLastOperationRequest{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndoLastOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndoLastOperationRequest) ProtoMessage() {}
+
+func (x *UndoLastOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndoLastOperationRequest.ProtoReflect.Descriptor instead.
+func (*UndoLastOperationRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{10}
+}
+
+// UndoLastOperationResponse message
+type UndoLastOperationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Operation that was reversed (create, update or delete)
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Task as it is after the undo. For an undone create this is the removed task.
+	Task          *Task `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndoLastOperationResponse) Reset() {
+	*x = UndoLastOperationResponse{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndoLastOperationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndoLastOperationResponse) ProtoMessage() {}
+
+func (x *UndoLastOperationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndoLastOperationResponse.ProtoReflect.Descriptor instead.
+func (*UndoLastOperationResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UndoLastOperationResponse) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *UndoLastOperationResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// RenameTagRequest message
+type RenameTagRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tag to rename
+	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	// New tag name
+	To            string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameTagRequest) Reset() {
+	*x = RenameTagRequest{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameTagRequest) ProtoMessage() {}
+
+func (x *RenameTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameTagRequest.ProtoReflect.Descriptor instead.
+func (*RenameTagRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RenameTagRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *RenameTagRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+// RenameTagResponse message
+type RenameTagResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of tasks that had the tag renamed
+	AffectedCount int32 `protobuf:"varint,1,opt,name=affected_count,json=affectedCount,proto3" json:"affected_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameTagResponse) Reset() {
+	*x = RenameTagResponse{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameTagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameTagResponse) ProtoMessage() {}
+
+func (x *RenameTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameTagResponse.ProtoReflect.Descriptor instead.
+func (*RenameTagResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RenameTagResponse) GetAffectedCount() int32 {
+	if x != nil {
+		return x.AffectedCount
+	}
+	return 0
+}
+
+// SuggestTagsRequest message
+type SuggestTagsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Prefix the suggested tags must start with
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Maximum number of suggestions to return
+	PageSize      int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestTagsRequest) Reset() {
+	*x = SuggestTagsRequest{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestTagsRequest) ProtoMessage() {}
+
+func (x *SuggestTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestTagsRequest.ProtoReflect.Descriptor instead.
+func (*SuggestTagsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SuggestTagsRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SuggestTagsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// SuggestTagsResponse message
+type SuggestTagsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Matching tags, most used first
+	Suggestions   []*TagSuggestion `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestTagsResponse) Reset() {
+	*x = SuggestTagsResponse{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestTagsResponse) ProtoMessage() {}
+
+func (x *SuggestTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestTagsResponse.ProtoReflect.Descriptor instead.
+func (*SuggestTagsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SuggestTagsResponse) GetSuggestions() []*TagSuggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+// TagSuggestion is a tag and how many of the caller's tasks use it
+type TagSuggestion struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tag name
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Number of tasks using the tag
+	Count         int32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagSuggestion) Reset() {
+	*x = TagSuggestion{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagSuggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagSuggestion) ProtoMessage() {}
+
+func (x *TagSuggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagSuggestion.ProtoReflect.Descriptor instead.
+func (*TagSuggestion) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TagSuggestion) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *TagSuggestion) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// GetTaskStatsRequest message
+type GetTaskStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskStatsRequest) Reset() {
+	*x = GetTaskStatsRequest{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskStatsRequest) ProtoMessage() {}
+
+func (x *GetTaskStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskStatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{17}
+}
+
+// TaskStats summarizes the tasks visible to the caller
+type TaskStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Total number of tasks
+	TotalCount int32 `protobuf:"varint,1,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	// Task counts per status
+	ByStatus []*StatusCount `protobuf:"bytes,2,rep,name=by_status,json=byStatus,proto3" json:"by_status,omitempty"`
+	// Task counts per priority
+	ByPriority []*PriorityCount `protobuf:"bytes,3,rep,name=by_priority,json=byPriority,proto3" json:"by_priority,omitempty"`
+	// Number of unfinished tasks past their due date
+	OverdueCount int32 `protobuf:"varint,4,opt,name=overdue_count,json=overdueCount,proto3" json:"overdue_count,omitempty"`
+	// Earliest upcoming due date among unfinished tasks
+	NextDueDate   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=next_due_date,json=nextDueDate,proto3" json:"next_due_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskStats) Reset() {
+	*x = TaskStats{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStats) ProtoMessage() {}
+
+func (x *TaskStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStats.ProtoReflect.Descriptor instead.
+func (*TaskStats) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TaskStats) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *TaskStats) GetByStatus() []*StatusCount {
+	if x != nil {
+		return x.ByStatus
+	}
+	return nil
+}
+
+func (x *TaskStats) GetByPriority() []*PriorityCount {
+	if x != nil {
+		return x.ByPriority
+	}
+	return nil
+}
+
+func (x *TaskStats) GetOverdueCount() int32 {
+	if x != nil {
+		return x.OverdueCount
+	}
+	return 0
+}
+
+func (x *TaskStats) GetNextDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextDueDate
+	}
+	return nil
+}
+
+// StatusCount is the number of tasks with a status
+type StatusCount struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Task status
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=todo.v1.Status" json:"status,omitempty"`
+	// Number of tasks
+	Count         int32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusCount) Reset() {
+	*x = StatusCount{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusCount) ProtoMessage() {}
+
+func (x *StatusCount) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusCount.ProtoReflect.Descriptor instead.
+func (*StatusCount) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StatusCount) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *StatusCount) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// PriorityCount is the number of tasks with a priority
+type PriorityCount struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Task priority
+	Priority Priority `protobuf:"varint,1,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	// Number of tasks
+	Count         int32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriorityCount) Reset() {
+	*x = PriorityCount{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriorityCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriorityCount) ProtoMessage() {}
+
+func (x *PriorityCount) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriorityCount.ProtoReflect.Descriptor instead.
+func (*PriorityCount) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PriorityCount) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *PriorityCount) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// BatchGetTasksRequest message
+type BatchGetTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Task names to fetch, e.g. tasks/123
+	Names         []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetTasksRequest) Reset() {
+	*x = BatchGetTasksRequest{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetTasksRequest) ProtoMessage() {}
+
+func (x *BatchGetTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetTasksRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetTasksRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BatchGetTasksRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+// BatchGetTasksResponse reports every requested name in request order
+type BatchGetTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per requested name
+	Results       []*BatchGetResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetTasksResponse) Reset() {
+	*x = BatchGetTasksResponse{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetTasksResponse) ProtoMessage() {}
+
+func (x *BatchGetTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetTasksResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetTasksResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BatchGetTasksResponse) GetResults() []*BatchGetResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// BatchGetResult is the outcome for one requested name
+type BatchGetResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Requested task name
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Whether the task was found and readable by the caller
+	Status BatchGetStatus `protobuf:"varint,2,opt,name=status,proto3,enum=todo.v1.BatchGetStatus" json:"status,omitempty"`
+	// The task, set only when status is FOUND
+	Task          *Task `protobuf:"bytes,3,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetResult) Reset() {
+	*x = BatchGetResult{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetResult) ProtoMessage() {}
+
+func (x *BatchGetResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetResult.ProtoReflect.Descriptor instead.
+func (*BatchGetResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BatchGetResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BatchGetResult) GetStatus() BatchGetStatus {
+	if x != nil {
+		return x.Status
+	}
+	return BatchGetStatus_BATCH_GET_STATUS_UNSPECIFIED
+}
+
+func (x *BatchGetResult) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// ReassignUserTasksRequest message
+type ReassignUserTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// User whose tasks are moved, e.g. one being offboarded
+	FromUser string `protobuf:"bytes,1,opt,name=from_user,json=fromUser,proto3" json:"from_user,omitempty"`
+	// User who becomes the owner of the tasks
+	ToUser        string `protobuf:"bytes,2,opt,name=to_user,json=toUser,proto3" json:"to_user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReassignUserTasksRequest) Reset() {
+	*x = ReassignUserTasksRequest{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignUserTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignUserTasksRequest) ProtoMessage() {}
+
+func (x *ReassignUserTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignUserTasksRequest.ProtoReflect.Descriptor instead.
+func (*ReassignUserTasksRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ReassignUserTasksRequest) GetFromUser() string {
+	if x != nil {
+		return x.FromUser
+	}
+	return ""
+}
+
+func (x *ReassignUserTasksRequest) GetToUser() string {
+	if x != nil {
+		return x.ToUser
+	}
+	return ""
+}
+
+// ReassignUserTasksResponse message
+type ReassignUserTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of tasks that changed owner
+	MovedCount    int32 `protobuf:"varint,1,opt,name=moved_count,json=movedCount,proto3" json:"moved_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReassignUserTasksResponse) Reset() {
+	*x = ReassignUserTasksResponse{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignUserTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignUserTasksResponse) ProtoMessage() {}
+
+func (x *ReassignUserTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignUserTasksResponse.ProtoReflect.Descriptor instead.
+func (*ReassignUserTasksResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ReassignUserTasksResponse) GetMovedCount() int32 {
+	if x != nil {
+		return x.MovedCount
+	}
+	return 0
+}
+
+// TaskTemplate holds the defaults for a task that is created repeatedly
+type TaskTemplate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resource name, assigned on creation, e.g. taskTemplates/{id}
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Task title; {date} expands to the creation date
+	TitlePattern string `protobuf:"bytes,2,opt,name=title_pattern,json=titlePattern,proto3" json:"title_pattern,omitempty"`
+	// Description of tasks created from the template
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Priority of tasks created from the template
+	Priority Priority `protobuf:"varint,4,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	// Tags of tasks created from the template
+	Tags          []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskTemplate) Reset() {
+	*x = TaskTemplate{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskTemplate) ProtoMessage() {}
+
+func (x *TaskTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskTemplate.ProtoReflect.Descriptor instead.
+func (*TaskTemplate) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TaskTemplate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TaskTemplate) GetTitlePattern() string {
+	if x != nil {
+		return x.TitlePattern
+	}
+	return ""
+}
+
+func (x *TaskTemplate) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TaskTemplate) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *TaskTemplate) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// CreateTaskTemplateRequest message
+type CreateTaskTemplateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Template to store; its name is assigned by the server
+	Template      *TaskTemplate `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskTemplateRequest) Reset() {
+	*x = CreateTaskTemplateRequest{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskTemplateRequest) ProtoMessage() {}
+
+func (x *CreateTaskTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskTemplateRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateTaskTemplateRequest) GetTemplate() *TaskTemplate {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+// ListTaskTemplatesRequest message
+type ListTaskTemplatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaskTemplatesRequest) Reset() {
+	*x = ListTaskTemplatesRequest{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaskTemplatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaskTemplatesRequest) ProtoMessage() {}
+
+func (x *ListTaskTemplatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaskTemplatesRequest.ProtoReflect.Descriptor instead.
+func (*ListTaskTemplatesRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{28}
+}
+
+// ListTaskTemplatesResponse message
+type ListTaskTemplatesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Templates of the caller's tenant, sorted by name
+	Templates     []*TaskTemplate `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaskTemplatesResponse) Reset() {
+	*x = ListTaskTemplatesResponse{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaskTemplatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaskTemplatesResponse) ProtoMessage() {}
+
+func (x *ListTaskTemplatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaskTemplatesResponse.ProtoReflect.Descriptor instead.
+func (*ListTaskTemplatesResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListTaskTemplatesResponse) GetTemplates() []*TaskTemplate {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+// CreateTaskFromTemplateRequest message
+type CreateTaskFromTemplateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resource name of the template, e.g. taskTemplates/{id}
+	Template string `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	// Non-empty fields replace the template's values
+	Overrides     *Task `protobuf:"bytes,2,opt,name=overrides,proto3" json:"overrides,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskFromTemplateRequest) Reset() {
+	*x = CreateTaskFromTemplateRequest{}
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskFromTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskFromTemplateRequest) ProtoMessage() {}
+
+func (x *CreateTaskFromTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_todo_v1_todo_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskFromTemplateRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskFromTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_todo_v1_todo_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CreateTaskFromTemplateRequest) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+func (x *CreateTaskFromTemplateRequest) GetOverrides() *Task {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+var File_api_proto_todo_v1_todo_proto protoreflect.FileDescriptor
+
+const file_api_proto_todo_v1_todo_proto_rawDesc = "" +
+	"\n" +
+	"\x1capi/proto/todo/v1/todo.proto\x12\atodo.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x19google/api/resource.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a google/protobuf/field_mask.proto\x1a\x1bbuf/validate/validate.proto\"\xeb\x04\n" +
+	"\x04Task\x12\x1a\n" +
+	"\x04name\x18\x01 \x01(\tB\x06\xe0A\b\xe0A\x03R\x04name\x12#\n" +
+	"\x05title\x18\x02 \x01(\tB\r\xe0A\x02\xbaH\ar\x05\x10\x01\x18\xc8\x01R\x05title\x12,\n" +
+	"\vdescription\x18\x03 \x01(\tB\n" +
+	"\xe0A\x01\xbaH\x04r\x02\x18dR\vdescription\x12,\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x0f.todo.v1.StatusB\x03\xe0A\x02R\x06status\x122\n" +
+	"\bpriority\x18\x05 \x01(\x0e2\x11.todo.v1.PriorityB\x03\xe0A\x01R\bpriority\x12B\n" +
+	"\bdue_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampB\v\xe0A\x01\xbaH\x05\xb2\x01\x02@\x01R\adueDate\x12@\n" +
+	"\vcreate_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"createTime\x12@\n" +
+	"\vupdate_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampB\x03\xe0A\x03R\n" +
+	"updateTime\x12\"\n" +
+	"\n" +
+	"created_by\x18\t \x01(\tB\x03\xe0A\x03R\tcreatedBy\x120\n" +
+	"\x04tags\x18\n" +
+	" \x03(\tB\x1c\xbaH\x19\x92\x01\x16\x10\n" +
+	"\"\x12r\x10\x1822\f^[a-z0-9-]+$R\x04tags\x12 \n" +
+	"\ttenant_id\x18\v \x01(\tB\x03\xe0A\x03R\btenantId\x12\x1b\n" +
+	"\ttime_zone\x18\f \x01(\tR\btimeZone:5\xeaA2\n" +
+	"\x15todo.example.com/Task\x12\ftasks/{task}*\x05tasks2\x04task\"\x86\x01\n" +
+	"\x11CreateTaskRequest\x12,\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskB\t\xe0A\x02\xbaH\x03\xc8\x01\x01R\x04task\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12*\n" +
+	"\x11relative_due_date\x18\x03 \x01(\tR\x0frelativeDueDate\"_\n" +
+	"\x0eGetTaskRequest\x12M\n" +
+	"\x04name\x18\x01 \x01(\tB9\xe0A\x02\xfaA\x17\n" +
+	"\x15todo.example.com/Task\xbaH\x19r\x172\x15^tasks/[a-zA-Z0-9-]+$R\x04name\"\xab\x01\n" +
+	"\x10ListTasksRequest\x12&\n" +
+	"\tpage_size\x18\x01 \x01(\x05B\t\xbaH\x06\x1a\x04\x18d(\x00R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06filter\x18\x03 \x01(\tR\x06filter\x12\x19\n" +
+	"\border_by\x18\x04 \x01(\tR\aorderBy\x12\x1d\n" +
+	"\n" +
+	"skip_total\x18\x05 \x01(\bR\tskipTotal\"\x7f\n" +
 	"\x11ListTasksResponse\x12#\n" +
 	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\x12&\n" +
 	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
@@ -783,7 +1992,67 @@ const file_api_proto_todo_v1_todo_proto_rawDesc = "" +
 	"\x17BatchCreateTasksRequest\x12E\n" +
 	"\brequests\x18\x01 \x03(\v2\x1a.todo.v1.CreateTaskRequestB\r\xe0A\x02\xbaH\a\x92\x01\x04\b\x01\x10dR\brequests\"?\n" +
 	"\x18BatchCreateTasksResponse\x12#\n" +
-	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks*x\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"\x1a\n" +
+	"\x18UndoLastOperationRequest\"\\\n" +
+	"\x19UndoLastOperationResponse\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12!\n" +
+	"\x04task\x18\x02 \x01(\v2\r.todo.v1.TaskR\x04task\"6\n" +
+	"\x10RenameTagRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\":\n" +
+	"\x11RenameTagResponse\x12%\n" +
+	"\x0eaffected_count\x18\x01 \x01(\x05R\raffectedCount\"I\n" +
+	"\x12SuggestTagsRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"O\n" +
+	"\x13SuggestTagsResponse\x128\n" +
+	"\vsuggestions\x18\x01 \x03(\v2\x16.todo.v1.TagSuggestionR\vsuggestions\"7\n" +
+	"\rTagSuggestion\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"\x15\n" +
+	"\x13GetTaskStatsRequest\"\xfd\x01\n" +
+	"\tTaskStats\x12\x1f\n" +
+	"\vtotal_count\x18\x01 \x01(\x05R\n" +
+	"totalCount\x121\n" +
+	"\tby_status\x18\x02 \x03(\v2\x14.todo.v1.StatusCountR\bbyStatus\x127\n" +
+	"\vby_priority\x18\x03 \x03(\v2\x16.todo.v1.PriorityCountR\n" +
+	"byPriority\x12#\n" +
+	"\roverdue_count\x18\x04 \x01(\x05R\foverdueCount\x12>\n" +
+	"\rnext_due_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vnextDueDate\"L\n" +
+	"\vStatusCount\x12'\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x0f.todo.v1.StatusR\x06status\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"T\n" +
+	"\rPriorityCount\x12-\n" +
+	"\bpriority\x18\x01 \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\",\n" +
+	"\x14BatchGetTasksRequest\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\"J\n" +
+	"\x15BatchGetTasksResponse\x121\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.todo.v1.BatchGetResultR\aresults\"x\n" +
+	"\x0eBatchGetResult\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12/\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x17.todo.v1.BatchGetStatusR\x06status\x12!\n" +
+	"\x04task\x18\x03 \x01(\v2\r.todo.v1.TaskR\x04task\"P\n" +
+	"\x18ReassignUserTasksRequest\x12\x1b\n" +
+	"\tfrom_user\x18\x01 \x01(\tR\bfromUser\x12\x17\n" +
+	"\ato_user\x18\x02 \x01(\tR\x06toUser\"<\n" +
+	"\x19ReassignUserTasksResponse\x12\x1f\n" +
+	"\vmoved_count\x18\x01 \x01(\x05R\n" +
+	"movedCount\"\xb1\x01\n" +
+	"\fTaskTemplate\x12\x17\n" +
+	"\x04name\x18\x01 \x01(\tB\x03\xe0A\x03R\x04name\x12#\n" +
+	"\rtitle_pattern\x18\x02 \x01(\tR\ftitlePattern\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12-\n" +
+	"\bpriority\x18\x04 \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\"N\n" +
+	"\x19CreateTaskTemplateRequest\x121\n" +
+	"\btemplate\x18\x01 \x01(\v2\x15.todo.v1.TaskTemplateR\btemplate\"\x1a\n" +
+	"\x18ListTaskTemplatesRequest\"P\n" +
+	"\x19ListTaskTemplatesResponse\x123\n" +
+	"\ttemplates\x18\x01 \x03(\v2\x15.todo.v1.TaskTemplateR\ttemplates\"h\n" +
+	"\x1dCreateTaskFromTemplateRequest\x12\x1a\n" +
+	"\btemplate\x18\x01 \x01(\tR\btemplate\x12+\n" +
+	"\toverrides\x18\x02 \x01(\v2\r.todo.v1.TaskR\toverrides*x\n" +
 	"\x06Status\x12\x16\n" +
 	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
 	"\x0eSTATUS_PENDING\x10\x01\x12\x16\n" +
@@ -795,7 +2064,12 @@ const file_api_proto_todo_v1_todo_proto_rawDesc = "" +
 	"\fPRIORITY_LOW\x10\x01\x12\x13\n" +
 	"\x0fPRIORITY_MEDIUM\x10\x02\x12\x11\n" +
 	"\rPRIORITY_HIGH\x10\x03\x12\x15\n" +
-	"\x11PRIORITY_CRITICAL\x10\x042\xc0\x04\n" +
+	"\x11PRIORITY_CRITICAL\x10\x04*\x8e\x01\n" +
+	"\x0eBatchGetStatus\x12 \n" +
+	"\x1cBATCH_GET_STATUS_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16BATCH_GET_STATUS_FOUND\x10\x01\x12\x1e\n" +
+	"\x1aBATCH_GET_STATUS_NOT_FOUND\x10\x02\x12\x1e\n" +
+	"\x1aBATCH_GET_STATUS_FORBIDDEN\x10\x032\xa7\f\n" +
 	"\vTodoService\x12M\n" +
 	"\n" +
 	"CreateTask\x12\x1a.todo.v1.CreateTaskRequest\x1a\r.todo.v1.Task\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/tasks\x12M\n" +
@@ -805,7 +2079,16 @@ const file_api_proto_todo_v1_todo_proto_rawDesc = "" +
 	"UpdateTask\x12\x1a.todo.v1.UpdateTaskRequest\x1a\r.todo.v1.Task\"%\x82\xd3\xe4\x93\x02\x1f:\x04task2\x17/v1/{task.name=tasks/*}\x12a\n" +
 	"\n" +
 	"DeleteTask\x12\x1a.todo.v1.DeleteTaskRequest\x1a\x1b.todo.v1.DeleteTaskResponse\"\x1a\x82\xd3\xe4\x93\x02\x14*\x12/v1/{name=tasks/*}\x12y\n" +
-	"\x10BatchCreateTasks\x12 .todo.v1.BatchCreateTasksRequest\x1a!.todo.v1.BatchCreateTasksResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/tasks:batchCreateB\x95\x01\n" +
+	"\x10BatchCreateTasks\x12 .todo.v1.BatchCreateTasksRequest\x1a!.todo.v1.BatchCreateTasksResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/tasks:batchCreate\x12u\n" +
+	"\x11UndoLastOperation\x12!.todo.v1.UndoLastOperationRequest\x1a\".todo.v1.UndoLastOperationResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/tasks:undo\x12b\n" +
+	"\tRenameTag\x12\x19.todo.v1.RenameTagRequest\x1a\x1a.todo.v1.RenameTagResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/tasks:renameTag\x12g\n" +
+	"\vSuggestTags\x12\x1b.todo.v1.SuggestTagsRequest\x1a\x1c.todo.v1.SuggestTagsResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/tasks:suggestTags\x12Y\n" +
+	"\fGetTaskStats\x12\x1c.todo.v1.GetTaskStatsRequest\x1a\x12.todo.v1.TaskStats\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/tasks:stats\x12j\n" +
+	"\rBatchGetTasks\x12\x1d.todo.v1.BatchGetTasksRequest\x1a\x1e.todo.v1.BatchGetTasksResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/tasks:batchGet\x12y\n" +
+	"\x11ReassignUserTasks\x12!.todo.v1.ReassignUserTasksRequest\x1a\".todo.v1.ReassignUserTasksResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/tasks:reassign\x12t\n" +
+	"\x12CreateTaskTemplate\x12\".todo.v1.CreateTaskTemplateRequest\x1a\x15.todo.v1.TaskTemplate\"#\x82\xd3\xe4\x93\x02\x1d:\btemplate\"\x11/v1/taskTemplates\x12u\n" +
+	"\x11ListTaskTemplates\x12!.todo.v1.ListTaskTemplatesRequest\x1a\".todo.v1.ListTaskTemplatesResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/taskTemplates\x12r\n" +
+	"\x16CreateTaskFromTemplate\x12&.todo.v1.CreateTaskFromTemplateRequest\x1a\r.todo.v1.Task\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/tasks:fromTemplateB\x95\x01\n" +
 	"\vcom.todo.v1B\tTodoProtoP\x01Z>github.com/bhatti/todo-api-errors/gen/api/proto/todo/v1;todov1\xa2\x02\x03TXX\xaa\x02\aTodo.V1\xca\x02\aTodo\\V1\xe2\x02\x13Todo\\V1\\GPBMetadata\xea\x02\bTodo::V1b\x06proto3"
 
 var (
@@ -820,53 +2103,107 @@ func file_api_proto_todo_v1_todo_proto_rawDescGZIP() []byte {
 	return file_api_proto_todo_v1_todo_proto_rawDescData
 }
 
-var file_api_proto_todo_v1_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_api_proto_todo_v1_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_proto_todo_v1_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_api_proto_todo_v1_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_api_proto_todo_v1_todo_proto_goTypes = []any{
-	(Status)(0),                      // 0: todo.v1.Status
-	(Priority)(0),                    // 1: todo.v1.Priority
-	(*Task)(nil),                     // 2: todo.v1.Task
-	(*CreateTaskRequest)(nil),        // 3: todo.v1.CreateTaskRequest
-	(*GetTaskRequest)(nil),           // 4: todo.v1.GetTaskRequest
-	(*ListTasksRequest)(nil),         // 5: todo.v1.ListTasksRequest
-	(*ListTasksResponse)(nil),        // 6: todo.v1.ListTasksResponse
-	(*UpdateTaskRequest)(nil),        // 7: todo.v1.UpdateTaskRequest
-	(*DeleteTaskRequest)(nil),        // 8: todo.v1.DeleteTaskRequest
-	(*DeleteTaskResponse)(nil),       // 9: todo.v1.DeleteTaskResponse
-	(*BatchCreateTasksRequest)(nil),  // 10: todo.v1.BatchCreateTasksRequest
-	(*BatchCreateTasksResponse)(nil), // 11: todo.v1.BatchCreateTasksResponse
-	(*timestamppb.Timestamp)(nil),    // 12: google.protobuf.Timestamp
-	(*fieldmaskpb.FieldMask)(nil),    // 13: google.protobuf.FieldMask
+	(Status)(0),                           // 0: todo.v1.Status
+	(Priority)(0),                         // 1: todo.v1.Priority
+	(BatchGetStatus)(0),                   // 2: todo.v1.BatchGetStatus
+	(*Task)(nil),                          // 3: todo.v1.Task
+	(*CreateTaskRequest)(nil),             // 4: todo.v1.CreateTaskRequest
+	(*GetTaskRequest)(nil),                // 5: todo.v1.GetTaskRequest
+	(*ListTasksRequest)(nil),              // 6: todo.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),             // 7: todo.v1.ListTasksResponse
+	(*UpdateTaskRequest)(nil),             // 8: todo.v1.UpdateTaskRequest
+	(*DeleteTaskRequest)(nil),             // 9: todo.v1.DeleteTaskRequest
+	(*DeleteTaskResponse)(nil),            // 10: todo.v1.DeleteTaskResponse
+	(*BatchCreateTasksRequest)(nil),       // 11: todo.v1.BatchCreateTasksRequest
+	(*BatchCreateTasksResponse)(nil),      // 12: todo.v1.BatchCreateTasksResponse
+	(*UndoLastOperationRequest)(nil),      // 13: todo.v1.UndoLastOperationRequest
+	(*UndoLastOperationResponse)(nil),     // 14: todo.v1.UndoLastOperationResponse
+	(*RenameTagRequest)(nil),              // 15: todo.v1.RenameTagRequest
+	(*RenameTagResponse)(nil),             // 16: todo.v1.RenameTagResponse
+	(*SuggestTagsRequest)(nil),            // 17: todo.v1.SuggestTagsRequest
+	(*SuggestTagsResponse)(nil),           // 18: todo.v1.SuggestTagsResponse
+	(*TagSuggestion)(nil),                 // 19: todo.v1.TagSuggestion
+	(*GetTaskStatsRequest)(nil),           // 20: todo.v1.GetTaskStatsRequest
+	(*TaskStats)(nil),                     // 21: todo.v1.TaskStats
+	(*StatusCount)(nil),                   // 22: todo.v1.StatusCount
+	(*PriorityCount)(nil),                 // 23: todo.v1.PriorityCount
+	(*BatchGetTasksRequest)(nil),          // 24: todo.v1.BatchGetTasksRequest
+	(*BatchGetTasksResponse)(nil),         // 25: todo.v1.BatchGetTasksResponse
+	(*BatchGetResult)(nil),                // 26: todo.v1.BatchGetResult
+	(*ReassignUserTasksRequest)(nil),      // 27: todo.v1.ReassignUserTasksRequest
+	(*ReassignUserTasksResponse)(nil),     // 28: todo.v1.ReassignUserTasksResponse
+	(*TaskTemplate)(nil),                  // 29: todo.v1.TaskTemplate
+	(*CreateTaskTemplateRequest)(nil),     // 30: todo.v1.CreateTaskTemplateRequest
+	(*ListTaskTemplatesRequest)(nil),      // 31: todo.v1.ListTaskTemplatesRequest
+	(*ListTaskTemplatesResponse)(nil),     // 32: todo.v1.ListTaskTemplatesResponse
+	(*CreateTaskFromTemplateRequest)(nil), // 33: todo.v1.CreateTaskFromTemplateRequest
+	(*timestamppb.Timestamp)(nil),         // 34: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),         // 35: google.protobuf.FieldMask
 }
 var file_api_proto_todo_v1_todo_proto_depIdxs = []int32{
 	0,  // 0: todo.v1.Task.status:type_name -> todo.v1.Status
 	1,  // 1: todo.v1.Task.priority:type_name -> todo.v1.Priority
-	12, // 2: todo.v1.Task.due_date:type_name -> google.protobuf.Timestamp
-	12, // 3: todo.v1.Task.create_time:type_name -> google.protobuf.Timestamp
-	12, // 4: todo.v1.Task.update_time:type_name -> google.protobuf.Timestamp
-	2,  // 5: todo.v1.CreateTaskRequest.task:type_name -> todo.v1.Task
-	2,  // 6: todo.v1.ListTasksResponse.tasks:type_name -> todo.v1.Task
-	2,  // 7: todo.v1.UpdateTaskRequest.task:type_name -> todo.v1.Task
-	13, // 8: todo.v1.UpdateTaskRequest.update_mask:type_name -> google.protobuf.FieldMask
-	3,  // 9: todo.v1.BatchCreateTasksRequest.requests:type_name -> todo.v1.CreateTaskRequest
-	2,  // 10: todo.v1.BatchCreateTasksResponse.tasks:type_name -> todo.v1.Task
-	3,  // 11: todo.v1.TodoService.CreateTask:input_type -> todo.v1.CreateTaskRequest
-	4,  // 12: todo.v1.TodoService.GetTask:input_type -> todo.v1.GetTaskRequest
-	5,  // 13: todo.v1.TodoService.ListTasks:input_type -> todo.v1.ListTasksRequest
-	7,  // 14: todo.v1.TodoService.UpdateTask:input_type -> todo.v1.UpdateTaskRequest
-	8,  // 15: todo.v1.TodoService.DeleteTask:input_type -> todo.v1.DeleteTaskRequest
-	10, // 16: todo.v1.TodoService.BatchCreateTasks:input_type -> todo.v1.BatchCreateTasksRequest
-	2,  // 17: todo.v1.TodoService.CreateTask:output_type -> todo.v1.Task
-	2,  // 18: todo.v1.TodoService.GetTask:output_type -> todo.v1.Task
-	6,  // 19: todo.v1.TodoService.ListTasks:output_type -> todo.v1.ListTasksResponse
-	2,  // 20: todo.v1.TodoService.UpdateTask:output_type -> todo.v1.Task
-	9,  // 21: todo.v1.TodoService.DeleteTask:output_type -> todo.v1.DeleteTaskResponse
-	11, // 22: todo.v1.TodoService.BatchCreateTasks:output_type -> todo.v1.BatchCreateTasksResponse
-	17, // [17:23] is the sub-list for method output_type
-	11, // [11:17] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	34, // 2: todo.v1.Task.due_date:type_name -> google.protobuf.Timestamp
+	34, // 3: todo.v1.Task.create_time:type_name -> google.protobuf.Timestamp
+	34, // 4: todo.v1.Task.update_time:type_name -> google.protobuf.Timestamp
+	3,  // 5: todo.v1.CreateTaskRequest.task:type_name -> todo.v1.Task
+	3,  // 6: todo.v1.ListTasksResponse.tasks:type_name -> todo.v1.Task
+	3,  // 7: todo.v1.UpdateTaskRequest.task:type_name -> todo.v1.Task
+	35, // 8: todo.v1.UpdateTaskRequest.update_mask:type_name -> google.protobuf.FieldMask
+	4,  // 9: todo.v1.BatchCreateTasksRequest.requests:type_name -> todo.v1.CreateTaskRequest
+	3,  // 10: todo.v1.BatchCreateTasksResponse.tasks:type_name -> todo.v1.Task
+	3,  // 11: todo.v1.UndoLastOperationResponse.task:type_name -> todo.v1.Task
+	19, // 12: todo.v1.SuggestTagsResponse.suggestions:type_name -> todo.v1.TagSuggestion
+	22, // 13: todo.v1.TaskStats.by_status:type_name -> todo.v1.StatusCount
+	23, // 14: todo.v1.TaskStats.by_priority:type_name -> todo.v1.PriorityCount
+	34, // 15: todo.v1.TaskStats.next_due_date:type_name -> google.protobuf.Timestamp
+	0,  // 16: todo.v1.StatusCount.status:type_name -> todo.v1.Status
+	1,  // 17: todo.v1.PriorityCount.priority:type_name -> todo.v1.Priority
+	26, // 18: todo.v1.BatchGetTasksResponse.results:type_name -> todo.v1.BatchGetResult
+	2,  // 19: todo.v1.BatchGetResult.status:type_name -> todo.v1.BatchGetStatus
+	3,  // 20: todo.v1.BatchGetResult.task:type_name -> todo.v1.Task
+	1,  // 21: todo.v1.TaskTemplate.priority:type_name -> todo.v1.Priority
+	29, // 22: todo.v1.CreateTaskTemplateRequest.template:type_name -> todo.v1.TaskTemplate
+	29, // 23: todo.v1.ListTaskTemplatesResponse.templates:type_name -> todo.v1.TaskTemplate
+	3,  // 24: todo.v1.CreateTaskFromTemplateRequest.overrides:type_name -> todo.v1.Task
+	4,  // 25: todo.v1.TodoService.CreateTask:input_type -> todo.v1.CreateTaskRequest
+	5,  // 26: todo.v1.TodoService.GetTask:input_type -> todo.v1.GetTaskRequest
+	6,  // 27: todo.v1.TodoService.ListTasks:input_type -> todo.v1.ListTasksRequest
+	8,  // 28: todo.v1.TodoService.UpdateTask:input_type -> todo.v1.UpdateTaskRequest
+	9,  // 29: todo.v1.TodoService.DeleteTask:input_type -> todo.v1.DeleteTaskRequest
+	11, // 30: todo.v1.TodoService.BatchCreateTasks:input_type -> todo.v1.BatchCreateTasksRequest
+	13, // 31: todo.v1.TodoService.UndoLastOperation:input_type -> todo.v1.UndoLastOperationRequest
+	15, // 32: todo.v1.TodoService.RenameTag:input_type -> todo.v1.RenameTagRequest
+	17, // 33: todo.v1.TodoService.SuggestTags:input_type -> todo.v1.SuggestTagsRequest
+	20, // 34: todo.v1.TodoService.GetTaskStats:input_type -> todo.v1.GetTaskStatsRequest
+	24, // 35: todo.v1.TodoService.BatchGetTasks:input_type -> todo.v1.BatchGetTasksRequest
+	27, // 36: todo.v1.TodoService.ReassignUserTasks:input_type -> todo.v1.ReassignUserTasksRequest
+	30, // 37: todo.v1.TodoService.CreateTaskTemplate:input_type -> todo.v1.CreateTaskTemplateRequest
+	31, // 38: todo.v1.TodoService.ListTaskTemplates:input_type -> todo.v1.ListTaskTemplatesRequest
+	33, // 39: todo.v1.TodoService.CreateTaskFromTemplate:input_type -> todo.v1.CreateTaskFromTemplateRequest
+	3,  // 40: todo.v1.TodoService.CreateTask:output_type -> todo.v1.Task
+	3,  // 41: todo.v1.TodoService.GetTask:output_type -> todo.v1.Task
+	7,  // 42: todo.v1.TodoService.ListTasks:output_type -> todo.v1.ListTasksResponse
+	3,  // 43: todo.v1.TodoService.UpdateTask:output_type -> todo.v1.Task
+	10, // 44: todo.v1.TodoService.DeleteTask:output_type -> todo.v1.DeleteTaskResponse
+	12, // 45: todo.v1.TodoService.BatchCreateTasks:output_type -> todo.v1.BatchCreateTasksResponse
+	14, // 46: todo.v1.TodoService.UndoLastOperation:output_type -> todo.v1.UndoLastOperationResponse
+	16, // 47: todo.v1.TodoService.RenameTag:output_type -> todo.v1.RenameTagResponse
+	18, // 48: todo.v1.TodoService.SuggestTags:output_type -> todo.v1.SuggestTagsResponse
+	21, // 49: todo.v1.TodoService.GetTaskStats:output_type -> todo.v1.TaskStats
+	25, // 50: todo.v1.TodoService.BatchGetTasks:output_type -> todo.v1.BatchGetTasksResponse
+	28, // 51: todo.v1.TodoService.ReassignUserTasks:output_type -> todo.v1.ReassignUserTasksResponse
+	29, // 52: todo.v1.TodoService.CreateTaskTemplate:output_type -> todo.v1.TaskTemplate
+	32, // 53: todo.v1.TodoService.ListTaskTemplates:output_type -> todo.v1.ListTaskTemplatesResponse
+	3,  // 54: todo.v1.TodoService.CreateTaskFromTemplate:output_type -> todo.v1.Task
+	40, // [40:55] is the sub-list for method output_type
+	25, // [25:40] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_todo_v1_todo_proto_init() }
@@ -879,8 +2216,8 @@ func file_api_proto_todo_v1_todo_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_todo_v1_todo_proto_rawDesc), len(file_api_proto_todo_v1_todo_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   10,
+			NumEnums:      3,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,12 +19,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TodoService_CreateTask_FullMethodName       = "/todo.v1.TodoService/CreateTask"
-	TodoService_GetTask_FullMethodName          = "/todo.v1.TodoService/GetTask"
-	TodoService_ListTasks_FullMethodName        = "/todo.v1.TodoService/ListTasks"
-	TodoService_UpdateTask_FullMethodName       = "/todo.v1.TodoService/UpdateTask"
-	TodoService_DeleteTask_FullMethodName       = "/todo.v1.TodoService/DeleteTask"
-	TodoService_BatchCreateTasks_FullMethodName = "/todo.v1.TodoService/BatchCreateTasks"
+	TodoService_CreateTask_FullMethodName             = "/todo.v1.TodoService/CreateTask"
+	TodoService_GetTask_FullMethodName                = "/todo.v1.TodoService/GetTask"
+	TodoService_ListTasks_FullMethodName              = "/todo.v1.TodoService/ListTasks"
+	TodoService_UpdateTask_FullMethodName             = "/todo.v1.TodoService/UpdateTask"
+	TodoService_DeleteTask_FullMethodName             = "/todo.v1.TodoService/DeleteTask"
+	TodoService_BatchCreateTasks_FullMethodName       = "/todo.v1.TodoService/BatchCreateTasks"
+	TodoService_UndoLastOperation_FullMethodName      = "/todo.v1.TodoService/UndoLastOperation"
+	TodoService_RenameTag_FullMethodName              = "/todo.v1.TodoService/RenameTag"
+	TodoService_SuggestTags_FullMethodName            = "/todo.v1.TodoService/SuggestTags"
+	TodoService_GetTaskStats_FullMethodName           = "/todo.v1.TodoService/GetTaskStats"
+	TodoService_BatchGetTasks_FullMethodName          = "/todo.v1.TodoService/BatchGetTasks"
+	TodoService_ReassignUserTasks_FullMethodName      = "/todo.v1.TodoService/ReassignUserTasks"
+	TodoService_CreateTaskTemplate_FullMethodName     = "/todo.v1.TodoService/CreateTaskTemplate"
+	TodoService_ListTaskTemplates_FullMethodName      = "/todo.v1.TodoService/ListTaskTemplates"
+	TodoService_CreateTaskFromTemplate_FullMethodName = "/todo.v1.TodoService/CreateTaskFromTemplate"
 )
 
 // TodoServiceClient is the client API for TodoService service.
@@ -45,6 +54,25 @@ type TodoServiceClient interface {
 	DeleteTask(ctx context.Context, in *DeleteTaskRequest, opts ...grpc.CallOption) (*DeleteTaskResponse, error)
 	// BatchCreateTasks creates multiple tasks at once
 	BatchCreateTasks(ctx context.Context, in *BatchCreateTasksRequest, opts ...grpc.CallOption) (*BatchCreateTasksResponse, error)
+	// UndoLastOperation reverses the caller's most recent create, update or delete
+	UndoLastOperation(ctx context.Context, in *UndoLastOperationRequest, opts ...grpc.CallOption) (*UndoLastOperationResponse, error)
+	// RenameTag renames a tag on every task the caller can modify
+	RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*RenameTagResponse, error)
+	// SuggestTags returns the caller's tags that start with a prefix, most used first
+	SuggestTags(ctx context.Context, in *SuggestTagsRequest, opts ...grpc.CallOption) (*SuggestTagsResponse, error)
+	// GetTaskStats returns aggregate counts over the caller's tasks
+	GetTaskStats(ctx context.Context, in *GetTaskStatsRequest, opts ...grpc.CallOption) (*TaskStats, error)
+	// BatchGetTasks fetches several tasks at once, reporting per name whether
+	// the task was found, missing or not readable by the caller
+	BatchGetTasks(ctx context.Context, in *BatchGetTasksRequest, opts ...grpc.CallOption) (*BatchGetTasksResponse, error)
+	// ReassignUserTasks moves every task owned by one user to another. Admin only.
+	ReassignUserTasks(ctx context.Context, in *ReassignUserTasksRequest, opts ...grpc.CallOption) (*ReassignUserTasksResponse, error)
+	// CreateTaskTemplate stores a reusable task template in the caller's tenant
+	CreateTaskTemplate(ctx context.Context, in *CreateTaskTemplateRequest, opts ...grpc.CallOption) (*TaskTemplate, error)
+	// ListTaskTemplates lists the task templates of the caller's tenant
+	ListTaskTemplates(ctx context.Context, in *ListTaskTemplatesRequest, opts ...grpc.CallOption) (*ListTaskTemplatesResponse, error)
+	// CreateTaskFromTemplate creates a task from a template, applying any overrides
+	CreateTaskFromTemplate(ctx context.Context, in *CreateTaskFromTemplateRequest, opts ...grpc.CallOption) (*Task, error)
 }
 
 type todoServiceClient struct {
@@ -115,6 +143,96 @@ func (c *todoServiceClient) BatchCreateTasks(ctx context.Context, in *BatchCreat
 	return out, nil
 }
 
+func (c *todoServiceClient) UndoLastOperation(ctx context.Context, in *UndoLastOperationRequest, opts ...grpc.CallOption) (*UndoLastOperationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UndoLastOperationResponse)
+	err := c.cc.Invoke(ctx, TodoService_UndoLastOperation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*RenameTagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenameTagResponse)
+	err := c.cc.Invoke(ctx, TodoService_RenameTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) SuggestTags(ctx context.Context, in *SuggestTagsRequest, opts ...grpc.CallOption) (*SuggestTagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestTagsResponse)
+	err := c.cc.Invoke(ctx, TodoService_SuggestTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetTaskStats(ctx context.Context, in *GetTaskStatsRequest, opts ...grpc.CallOption) (*TaskStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskStats)
+	err := c.cc.Invoke(ctx, TodoService_GetTaskStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) BatchGetTasks(ctx context.Context, in *BatchGetTasksRequest, opts ...grpc.CallOption) (*BatchGetTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetTasksResponse)
+	err := c.cc.Invoke(ctx, TodoService_BatchGetTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ReassignUserTasks(ctx context.Context, in *ReassignUserTasksRequest, opts ...grpc.CallOption) (*ReassignUserTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReassignUserTasksResponse)
+	err := c.cc.Invoke(ctx, TodoService_ReassignUserTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateTaskTemplate(ctx context.Context, in *CreateTaskTemplateRequest, opts ...grpc.CallOption) (*TaskTemplate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskTemplate)
+	err := c.cc.Invoke(ctx, TodoService_CreateTaskTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTaskTemplates(ctx context.Context, in *ListTaskTemplatesRequest, opts ...grpc.CallOption) (*ListTaskTemplatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTaskTemplatesResponse)
+	err := c.cc.Invoke(ctx, TodoService_ListTaskTemplates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateTaskFromTemplate(ctx context.Context, in *CreateTaskFromTemplateRequest, opts ...grpc.CallOption) (*Task, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Task)
+	err := c.cc.Invoke(ctx, TodoService_CreateTaskFromTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TodoServiceServer is the server API for TodoService service.
 // All implementations must embed UnimplementedTodoServiceServer
 // for forward compatibility.
@@ -133,6 +251,25 @@ type TodoServiceServer interface {
 	DeleteTask(context.Context, *DeleteTaskRequest) (*DeleteTaskResponse, error)
 	// BatchCreateTasks creates multiple tasks at once
 	BatchCreateTasks(context.Context, *BatchCreateTasksRequest) (*BatchCreateTasksResponse, error)
+	// UndoLastOperation reverses the caller's most recent create, update or delete
+	UndoLastOperation(context.Context, *UndoLastOperationRequest) (*UndoLastOperationResponse, error)
+	// RenameTag renames a tag on every task the caller can modify
+	RenameTag(context.Context, *RenameTagRequest) (*RenameTagResponse, error)
+	// SuggestTags returns the caller's tags that start with a prefix, most used first
+	SuggestTags(context.Context, *SuggestTagsRequest) (*SuggestTagsResponse, error)
+	// GetTaskStats returns aggregate counts over the caller's tasks
+	GetTaskStats(context.Context, *GetTaskStatsRequest) (*TaskStats, error)
+	// BatchGetTasks fetches several tasks at once, reporting per name whether
+	// the task was found, missing or not readable by the caller
+	BatchGetTasks(context.Context, *BatchGetTasksRequest) (*BatchGetTasksResponse, error)
+	// ReassignUserTasks moves every task owned by one user to another. Admin only.
+	ReassignUserTasks(context.Context, *ReassignUserTasksRequest) (*ReassignUserTasksResponse, error)
+	// CreateTaskTemplate stores a reusable task template in the caller's tenant
+	CreateTaskTemplate(context.Context, *CreateTaskTemplateRequest) (*TaskTemplate, error)
+	// ListTaskTemplates lists the task templates of the caller's tenant
+	ListTaskTemplates(context.Context, *ListTaskTemplatesRequest) (*ListTaskTemplatesResponse, error)
+	// CreateTaskFromTemplate creates a task from a template, applying any overrides
+	CreateTaskFromTemplate(context.Context, *CreateTaskFromTemplateRequest) (*Task, error)
 	mustEmbedUnimplementedTodoServiceServer()
 }
 
@@ -161,6 +298,33 @@ func (UnimplementedTodoServiceServer) DeleteTask(context.Context, *DeleteTaskReq
 func (UnimplementedTodoServiceServer) BatchCreateTasks(context.Context, *BatchCreateTasksRequest) (*BatchCreateTasksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateTasks not implemented")
 }
+func (UnimplementedTodoServiceServer) UndoLastOperation(context.Context, *UndoLastOperationRequest) (*UndoLastOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndoLastOperation not implemented")
+}
+func (UnimplementedTodoServiceServer) RenameTag(context.Context, *RenameTagRequest) (*RenameTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameTag not implemented")
+}
+func (UnimplementedTodoServiceServer) SuggestTags(context.Context, *SuggestTagsRequest) (*SuggestTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestTags not implemented")
+}
+func (UnimplementedTodoServiceServer) GetTaskStats(context.Context, *GetTaskStatsRequest) (*TaskStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaskStats not implemented")
+}
+func (UnimplementedTodoServiceServer) BatchGetTasks(context.Context, *BatchGetTasksRequest) (*BatchGetTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetTasks not implemented")
+}
+func (UnimplementedTodoServiceServer) ReassignUserTasks(context.Context, *ReassignUserTasksRequest) (*ReassignUserTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReassignUserTasks not implemented")
+}
+func (UnimplementedTodoServiceServer) CreateTaskTemplate(context.Context, *CreateTaskTemplateRequest) (*TaskTemplate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTaskTemplate not implemented")
+}
+func (UnimplementedTodoServiceServer) ListTaskTemplates(context.Context, *ListTaskTemplatesRequest) (*ListTaskTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTaskTemplates not implemented")
+}
+func (UnimplementedTodoServiceServer) CreateTaskFromTemplate(context.Context, *CreateTaskFromTemplateRequest) (*Task, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTaskFromTemplate not implemented")
+}
 func (UnimplementedTodoServiceServer) mustEmbedUnimplementedTodoServiceServer() {}
 func (UnimplementedTodoServiceServer) testEmbeddedByValue()                     {}
 
@@ -290,6 +454,168 @@ func _TodoService_BatchCreateTasks_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TodoService_UndoLastOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndoLastOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UndoLastOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_UndoLastOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UndoLastOperation(ctx, req.(*UndoLastOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RenameTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RenameTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_RenameTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RenameTag(ctx, req.(*RenameTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_SuggestTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).SuggestTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_SuggestTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).SuggestTags(ctx, req.(*SuggestTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetTaskStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetTaskStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_GetTaskStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetTaskStats(ctx, req.(*GetTaskStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_BatchGetTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).BatchGetTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_BatchGetTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).BatchGetTasks(ctx, req.(*BatchGetTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ReassignUserTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReassignUserTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ReassignUserTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_ReassignUserTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ReassignUserTasks(ctx, req.(*ReassignUserTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateTaskTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTaskTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateTaskTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_CreateTaskTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateTaskTemplate(ctx, req.(*CreateTaskTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTaskTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTaskTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTaskTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_ListTaskTemplates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTaskTemplates(ctx, req.(*ListTaskTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateTaskFromTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTaskFromTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateTaskFromTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoService_CreateTaskFromTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateTaskFromTemplate(ctx, req.(*CreateTaskFromTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TodoService_ServiceDesc is the grpc.ServiceDesc for TodoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -321,6 +647,42 @@ var TodoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BatchCreateTasks",
 			Handler:    _TodoService_BatchCreateTasks_Handler,
 		},
+		{
+			MethodName: "UndoLastOperation",
+			Handler:    _TodoService_UndoLastOperation_Handler,
+		},
+		{
+			MethodName: "RenameTag",
+			Handler:    _TodoService_RenameTag_Handler,
+		},
+		{
+			MethodName: "SuggestTags",
+			Handler:    _TodoService_SuggestTags_Handler,
+		},
+		{
+			MethodName: "GetTaskStats",
+			Handler:    _TodoService_GetTaskStats_Handler,
+		},
+		{
+			MethodName: "BatchGetTasks",
+			Handler:    _TodoService_BatchGetTasks_Handler,
+		},
+		{
+			MethodName: "ReassignUserTasks",
+			Handler:    _TodoService_ReassignUserTasks_Handler,
+		},
+		{
+			MethodName: "CreateTaskTemplate",
+			Handler:    _TodoService_CreateTaskTemplate_Handler,
+		},
+		{
+			MethodName: "ListTaskTemplates",
+			Handler:    _TodoService_ListTaskTemplates_Handler,
+		},
+		{
+			MethodName: "CreateTaskFromTemplate",
+			Handler:    _TodoService_CreateTaskFromTemplate_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/todo/v1/todo.proto",
@@ -0,0 +1,357 @@
+package config
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds deployment settings that can be tuned without recompiling
+type Config struct {
+	// RequestTimeout bounds how long a single RPC may run
+	RequestTimeout time.Duration
+
+	// Tracing controls how request traces are sampled
+	Tracing TracingConfig
+
+	// Features toggles optional, operator-facing functionality
+	Features FeaturesConfig
+
+	// Tenants holds per-tenant workflow settings keyed by tenant ID
+	Tenants map[string]TenantConfig
+
+	// Validation tightens field limits beyond the proto rules
+	Validation ValidationConfig
+
+	// Maintenance blocks writes during migrations
+	Maintenance MaintenanceConfig
+
+	// Errors controls how error responses are rendered
+	Errors ErrorsConfig
+
+	// Logging controls optional structured logs
+	Logging LoggingConfig
+
+	// CORS sets which browser origins may call the public API routes
+	CORS CORSConfig
+
+	// Replay rejects replayed writes that carry a signed nonce
+	Replay ReplayConfig
+
+	// Auth controls what callers without credentials may do
+	Auth AuthConfig
+
+	// Concurrency caps how many requests are handled at once
+	Concurrency ConcurrencyConfig
+
+	// Envelope wraps successful HTTP responses for clients that expect it
+	Envelope EnvelopeConfig
+}
+
+// TracingConfig controls trace sampling
+type TracingConfig struct {
+	// SampleRatio is the fraction (0.0-1.0) of successful requests that are traced.
+	// Requests failing with one of ForceSampleCodes are always traced
+	// regardless of this ratio.
+	SampleRatio float64
+
+	// ForceSampleCodes names the gRPC codes, e.g. INTERNAL, whose requests are
+	// always traced. Client errors are left out by default so bad requests
+	// can't defeat the ratio.
+	ForceSampleCodes []string
+}
+
+// FeaturesConfig toggles optional features that are off unless enabled
+type FeaturesConfig struct {
+	// DebugErrors exposes sample error responses under /v1/debug/errors
+	DebugErrors bool
+
+	// MutationLogSize keeps the last N task writes for admins under
+	// /v1/debug/mutations; zero turns the log off
+	MutationLogSize int
+
+	// Methods turns individual endpoints on or off by method name, e.g.
+	// {"BatchCreateTasks": false}. Methods not listed stay enabled.
+	Methods map[string]bool
+}
+
+// ValidationConfig overrides length limits for a deployment. Zero keeps the
+// limit declared in the proto; values can only make validation stricter.
+type ValidationConfig struct {
+	MaxTitleLength       int
+	MaxDescriptionLength int
+
+	// UniqueTitles rejects tasks whose title is already used in the tenant
+	UniqueTitles bool
+
+	// MaxResponseBytes caps the estimated size of a ListTasks page; zero
+	// means no limit
+	MaxResponseBytes int
+
+	// DefaultOrderBy sorts ListTasks when a request has no order_by, e.g.
+	// "-create_time" for newest first
+	DefaultOrderBy string
+
+	// OverdueGrace is how late a task may be completed without being
+	// flagged as an overdue completion
+	OverdueGrace time.Duration
+
+	// BlockedWords are rejected as whole words in titles and descriptions
+	BlockedWords []string
+}
+
+// MaintenanceConfig controls read-only mode. While ReadOnly is set, mutating
+// RPCs fail with 503 and clients are asked to retry after RetryAfter.
+type MaintenanceConfig struct {
+	ReadOnly   bool
+	RetryAfter time.Duration
+}
+
+// ErrorsConfig controls the shape of problem+json error bodies
+type ErrorsConfig struct {
+	// SnakeCaseFields renders keys like trace_id instead of traceId, matching
+	// the proto field names used in success responses
+	SnakeCaseFields bool
+
+	// RedactInternal replaces the detail of internal errors with a generic
+	// message for clients; logs keep the original detail and cause
+	RedactInternal bool
+
+	// TrustedProxies lists the proxy IPs or CIDRs whose X-Forwarded-Path and
+	// X-Forwarded-Prefix headers set the instance; empty ignores the headers
+	TrustedProxies []string
+}
+
+// LoggingConfig sets the level of the structured analytics log. At "debug"
+// every field violation is logged with its field, code and a hash of the
+// value, never the value itself.
+type LoggingConfig struct {
+	Level string
+
+	// ViolationHashKey is the secret the value hashes are keyed with. Empty
+	// uses a random key per process, so hashes don't group across restarts.
+	ViolationHashKey string
+
+	// SlowRequestThreshold is the duration past which a request is logged at
+	// warn with slow=true; faster requests are logged at debug. Zero logs
+	// every request at debug.
+	SlowRequestThreshold time.Duration
+}
+
+// CORSConfig is the CORS policy for public routes. Debug routes never get
+// CORS headers regardless of this setting.
+type CORSConfig struct {
+	// AllowedOrigins lists origins allowed to call the API; "*" allows any
+	AllowedOrigins []string
+}
+
+// ReplayConfig enables signed request nonces. Protection is off while
+// Secret is empty.
+type ReplayConfig struct {
+	// Secret is the HMAC key clients sign nonces with
+	Secret string
+
+	// Window is how far a nonce's timestamp may be from the server clock
+	Window time.Duration
+}
+
+// AuthConfig controls access for anonymous callers
+type AuthConfig struct {
+	// AnonymousPolicy is "allow" (read and write), "read_only" or "deny"
+	AnonymousPolicy string
+}
+
+// ConcurrencyConfig limits in-flight requests. A request over MaxInFlight
+// waits up to QueueTimeout for a slot before it is rejected.
+type ConcurrencyConfig struct {
+	// MaxInFlight is the number of requests handled at once; zero means no limit
+	MaxInFlight int
+
+	QueueTimeout time.Duration
+}
+
+// EnvelopeConfig wraps successful gateway responses as
+// {"<DataKey>": ..., "<MetaKey>": ...}. Error bodies are never wrapped.
+type EnvelopeConfig struct {
+	Enabled bool
+	DataKey string
+	MetaKey string
+}
+
+// TenantConfig lists a tenant's users, restricts the enum values its tasks
+// may use and sets the defaults for new tasks. Users are the credentials the
+// caller authenticates with; a request's tenant is derived from them. Values
+// are enum names (e.g. "PRIORITY_HIGH"); an empty list allows every value and
+// an empty default keeps the server default.
+type TenantConfig struct {
+	Users             []string `json:"users"`
+	AllowedStatuses   []string `json:"allowed_statuses"`
+	AllowedPriorities []string `json:"allowed_priorities"`
+	DefaultStatus     string   `json:"default_status"`
+	DefaultPriority   string   `json:"default_priority"`
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() *Config {
+	return &Config{
+		RequestTimeout: 30 * time.Second,
+		Tracing: TracingConfig{
+			SampleRatio:      1.0,
+			ForceSampleCodes: []string{"UNKNOWN", "DEADLINE_EXCEEDED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS"},
+		},
+		Validation: ValidationConfig{
+			UniqueTitles:   true,
+			DefaultOrderBy: "-create_time",
+		},
+		Maintenance: MaintenanceConfig{
+			RetryAfter: 5 * time.Minute,
+		},
+		Logging: LoggingConfig{
+			Level:                "info",
+			SlowRequestThreshold: time.Second,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+		},
+		Replay: ReplayConfig{
+			Window: 5 * time.Minute,
+		},
+		Auth: AuthConfig{
+			AnonymousPolicy: "allow",
+		},
+		Concurrency: ConcurrencyConfig{
+			QueueTimeout: 100 * time.Millisecond,
+		},
+		Envelope: EnvelopeConfig{
+			DataKey: "data",
+			MetaKey: "meta",
+		},
+	}
+}
+
+// Load builds a configuration from environment variables, falling back to defaults
+func Load() *Config {
+	cfg := Default()
+	cfg.RequestTimeout = envDuration("TODO_REQUEST_TIMEOUT", cfg.RequestTimeout)
+	cfg.Tracing.SampleRatio = envFloat("TODO_TRACE_SAMPLE_RATIO", cfg.Tracing.SampleRatio)
+	cfg.Tracing.ForceSampleCodes = envList("TODO_TRACE_FORCE_SAMPLE_CODES", cfg.Tracing.ForceSampleCodes)
+	cfg.Features.DebugErrors = envBool("TODO_ENABLE_DEBUG_ERRORS", cfg.Features.DebugErrors)
+	cfg.Features.MutationLogSize = envInt("TODO_MUTATION_LOG_SIZE", cfg.Features.MutationLogSize)
+	cfg.Features.Methods = envMethodFlags("TODO_METHOD_FLAGS", cfg.Features.Methods)
+	cfg.Tenants = envTenants("TODO_TENANTS", cfg.Tenants)
+	cfg.Validation.MaxTitleLength = envInt("TODO_MAX_TITLE_LENGTH", cfg.Validation.MaxTitleLength)
+	cfg.Validation.MaxDescriptionLength = envInt("TODO_MAX_DESCRIPTION_LENGTH", cfg.Validation.MaxDescriptionLength)
+	cfg.Validation.UniqueTitles = envBool("TODO_UNIQUE_TITLES", cfg.Validation.UniqueTitles)
+	cfg.Validation.MaxResponseBytes = envInt("TODO_MAX_RESPONSE_BYTES", cfg.Validation.MaxResponseBytes)
+	cfg.Validation.DefaultOrderBy = envString("TODO_DEFAULT_ORDER_BY", cfg.Validation.DefaultOrderBy)
+	cfg.Validation.OverdueGrace = envDuration("TODO_OVERDUE_GRACE", cfg.Validation.OverdueGrace)
+	cfg.Validation.BlockedWords = envList("TODO_BLOCKED_WORDS", cfg.Validation.BlockedWords)
+	cfg.Maintenance.ReadOnly = envBool("TODO_READ_ONLY", cfg.Maintenance.ReadOnly)
+	cfg.Maintenance.RetryAfter = envDuration("TODO_READ_ONLY_RETRY_AFTER", cfg.Maintenance.RetryAfter)
+	cfg.Errors.SnakeCaseFields = envBool("TODO_ERROR_SNAKE_CASE", cfg.Errors.SnakeCaseFields)
+	cfg.Errors.RedactInternal = envBool("TODO_REDACT_INTERNAL_ERRORS", cfg.Errors.RedactInternal)
+	cfg.Errors.TrustedProxies = envList("TODO_TRUSTED_PROXIES", cfg.Errors.TrustedProxies)
+	cfg.Logging.Level = envString("TODO_LOG_LEVEL", cfg.Logging.Level)
+	cfg.Logging.ViolationHashKey = envString("TODO_VIOLATION_HASH_KEY", cfg.Logging.ViolationHashKey)
+	cfg.Logging.SlowRequestThreshold = envDuration("TODO_SLOW_REQUEST_THRESHOLD", cfg.Logging.SlowRequestThreshold)
+	cfg.CORS.AllowedOrigins = envList("TODO_CORS_ORIGINS", cfg.CORS.AllowedOrigins)
+	cfg.Replay.Secret = envString("TODO_NONCE_SECRET", cfg.Replay.Secret)
+	cfg.Replay.Window = envDuration("TODO_NONCE_WINDOW", cfg.Replay.Window)
+	cfg.Auth.AnonymousPolicy = envString("TODO_ANONYMOUS_POLICY", cfg.Auth.AnonymousPolicy)
+	cfg.Concurrency.MaxInFlight = envInt("TODO_MAX_IN_FLIGHT", cfg.Concurrency.MaxInFlight)
+	cfg.Concurrency.QueueTimeout = envDuration("TODO_MAX_IN_FLIGHT_WAIT", cfg.Concurrency.QueueTimeout)
+	cfg.Envelope.Enabled = envBool("TODO_RESPONSE_ENVELOPE", cfg.Envelope.Enabled)
+	cfg.Envelope.DataKey = envString("TODO_ENVELOPE_DATA_KEY", cfg.Envelope.DataKey)
+	cfg.Envelope.MetaKey = envString("TODO_ENVELOPE_META_KEY", cfg.Envelope.MetaKey)
+	return cfg
+}
+
+// Helper functions
+
+func envString(key string, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// envList reads a comma-separated list, ignoring blank entries
+func envList(key string, fallback []string) []string {
+	if v, ok := os.LookupEnv(key); ok {
+		var list []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		if len(list) > 0 {
+			return list
+		}
+	}
+	return fallback
+}
+
+func envFloat(key string, fallback float64) float64 {
+	if v, ok := os.LookupEnv(key); ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
+
+func envInt(key string, fallback int) int {
+	if v, ok := os.LookupEnv(key); ok {
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return fallback
+}
+
+func envBool(key string, fallback bool) bool {
+	if v, ok := os.LookupEnv(key); ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
+// envTenants reads a JSON object of tenant ID to TenantConfig, e.g.
+// {"acme":{"users":["alice"],"allowed_priorities":["PRIORITY_MEDIUM","PRIORITY_HIGH"]}}
+func envTenants(key string, fallback map[string]TenantConfig) map[string]TenantConfig {
+	if v, ok := os.LookupEnv(key); ok {
+		var tenants map[string]TenantConfig
+		if err := json.Unmarshal([]byte(v), &tenants); err == nil {
+			return tenants
+		}
+		log.Printf("Ignoring invalid %s: expected a JSON object of tenant settings", key)
+	}
+	return fallback
+}
+
+// envMethodFlags reads a JSON object of method name to enabled flag, e.g.
+// {"BatchCreateTasks":false}
+func envMethodFlags(key string, fallback map[string]bool) map[string]bool {
+	if v, ok := os.LookupEnv(key); ok {
+		var methods map[string]bool
+		if err := json.Unmarshal([]byte(v), &methods); err == nil {
+			return methods
+		}
+		log.Printf("Ignoring invalid %s: expected a JSON object of method flags", key)
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if v, ok := os.LookupEnv(key); ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,41 @@
+package duedate
+
+import (
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+
+	// Embed the zone database so time zones resolve in minimal containers
+	_ "time/tzdata"
+)
+
+// Location returns the task's time zone, or UTC when it has none or the zone
+// is unknown
+func Location(task *todopb.Task) *time.Location {
+	if task.GetTimeZone() == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(task.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Deadline returns the instant a task falls due. A due date at midnight UTC on
+// a task with a time zone is a date-only due date: the task is due at the end
+// of that calendar day in its zone. Otherwise the due date is the deadline.
+func Deadline(task *todopb.Task) time.Time {
+	due := task.GetDueDate().AsTime()
+	if task.GetTimeZone() == "" || !due.Equal(due.Truncate(24*time.Hour)) {
+		return due
+	}
+
+	y, m, d := due.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, Location(task)).Add(-time.Nanosecond)
+}
+
+// IsOverdue reports whether a task with a due date is past its deadline at now
+func IsOverdue(task *todopb.Task, now time.Time) bool {
+	return task.GetDueDate() != nil && now.After(Deadline(task))
+}
@@ -0,0 +1,46 @@
+package duedate
+
+import (
+	"testing"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDateOnlyDueDateUsesTaskTimeZone(t *testing.T) {
+	task := &todopb.Task{
+		DueDate:  timestamppb.New(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)),
+		TimeZone: "America/Los_Angeles",
+	}
+
+	// 2024-06-02 05:00 UTC is still 2024-06-01 22:00 in Los Angeles
+	if now := time.Date(2024, 6, 2, 5, 0, 0, 0, time.UTC); IsOverdue(task, now) {
+		t.Errorf("IsOverdue at %v = true, want false before the local end of day", now)
+	}
+	if now := time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC); !IsOverdue(task, now) {
+		t.Errorf("IsOverdue at %v = false, want true after the local end of day", now)
+	}
+
+	// Without a zone the due date is the deadline
+	task.TimeZone = ""
+	if now := time.Date(2024, 6, 1, 0, 0, 1, 0, time.UTC); !IsOverdue(task, now) {
+		t.Errorf("IsOverdue without a zone at %v = false, want true", now)
+	}
+}
+
+func TestDeadlineKeepsTimedDueDates(t *testing.T) {
+	due := time.Date(2024, 6, 1, 15, 30, 0, 0, time.UTC)
+	task := &todopb.Task{DueDate: timestamppb.New(due), TimeZone: "America/Los_Angeles"}
+	if got := Deadline(task); !got.Equal(due) {
+		t.Errorf("Deadline = %v, want %v", got, due)
+	}
+}
+
+func TestLocationFallsBackToUTC(t *testing.T) {
+	for _, zone := range []string{"", "Not/AZone"} {
+		if got := Location(&todopb.Task{TimeZone: zone}); got != time.UTC {
+			t.Errorf("Location(%q) = %v, want UTC", zone, got)
+		}
+	}
+}
@@ -2,15 +2,23 @@ package errors
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// SchemaVersion identifies the shape of the error envelope. Bump it when
+// fields are added, renamed or change meaning.
+const SchemaVersion = "1"
+
 // AppError is our custom error type using protobuf definitions.
 type AppError struct {
 	GRPCCode        codes.Code
@@ -21,7 +29,53 @@ type AppError struct {
 	TraceID         string
 	Instance        string
 	Extensions      map[string]*anypb.Any
-	CausedBy        error // For internal logging
+	RetryAfter      time.Duration // When clients may retry; zero if unknown
+	CausedBy        error         // For internal logging
+}
+
+// Severity says how much attention an error deserves in logs
+type Severity int
+
+const (
+	SeverityDebug Severity = iota
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "DEBUG"
+	case SeverityInfo:
+		return "INFO"
+	case SeverityWarn:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// SeverityForCode classifies a gRPC code: client mistakes are Info, transient
+// server conditions are Warn and genuine server faults are Error
+func SeverityForCode(code codes.Code) Severity {
+	switch code {
+	case codes.OK:
+		return SeverityDebug
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.FailedPrecondition,
+		codes.Aborted, codes.OutOfRange, codes.Unimplemented:
+		return SeverityInfo
+	case codes.ResourceExhausted, codes.Unavailable, codes.DeadlineExceeded:
+		return SeverityWarn
+	default:
+		return SeverityError
+	}
+}
+
+// Severity is derived from the gRPC code so it can never disagree with it
+func (e *AppError) Severity() Severity {
+	return SeverityForCode(e.GRPCCode)
 }
 
 func (e *AppError) Error() string {
@@ -41,6 +95,7 @@ func (e *AppError) ToGRPCStatus() *status.Status {
 		Timestamp:       timestamppb.Now(),
 		Instance:        e.Instance,
 		Extensions:      e.Extensions,
+		Version:         SchemaVersion,
 	}
 
 	// For validation errors, we also attach the standard BadRequest detail
@@ -57,10 +112,89 @@ func (e *AppError) ToGRPCStatus() *status.Status {
 		return st
 	}
 
+	// Tell well-behaved clients when to come back
+	if e.RetryAfter > 0 {
+		st, _ = st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)}, errorDetail)
+		return st
+	}
+
 	st, _ = st.WithDetails(errorDetail)
 	return st
 }
 
+// FromGRPCStatus reconstructs an AppError from a gRPC status. Our ErrorDetail is
+// preferred; the standard BadRequest and ErrorInfo details are used as a fallback
+// for statuses produced by other services or libraries.
+func FromGRPCStatus(st *status.Status) *AppError {
+	appCode, title := defaultsForCode(st.Code())
+	appErr := &AppError{
+		GRPCCode: st.Code(),
+		AppCode:  appCode,
+		Title:    title,
+		Detail:   st.Message(),
+	}
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.RetryInfo:
+			appErr.RetryAfter = d.RetryDelay.AsDuration()
+		case *errorspb.ErrorDetail:
+			appErr.AppCode = errorspb.AppErrorCode(errorspb.AppErrorCode_value[d.Code])
+			appErr.Title = d.Title
+			appErr.Detail = d.Detail
+			appErr.FieldViolations = d.FieldViolations
+			appErr.TraceID = d.TraceId
+			appErr.Instance = d.Instance
+			appErr.Extensions = d.Extensions
+			return appErr
+		case *errdetails.BadRequest:
+			for _, fv := range d.FieldViolations {
+				appErr.FieldViolations = append(appErr.FieldViolations, &errorspb.FieldViolation{
+					Field:       fv.Field,
+					Code:        errorspb.AppErrorCode_VALIDATION_FAILED.String(),
+					Description: fv.Description,
+				})
+			}
+		case *errdetails.ErrorInfo:
+			if code, ok := errorspb.AppErrorCode_value[d.Reason]; ok {
+				appErr.AppCode = errorspb.AppErrorCode(code)
+			}
+		}
+	}
+
+	return appErr
+}
+
+// defaultsForCode picks an app code and title for statuses without an ErrorDetail
+func defaultsForCode(code codes.Code) (errorspb.AppErrorCode, string) {
+	switch code {
+	case codes.InvalidArgument:
+		return errorspb.AppErrorCode_VALIDATION_FAILED, "Validation Failed"
+	case codes.NotFound:
+		return errorspb.AppErrorCode_RESOURCE_NOT_FOUND, "Resource Not Found"
+	case codes.AlreadyExists:
+		return errorspb.AppErrorCode_RESOURCE_CONFLICT, "Resource Conflict"
+	case codes.Unauthenticated:
+		return errorspb.AppErrorCode_AUTHENTICATION_FAILED, "Authentication Failed"
+	case codes.PermissionDenied:
+		return errorspb.AppErrorCode_PERMISSION_DENIED, "Permission Denied"
+	case codes.ResourceExhausted:
+		return errorspb.AppErrorCode_RATE_LIMIT_EXCEEDED, "Rate Limit Exceeded"
+	case codes.Unavailable:
+		return errorspb.AppErrorCode_SERVICE_UNAVAILABLE, "Service Unavailable"
+	case codes.DeadlineExceeded:
+		return errorspb.AppErrorCode_TIMEOUT, "Request Timeout"
+	case codes.Canceled:
+		return errorspb.AppErrorCode_CANCELED, "Request Canceled"
+	case codes.Unimplemented:
+		return errorspb.AppErrorCode_NOT_IMPLEMENTED, "Not Implemented"
+	case codes.FailedPrecondition:
+		return errorspb.AppErrorCode_INCONSISTENT_STATE, "Inconsistent State"
+	default:
+		return errorspb.AppErrorCode_INTERNAL_ERROR, "Internal Server Error"
+	}
+}
+
 // Helper functions for creating common errors
 
 func NewValidationFailed(violations []*errorspb.FieldViolation, traceID string) *AppError {
@@ -74,6 +208,55 @@ func NewValidationFailed(violations []*errorspb.FieldViolation, traceID string)
 	}
 }
 
+func NewInvalidArgument(detail string, traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.InvalidArgument,
+		AppCode:  errorspb.AppErrorCode_INVALID_ARGUMENT,
+		Title:    "Invalid Argument",
+		Detail:   detail,
+		TraceID:  traceID,
+	}
+}
+
+// NewMalformedBody reports a request body that could not be decoded as JSON.
+// where locates the failure, e.g. " at line 1, column 24: unexpected end of input".
+func NewMalformedBody(where string, traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.InvalidArgument,
+		AppCode:  errorspb.AppErrorCode_INVALID_FORMAT,
+		Title:    "Malformed Request Body",
+		Detail:   "Request body is not valid JSON" + where,
+		TraceID:  traceID,
+	}
+}
+
+// FilterSyntax is the filter grammar suggested to clients when a filter is rejected
+const FilterSyntax = "field=value [AND field=value ...], e.g. status=STATUS_PENDING AND priority=PRIORITY_HIGH"
+
+func NewInvalidFilter(token string, position int, reason string, traceID string) *AppError {
+	appErr := &AppError{
+		GRPCCode: codes.InvalidArgument,
+		AppCode:  errorspb.AppErrorCode_INVALID_FILTER,
+		Title:    "Invalid Filter",
+		Detail:   fmt.Sprintf("Invalid filter: %s at position %d (%q). Expected syntax: %s", reason, position, token, FilterSyntax),
+		TraceID:  traceID,
+	}
+
+	// Point clients at the exact token that failed
+	if pointer, err := structpb.NewStruct(map[string]interface{}{
+		"token":    token,
+		"position": position,
+		"reason":   reason,
+		"syntax":   FilterSyntax,
+	}); err == nil {
+		if ext, err := anypb.New(pointer); err == nil {
+			appErr.Extensions = map[string]*anypb.Any{"filter": ext}
+		}
+	}
+
+	return appErr
+}
+
 func NewNotFound(resource string, id string, traceID string) *AppError {
 	return &AppError{
 		GRPCCode: codes.NotFound,
@@ -94,6 +277,47 @@ func NewConflict(resource, reason string, traceID string) *AppError {
 	}
 }
 
+// NewFieldConflict is a NewConflict that also points at the fields that
+// clash with an existing resource, so clients get the same errors[] array as
+// for validation failures
+func NewFieldConflict(resource, reason string, violations []*errorspb.FieldViolation, traceID string) *AppError {
+	appErr := NewConflict(resource, reason, traceID)
+	appErr.FieldViolations = violations
+	return appErr
+}
+
+func NewMethodNotAllowed(method, path string, allowed []string, traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.Unimplemented,
+		AppCode:  errorspb.AppErrorCode_METHOD_NOT_ALLOWED,
+		Title:    "Method Not Allowed",
+		Detail:   fmt.Sprintf("%s is not supported on %s. Allowed methods: %s", method, path, strings.Join(allowed, ", ")),
+		TraceID:  traceID,
+	}
+}
+
+func NewNotImplemented(method string, traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.Unimplemented,
+		AppCode:  errorspb.AppErrorCode_NOT_IMPLEMENTED,
+		Title:    "Not Implemented",
+		Detail:   fmt.Sprintf("%s is not implemented yet.", method),
+		TraceID:  traceID,
+	}
+}
+
+// NewInconsistentState reports a change the resource's current state does not
+// allow, e.g. an illegal status transition
+func NewInconsistentState(resource, detail string, traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.FailedPrecondition,
+		AppCode:  errorspb.AppErrorCode_INCONSISTENT_STATE,
+		Title:    "Inconsistent State",
+		Detail:   fmt.Sprintf("The %s cannot be changed this way: %s", resource, detail),
+		TraceID:  traceID,
+	}
+}
+
 func NewInternal(message string, traceID string, causedBy error) *AppError {
 	return &AppError{
 		GRPCCode: codes.Internal,
@@ -105,26 +329,175 @@ func NewInternal(message string, traceID string, causedBy error) *AppError {
 	}
 }
 
-func NewPermissionDenied(resource, action string, traceID string) *AppError {
-	return &AppError{
+// NewPermissionDenied reports a forbidden action. A non-empty requiredRole
+// names the role that would grant access, so clients can prompt for elevation.
+func NewPermissionDenied(resource, action, requiredRole string, traceID string) *AppError {
+	appErr := &AppError{
 		GRPCCode: codes.PermissionDenied,
 		AppCode:  errorspb.AppErrorCode_PERMISSION_DENIED,
 		Title:    "Permission Denied",
 		Detail:   fmt.Sprintf("You don't have permission to %s %s", action, resource),
 		TraceID:  traceID,
 	}
+
+	if requiredRole != "" {
+		appErr.Detail += fmt.Sprintf("; the %s role is required", requiredRole)
+		if info, err := structpb.NewStruct(map[string]interface{}{"name": requiredRole}); err == nil {
+			if ext, err := anypb.New(info); err == nil {
+				appErr.Extensions = map[string]*anypb.Any{"requiredRole": ext}
+			}
+		}
+	}
+
+	return appErr
+}
+
+func NewAuthenticationRequired(action string, traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.Unauthenticated,
+		AppCode:  errorspb.AppErrorCode_AUTHENTICATION_FAILED,
+		Title:    "Authentication Required",
+		Detail:   fmt.Sprintf("You must sign in to %s.", action),
+		TraceID:  traceID,
+	}
 }
 
-func NewServiceUnavailable(message string, traceID string) *AppError {
+func NewReplayedRequest(detail string, traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.Unauthenticated,
+		AppCode:  errorspb.AppErrorCode_REPLAYED_REQUEST,
+		Title:    "Replayed Request",
+		Detail:   detail,
+		TraceID:  traceID,
+	}
+}
+
+// NewNonceReused reports a correctly signed request whose nonce was already
+// used. The signature checked out, so this is a conflict with the earlier
+// request rather than an authentication failure.
+func NewNonceReused(traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.AlreadyExists,
+		AppCode:  errorspb.AppErrorCode_REPLAYED_REQUEST,
+		Title:    "Replayed Request",
+		Detail:   "The request nonce has already been used.",
+		TraceID:  traceID,
+	}
+}
+
+// NewServiceUnavailable reports that a backend the request needs is down.
+// dependency names it (e.g. "database") for operators; pass "" if unknown.
+// retryAfter hints when to try again; pass zero if unknown.
+func NewServiceUnavailable(dependency, message string, retryAfter time.Duration, traceID string) *AppError {
+	appErr := &AppError{
+		GRPCCode:   codes.Unavailable,
+		AppCode:    errorspb.AppErrorCode_SERVICE_UNAVAILABLE,
+		Title:      "Service Unavailable",
+		Detail:     message,
+		TraceID:    traceID,
+		RetryAfter: retryAfter,
+	}
+
+	if dependency != "" {
+		if info, err := structpb.NewStruct(map[string]interface{}{"name": dependency}); err == nil {
+			if ext, err := anypb.New(info); err == nil {
+				appErr.Extensions = map[string]*anypb.Any{"dependency": ext}
+			}
+		}
+	}
+
+	return appErr
+}
+
+func NewBadGateway(traceID string) *AppError {
 	return &AppError{
 		GRPCCode: codes.Unavailable,
-		AppCode:  errorspb.AppErrorCode_SERVICE_UNAVAILABLE,
-		Title:    "Service Unavailable",
-		Detail:   message,
+		AppCode:  errorspb.AppErrorCode_UPSTREAM_UNAVAILABLE,
+		Title:    "Bad Gateway",
+		Detail:   "The gateway was unable to reach the upstream service. Please try again later.",
 		TraceID:  traceID,
 	}
 }
 
+func NewTimeout(traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.DeadlineExceeded,
+		AppCode:  errorspb.AppErrorCode_TIMEOUT,
+		Title:    "Request Timeout",
+		Detail:   "The request did not complete within the allotted time. Please try again later.",
+		TraceID:  traceID,
+	}
+}
+
+// NewCanceled reports a request the client abandoned before it completed
+func NewCanceled(traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.Canceled,
+		AppCode:  errorspb.AppErrorCode_CANCELED,
+		Title:    "Request Canceled",
+		Detail:   "The request was canceled by the client.",
+		TraceID:  traceID,
+	}
+}
+
+func NewFeatureDisabled(feature string, traceID string) *AppError {
+	return &AppError{
+		GRPCCode: codes.Unimplemented,
+		AppCode:  errorspb.AppErrorCode_FEATURE_DISABLED,
+		Title:    "Feature Disabled",
+		Detail:   fmt.Sprintf("The %s feature is disabled on this server.", feature),
+		TraceID:  traceID,
+	}
+}
+
+func NewReadOnly(retryAfter time.Duration, traceID string) *AppError {
+	return &AppError{
+		GRPCCode:   codes.Unavailable,
+		AppCode:    errorspb.AppErrorCode_READ_ONLY,
+		Title:      "Read-Only Mode",
+		Detail:     "The service is in read-only mode for maintenance. Reads are still available; please retry writes later.",
+		TraceID:    traceID,
+		RetryAfter: retryAfter,
+	}
+}
+
+// NewTooManyRequests reports that the server is shedding load; clients
+// should back off for retryAfter before trying again
+func NewTooManyRequests(detail string, retryAfter time.Duration, traceID string) *AppError {
+	return &AppError{
+		GRPCCode:   codes.ResourceExhausted,
+		AppCode:    errorspb.AppErrorCode_RATE_LIMIT_EXCEEDED,
+		Title:      "Too Many Requests",
+		Detail:     detail,
+		TraceID:    traceID,
+		RetryAfter: retryAfter,
+	}
+}
+
+func NewResponseTooLarge(size, budget, suggestedPageSize int, traceID string) *AppError {
+	appErr := &AppError{
+		GRPCCode: codes.ResourceExhausted,
+		AppCode:  errorspb.AppErrorCode_RESPONSE_TOO_LARGE,
+		Title:    "Response Too Large",
+		Detail: fmt.Sprintf("The response would be about %d bytes, over the %d byte limit. Retry with page_size=%d or less.",
+			size, budget, suggestedPageSize),
+		TraceID: traceID,
+	}
+
+	// Let clients retry with a workable page size without parsing the detail
+	if hint, err := structpb.NewStruct(map[string]interface{}{
+		"estimatedBytes":    size,
+		"maxBytes":          budget,
+		"suggestedPageSize": suggestedPageSize,
+	}); err == nil {
+		if ext, err := anypb.New(hint); err == nil {
+			appErr.Extensions = map[string]*anypb.Any{"responseSize": ext}
+		}
+	}
+
+	return appErr
+}
+
 func NewRequiredField(field, message string, traceID string) *AppError {
 	return &AppError{
 		GRPCCode: codes.InvalidArgument,
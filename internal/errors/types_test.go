@@ -0,0 +1,145 @@
+package errors
+
+import (
+	"testing"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFromGRPCStatusRoundTrip(t *testing.T) {
+	for _, original := range []*AppError{
+		NewValidationFailed([]*errorspb.FieldViolation{
+			{Field: "title", Code: errorspb.AppErrorCode_REQUIRED_FIELD.String(), Description: "Title is required"},
+			{Field: "tags[1]", Code: errorspb.AppErrorCode_DUPLICATE_TAG.String(), Description: "Tag 'a' appears multiple times"},
+		}, "trace-1"),
+		NewNotFound("Task", "tasks/1", "trace-2"),
+		NewTimeout("trace-3"),
+		NewCanceled("trace-5"),
+		NewInternal("boom", "trace-4", nil),
+	} {
+		got := FromGRPCStatus(original.ToGRPCStatus())
+
+		if got.GRPCCode != original.GRPCCode || got.AppCode != original.AppCode {
+			t.Errorf("%s: codes = %v/%v, want %v/%v", original.Title, got.GRPCCode, got.AppCode, original.GRPCCode, original.AppCode)
+		}
+		if got.Title != original.Title || got.Detail != original.Detail {
+			t.Errorf("%s: title/detail = %q/%q, want %q/%q", original.Title, got.Title, got.Detail, original.Title, original.Detail)
+		}
+		if got.TraceID != original.TraceID {
+			t.Errorf("%s: trace ID = %q, want %q", original.Title, got.TraceID, original.TraceID)
+		}
+		if len(got.FieldViolations) != len(original.FieldViolations) {
+			t.Fatalf("%s: %d violations, want %d", original.Title, len(got.FieldViolations), len(original.FieldViolations))
+		}
+		for i := range original.FieldViolations {
+			if !proto.Equal(got.FieldViolations[i], original.FieldViolations[i]) {
+				t.Errorf("%s: violation %d = %v, want %v", original.Title, i, got.FieldViolations[i], original.FieldViolations[i])
+			}
+		}
+	}
+}
+
+func TestFromGRPCStatusFallsBackToStandardDetails(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "bad input").WithDetails(
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "title", Description: "too short"},
+		}},
+		&errdetails.ErrorInfo{Reason: errorspb.AppErrorCode_INVALID_FORMAT.String()},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := FromGRPCStatus(st)
+	if got.AppCode != errorspb.AppErrorCode_INVALID_FORMAT {
+		t.Errorf("app code = %v, want the ErrorInfo reason", got.AppCode)
+	}
+	if got.Detail != "bad input" {
+		t.Errorf("detail = %q, want the status message", got.Detail)
+	}
+	if len(got.FieldViolations) != 1 || got.FieldViolations[0].Field != "title" || got.FieldViolations[0].Description != "too short" {
+		t.Errorf("violations = %v, want the BadRequest violation", got.FieldViolations)
+	}
+}
+
+func TestFromGRPCStatusWithoutDetails(t *testing.T) {
+	for code, want := range map[codes.Code]errorspb.AppErrorCode{
+		codes.NotFound:         errorspb.AppErrorCode_RESOURCE_NOT_FOUND,
+		codes.DeadlineExceeded: errorspb.AppErrorCode_TIMEOUT,
+		codes.Unknown:          errorspb.AppErrorCode_INTERNAL_ERROR,
+	} {
+		got := FromGRPCStatus(status.New(code, "plain"))
+		if got.GRPCCode != code || got.AppCode != want {
+			t.Errorf("%v: got %v/%v, want %v/%v", code, got.GRPCCode, got.AppCode, code, want)
+		}
+	}
+}
+
+func TestNewInvalidArgumentHasNoFieldViolations(t *testing.T) {
+	appErr := NewInvalidArgument("Failed to parse filter", "trace-1")
+	if appErr.GRPCCode != codes.InvalidArgument || appErr.AppCode != errorspb.AppErrorCode_INVALID_ARGUMENT {
+		t.Errorf("codes = %v/%v, want InvalidArgument/INVALID_ARGUMENT", appErr.GRPCCode, appErr.AppCode)
+	}
+	if appErr.Detail != "Failed to parse filter" || appErr.TraceID != "trace-1" {
+		t.Errorf("detail/trace = %q/%q", appErr.Detail, appErr.TraceID)
+	}
+
+	for _, detail := range appErr.ToGRPCStatus().Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			t.Errorf("unexpected BadRequest detail %v", d)
+		case *errorspb.ErrorDetail:
+			if len(d.FieldViolations) != 0 {
+				t.Errorf("unexpected field violations %v", d.FieldViolations)
+			}
+		}
+	}
+}
+
+func TestToGRPCStatusCarriesSchemaVersion(t *testing.T) {
+	for _, appErr := range []*AppError{
+		NewNotFound("Task", "tasks/1", "trace-1"),
+		NewValidationFailed([]*errorspb.FieldViolation{{Field: "title", Description: "required"}}, "trace-2"),
+	} {
+		var found bool
+		for _, detail := range appErr.ToGRPCStatus().Details() {
+			if d, ok := detail.(*errorspb.ErrorDetail); ok {
+				found = true
+				if d.Version != SchemaVersion {
+					t.Errorf("%s: version = %q, want %q", appErr.Title, d.Version, SchemaVersion)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s: no ErrorDetail", appErr.Title)
+		}
+	}
+}
+
+func TestRetryAfterRoundTripsAsRetryInfo(t *testing.T) {
+	st := NewServiceUnavailable("database", "down", 5*time.Second, "trace-1").ToGRPCStatus()
+
+	var info *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			info = ri
+		}
+	}
+	if info == nil || info.RetryDelay.AsDuration() != 5*time.Second {
+		t.Fatalf("RetryInfo = %v, want a 5s delay", info)
+	}
+	if got := FromGRPCStatus(st).RetryAfter; got != 5*time.Second {
+		t.Errorf("RetryAfter after round trip = %v, want 5s", got)
+	}
+
+	for _, d := range NewServiceUnavailable("", "down", 0, "").ToGRPCStatus().Details() {
+		if _, ok := d.(*errdetails.RetryInfo); ok {
+			t.Error("RetryInfo attached without a retry delay")
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+)
+
+// maxRejectedValueLen bounds echoed input, in characters
+const maxRejectedValueLen = 64
+
+// echoableFields may have their rejected input echoed back to clients. They
+// never hold personal data; account fields must not be added here.
+var echoableFields = map[string]bool{
+	"title":    true,
+	"tags":     true,
+	"priority": true,
+}
+
+// Violations collects field violations fluently:
+//
+//	v := NewViolations().
+//		Add("title", errorspb.AppErrorCode_REQUIRED_FIELD, "Title is required").
+//		Addf(IndexedField("tags", 2), errorspb.AppErrorCode_DUPLICATE_TAG, "Tag '%s' appears multiple times", tag)
+type Violations struct {
+	list []*errorspb.FieldViolation
+}
+
+func NewViolations() *Violations {
+	return &Violations{}
+}
+
+// Add records a violation of field
+func (v *Violations) Add(field string, code errorspb.AppErrorCode, description string) *Violations {
+	v.list = append(v.list, &errorspb.FieldViolation{
+		Field:       field,
+		Code:        code.String(),
+		Description: description,
+	})
+	return v
+}
+
+// Addf is Add with a formatted description
+func (v *Violations) Addf(field string, code errorspb.AppErrorCode, format string, args ...interface{}) *Violations {
+	return v.Add(field, code, fmt.Sprintf(format, args...))
+}
+
+// Append records violations built elsewhere, e.g. by proto validation
+func (v *Violations) Append(violations ...*errorspb.FieldViolation) *Violations {
+	v.list = append(v.list, violations...)
+	return v
+}
+
+// List returns the violations recorded so far
+func (v *Violations) List() []*errorspb.FieldViolation {
+	return v.list
+}
+
+func (v *Violations) Empty() bool {
+	return len(v.list) == 0
+}
+
+// Err returns a VALIDATION_FAILED error for the recorded violations, or nil
+// if there are none
+func (v *Violations) Err(traceID string) error {
+	if v.Empty() {
+		return nil
+	}
+	return NewValidationFailed(v.list, traceID)
+}
+
+// EchoRejectedValue attaches the offending input to fv, truncated, when the
+// field is on the echo allowlist. Any other field, including every account
+// field, is left without a value.
+func EchoRejectedValue(fv *errorspb.FieldViolation, value string) {
+	if !echoableFields[leafField(fv.Field)] {
+		return
+	}
+	if utf8.RuneCountInString(value) > maxRejectedValueLen {
+		value = string([]rune(value)[:maxRejectedValueLen]) + "…"
+	}
+	fv.RejectedValue = value
+}
+
+// leafField strips the parent path and index from a field path, e.g.
+// requests[0].task.tags[2] becomes tags
+func leafField(path string) string {
+	if dot := strings.LastIndex(path, "."); dot >= 0 {
+		path = path[dot+1:]
+	}
+	if open := strings.Index(path, "["); open >= 0 {
+		path = path[:open]
+	}
+	return path
+}
+
+// IndexedField formats the path of a repeated field element, e.g. tags[2]
+func IndexedField(field string, index int) string {
+	return fmt.Sprintf("%s[%d]", field, index)
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestViolationsBuilder(t *testing.T) {
+	v := NewViolations().
+		Add("title", errorspb.AppErrorCode_REQUIRED_FIELD, "Title is required").
+		Addf(IndexedField("tags", 2), errorspb.AppErrorCode_DUPLICATE_TAG, "Tag '%s' appears multiple times", "home").
+		Append(&errorspb.FieldViolation{Field: "description", Code: errorspb.AppErrorCode_TOO_LONG.String(), Description: "too long"})
+
+	want := []*errorspb.FieldViolation{
+		{Field: "title", Code: "REQUIRED_FIELD", Description: "Title is required"},
+		{Field: "tags[2]", Code: "DUPLICATE_TAG", Description: "Tag 'home' appears multiple times"},
+		{Field: "description", Code: "TOO_LONG", Description: "too long"},
+	}
+	got := v.List()
+	if len(got) != len(want) {
+		t.Fatalf("List() = %v, want %d violations", got, len(want))
+	}
+	for i := range want {
+		if !proto.Equal(got[i], want[i]) {
+			t.Errorf("violation %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	var appErr *AppError
+	if err := v.Err("trace-1"); !errors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_VALIDATION_FAILED || appErr.TraceID != "trace-1" || len(appErr.FieldViolations) != 3 {
+		t.Errorf("Err() = %v, want VALIDATION_FAILED with 3 violations", err)
+	}
+}
+
+func TestEmptyViolationsHaveNoError(t *testing.T) {
+	v := NewViolations()
+	if !v.Empty() || v.Err("trace-1") != nil {
+		t.Errorf("empty builder: Empty() = %v, Err() = %v", v.Empty(), v.Err("trace-1"))
+	}
+	if v.Append().Empty() != true {
+		t.Error("appending nothing made the builder non-empty")
+	}
+}
+
+func TestEchoRejectedValueOnlyForAllowlistedFields(t *testing.T) {
+	long := strings.Repeat("x", maxRejectedValueLen+10)
+
+	for _, tc := range []struct {
+		field, value, want string
+	}{
+		{"title", "Buy milk!!", "Buy milk!!"},
+		{"requests[0].task.tags[2]", "urgent", "urgent"},
+		{"title", long, strings.Repeat("x", maxRejectedValueLen) + "…"},
+		{"ssn", "123-45-6789", ""},
+		{"account.ssn", "123-45-6789", ""},
+		{"email", "alice@example.com", ""},
+	} {
+		fv := &errorspb.FieldViolation{Field: tc.field}
+		EchoRejectedValue(fv, tc.value)
+		if fv.RejectedValue != tc.want {
+			t.Errorf("%s: rejected value = %q, want %q", tc.field, fv.RejectedValue, tc.want)
+		}
+	}
+}
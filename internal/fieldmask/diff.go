@@ -0,0 +1,89 @@
+package fieldmask
+
+import (
+	"encoding/json"
+	"strings"
+
+	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Change describes one masked field whose value differs between two versions
+type Change struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// Diff lists the fields named in mask whose values differ between before and
+// after, rendered as JSON values. Paths that don't resolve are skipped, as are
+// fields annotated with MEDIUM or HIGH sensitivity so PII never leaks into
+// change logs.
+func Diff(before, after proto.Message, mask *fieldmaskpb.FieldMask) []Change {
+	if mask == nil {
+		return nil
+	}
+
+	var changes []Change
+	md := before.ProtoReflect().Descriptor()
+	for _, path := range Normalize(mask).Paths {
+		if checkPath(md, path, nil) != nil || isSensitivePath(md, path) {
+			continue
+		}
+
+		// Project each version down to just this path and compare
+		segments := strings.Split(path, ".")
+		oldPart := before.ProtoReflect().New()
+		newPart := after.ProtoReflect().New()
+		copyPath(oldPart, before.ProtoReflect(), segments)
+		copyPath(newPart, after.ProtoReflect(), segments)
+		if proto.Equal(oldPart.Interface(), newPart.Interface()) {
+			continue
+		}
+
+		changes = append(changes, Change{
+			Field: path,
+			Old:   jsonValueAt(oldPart.Interface(), segments),
+			New:   jsonValueAt(newPart.Interface(), segments),
+		})
+	}
+	return changes
+}
+
+func isSensitivePath(md protoreflect.MessageDescriptor, path string) bool {
+	for _, segment := range strings.Split(path, ".") {
+		fd := md.Fields().ByName(protoreflect.Name(segment))
+		level := proto.GetExtension(fd.Options(), pii.E_Sensitivity).(pii.SensitivityLevel)
+		if level >= pii.SensitivityLevel_MEDIUM {
+			return true
+		}
+		if fd.Kind() == protoreflect.MessageKind {
+			md = fd.Message()
+		}
+	}
+	return false
+}
+
+// jsonValueAt renders the value at path the way clients see it over HTTP
+func jsonValueAt(msg proto.Message, segments []string) interface{} {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	for _, segment := range segments {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = obj[segment]
+	}
+	return value
+}
@@ -0,0 +1,47 @@
+package fieldmask
+
+import (
+	"reflect"
+	"testing"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestDiffListsOnlyChangedMaskedFields(t *testing.T) {
+	before := &todopb.Task{Title: "Old", Description: "Same", Priority: todopb.Priority_PRIORITY_LOW, Tags: []string{"a"}}
+	after := &todopb.Task{Title: "New", Description: "Same", Priority: todopb.Priority_PRIORITY_HIGH, Tags: []string{"b"}}
+
+	got := Diff(before, after, &fieldmaskpb.FieldMask{Paths: []string{"title", "description", "priority", "bogus"}})
+	want := []Change{
+		{Field: "title", Old: "Old", New: "New"},
+		{Field: "priority", Old: "PRIORITY_LOW", New: "PRIORITY_HIGH"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffRendersClearedAndRepeatedFields(t *testing.T) {
+	before := &todopb.Task{Description: "Gone", Tags: []string{"a"}}
+	after := &todopb.Task{Tags: []string{"a", "b"}}
+
+	got := Diff(before, after, &fieldmaskpb.FieldMask{Paths: []string{"description,tags"}})
+	want := []Change{
+		{Field: "description", Old: "Gone", New: nil},
+		{Field: "tags", Old: []interface{}{"a"}, New: []interface{}{"a", "b"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffWithoutChanges(t *testing.T) {
+	task := &todopb.Task{Title: "Same"}
+	if got := Diff(task, task, &fieldmaskpb.FieldMask{Paths: []string{"title"}}); len(got) != 0 {
+		t.Errorf("Diff of identical tasks = %+v, want none", got)
+	}
+	if got := Diff(task, &todopb.Task{}, nil); got != nil {
+		t.Errorf("Diff without a mask = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,159 @@
+package fieldmask
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Common errors
+var (
+	ErrUnknownField   = errors.New("unknown field")
+	ErrImmutableField = errors.New("field cannot be updated")
+)
+
+// PathError reports the field mask path that could not be applied
+type PathError struct {
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("%s: %q", e.Err, e.Path)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// Apply copies the fields named in mask from src to dst using proto
+// reflection, so new proto fields are picked up without code changes. Paths
+// may name nested message fields ("home_address.city"). A path that names an
+// immutable field (or a field inside one) or a field that does not exist is
+// rejected, and in that case dst is left untouched. The mask is normalized
+// first, so REST-style paths are accepted as well.
+func Apply(dst, src proto.Message, mask *fieldmaskpb.FieldMask, immutable ...string) error {
+	if mask == nil {
+		return nil
+	}
+	mask = Normalize(mask)
+
+	// Check every path up front so a bad path never leaves dst half updated
+	for _, path := range mask.Paths {
+		if err := checkPath(dst.ProtoReflect().Descriptor(), path, immutable); err != nil {
+			return err
+		}
+	}
+
+	// Copy from a clone so dst never shares lists, maps or messages with src
+	srcMsg := proto.Clone(src).ProtoReflect()
+	for _, path := range mask.Paths {
+		copyPath(dst.ProtoReflect(), srcMsg, strings.Split(path, "."))
+	}
+	return nil
+}
+
+// Validate checks that every path in mask names a field of msg, e.g. for a
+// read mask. The mask is normalized first, as in Apply.
+func Validate(msg proto.Message, mask *fieldmaskpb.FieldMask) error {
+	if mask == nil {
+		return nil
+	}
+	for _, path := range Normalize(mask).Paths {
+		if err := checkPath(msg.ProtoReflect().Descriptor(), path, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Normalize converts REST-style masks into proto paths: entries holding a
+// comma-joined list ("title,dueDate") are split, and camelCase JSON names are
+// converted to snake_case. Empty and duplicate paths are dropped.
+func Normalize(mask *fieldmaskpb.FieldMask) *fieldmaskpb.FieldMask {
+	normalized := &fieldmaskpb.FieldMask{}
+	seen := make(map[string]bool)
+	for _, entry := range mask.Paths {
+		for _, path := range strings.Split(entry, ",") {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+
+			segments := strings.Split(path, ".")
+			for i, segment := range segments {
+				segments[i] = toSnakeCase(segment)
+			}
+			path = strings.Join(segments, ".")
+
+			if !seen[path] {
+				seen[path] = true
+				normalized.Paths = append(normalized.Paths, path)
+			}
+		}
+	}
+	return normalized
+}
+
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func checkPath(md protoreflect.MessageDescriptor, path string, immutable []string) error {
+	for _, name := range immutable {
+		if path == name || strings.HasPrefix(path, name+".") {
+			return &PathError{Path: path, Err: ErrImmutableField}
+		}
+	}
+
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		fd := md.Fields().ByName(protoreflect.Name(segment))
+		if fd == nil {
+			return &PathError{Path: path, Err: ErrUnknownField}
+		}
+		if i == len(segments)-1 {
+			break
+		}
+		// Only singular message fields can be traversed
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return &PathError{Path: path, Err: ErrUnknownField}
+		}
+		md = fd.Message()
+	}
+	return nil
+}
+
+func copyPath(dst, src protoreflect.Message, segments []string) {
+	fd := dst.Descriptor().Fields().ByName(protoreflect.Name(segments[0]))
+
+	if len(segments) > 1 {
+		if !src.Has(fd) && !dst.Has(fd) {
+			return
+		}
+		copyPath(dst.Mutable(fd).Message(), src.Get(fd).Message(), segments[1:])
+		return
+	}
+
+	// A masked field that is unset in src clears the field in dst
+	if src.Has(fd) {
+		dst.Set(fd, src.Get(fd))
+	} else {
+		dst.Clear(fd)
+	}
+}
@@ -0,0 +1,205 @@
+package fieldmask
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{"proto paths unchanged", []string{"title", "due_date"}, []string{"title", "due_date"}},
+		{"comma-joined", []string{"title,description"}, []string{"title", "description"}},
+		{"comma-joined with spaces", []string{" title , description ,"}, []string{"title", "description"}},
+		{"camelCase", []string{"dueDate", "createdBy"}, []string{"due_date", "created_by"}},
+		{"nested camelCase", []string{"homeAddress.postalCode"}, []string{"home_address.postal_code"}},
+		{"mixed and duplicated", []string{"dueDate,due_date", "title"}, []string{"due_date", "title"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Normalize(&fieldmaskpb.FieldMask{Paths: tc.paths}).Paths
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Normalize(%q) = %q, want %q", tc.paths, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		dst, src  proto.Message
+		paths     []string
+		immutable []string
+		want      proto.Message
+		wantErr   error
+	}{
+		{
+			name:  "scalar",
+			dst:   &todopb.Task{Title: "old", Description: "kept"},
+			src:   &todopb.Task{Title: "new", Description: "ignored"},
+			paths: []string{"title"},
+			want:  &todopb.Task{Title: "new", Description: "kept"},
+		},
+		{
+			name:  "scalar unset in src clears dst",
+			dst:   &todopb.Task{Title: "old", Description: "gone"},
+			src:   &todopb.Task{Title: "old"},
+			paths: []string{"description"},
+			want:  &todopb.Task{Title: "old"},
+		},
+		{
+			name:  "nested",
+			dst:   &pii.Account{HomeAddress: &pii.Address{City: "Austin", Country: "US"}},
+			src:   &pii.Account{HomeAddress: &pii.Address{City: "Denver", Country: "CA"}},
+			paths: []string{"home_address.city"},
+			want:  &pii.Account{HomeAddress: &pii.Address{City: "Denver", Country: "US"}},
+		},
+		{
+			name:  "nested into unset parent",
+			dst:   &pii.Account{},
+			src:   &pii.Account{HomeAddress: &pii.Address{City: "Denver", Country: "CA"}},
+			paths: []string{"homeAddress.city"},
+			want:  &pii.Account{HomeAddress: &pii.Address{City: "Denver"}},
+		},
+		{
+			name:  "whole nested message",
+			dst:   &pii.Account{HomeAddress: &pii.Address{City: "Austin", Country: "US"}},
+			src:   &pii.Account{HomeAddress: &pii.Address{City: "Denver"}},
+			paths: []string{"home_address"},
+			want:  &pii.Account{HomeAddress: &pii.Address{City: "Denver"}},
+		},
+		{
+			name:  "repeated replaces the list",
+			dst:   &todopb.Task{Tags: []string{"a", "b"}},
+			src:   &todopb.Task{Tags: []string{"c"}},
+			paths: []string{"tags"},
+			want:  &todopb.Task{Tags: []string{"c"}},
+		},
+		{
+			name:  "repeated unset in src clears the list",
+			dst:   &todopb.Task{Tags: []string{"a"}},
+			src:   &todopb.Task{},
+			paths: []string{"tags"},
+			want:  &todopb.Task{},
+		},
+		{
+			name:      "immutable",
+			dst:       &todopb.Task{Name: "tasks/1", Title: "old"},
+			src:       &todopb.Task{Name: "tasks/2", Title: "new"},
+			paths:     []string{"title", "name"},
+			immutable: []string{"name"},
+			want:      &todopb.Task{Name: "tasks/1", Title: "old"},
+			wantErr:   ErrImmutableField,
+		},
+		{
+			name:      "inside immutable message",
+			dst:       &pii.Account{HomeAddress: &pii.Address{City: "Austin"}},
+			src:       &pii.Account{HomeAddress: &pii.Address{City: "Denver"}},
+			paths:     []string{"home_address.city"},
+			immutable: []string{"home_address"},
+			want:      &pii.Account{HomeAddress: &pii.Address{City: "Austin"}},
+			wantErr:   ErrImmutableField,
+		},
+		{
+			name:    "unknown",
+			dst:     &todopb.Task{Title: "old"},
+			src:     &todopb.Task{Title: "new"},
+			paths:   []string{"title", "bogus"},
+			want:    &todopb.Task{Title: "old"},
+			wantErr: ErrUnknownField,
+		},
+		{
+			name:    "unknown nested",
+			dst:     &pii.Account{HomeAddress: &pii.Address{City: "Austin"}},
+			src:     &pii.Account{HomeAddress: &pii.Address{City: "Denver"}},
+			paths:   []string{"home_address.planet"},
+			want:    &pii.Account{HomeAddress: &pii.Address{City: "Austin"}},
+			wantErr: ErrUnknownField,
+		},
+		{
+			name:    "through a repeated field",
+			dst:     &todopb.Task{Tags: []string{"a"}},
+			src:     &todopb.Task{Tags: []string{"b"}},
+			paths:   []string{"tags.length"},
+			want:    &todopb.Task{Tags: []string{"a"}},
+			wantErr: ErrUnknownField,
+		},
+		{
+			name:    "through a scalar field",
+			dst:     &todopb.Task{Title: "old"},
+			src:     &todopb.Task{Title: "new"},
+			paths:   []string{"title.text"},
+			want:    &todopb.Task{Title: "old"},
+			wantErr: ErrUnknownField,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Apply(tc.dst, tc.src, &fieldmaskpb.FieldMask{Paths: tc.paths}, tc.immutable...)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Apply(%q) error = %v, want %v", tc.paths, err, tc.wantErr)
+			}
+			if !proto.Equal(tc.dst, tc.want) {
+				t.Errorf("Apply(%q) = %v, want %v", tc.paths, tc.dst, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyDoesNotAliasSource(t *testing.T) {
+	src := &todopb.Task{Tags: []string{"a"}}
+	dst := &todopb.Task{}
+	if err := Apply(dst, src, &fieldmaskpb.FieldMask{Paths: []string{"tags"}}); err != nil {
+		t.Fatal(err)
+	}
+	src.Tags[0] = "changed"
+	if dst.Tags[0] != "a" {
+		t.Errorf("dst.Tags = %q, shares storage with src", dst.Tags)
+	}
+}
+
+func TestApplyAcceptsRESTStyleMasks(t *testing.T) {
+	due := timestamppb.Now()
+	src := &todopb.Task{Title: "new title", Description: "new description", DueDate: due, Priority: todopb.Priority_PRIORITY_HIGH}
+
+	for _, paths := range [][]string{
+		{"title,description,dueDate"},
+		{"title", "description", "dueDate"},
+	} {
+		dst := &todopb.Task{Title: "old title", Description: "old description", Priority: todopb.Priority_PRIORITY_LOW}
+		if err := Apply(dst, src, &fieldmaskpb.FieldMask{Paths: paths}); err != nil {
+			t.Fatalf("Apply(%q): %v", paths, err)
+		}
+		if dst.Title != "new title" || dst.Description != "new description" || !dst.DueDate.AsTime().Equal(due.AsTime()) {
+			t.Errorf("Apply(%q) = %v, want title, description and due date copied", paths, dst)
+		}
+		if dst.Priority != todopb.Priority_PRIORITY_LOW {
+			t.Errorf("Apply(%q) changed priority, which is not in the mask", paths)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	task := &todopb.Task{}
+	if err := Validate(task, &fieldmaskpb.FieldMask{Paths: []string{"title", "dueDate"}}); err != nil {
+		t.Errorf("valid mask: %v", err)
+	}
+	if err := Validate(task, nil); err != nil {
+		t.Errorf("nil mask: %v", err)
+	}
+
+	err := Validate(task, &fieldmaskpb.FieldMask{Paths: []string{"title", "bogus"}})
+	var pathErr *PathError
+	if !errors.As(err, &pathErr) || pathErr.Path != "bogus" || !errors.Is(err, ErrUnknownField) {
+		t.Errorf("unknown path = %v, want ErrUnknownField naming bogus", err)
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TenantsByUser maps each user to the one tenant it belongs to, built from a
+// tenant -> users listing. A user listed under two tenants is rejected, since
+// its requests could not be scoped to either.
+func TenantsByUser(usersByTenant map[string][]string) (map[string]string, error) {
+	tenants := make(map[string]string)
+	for tenant, users := range usersByTenant {
+		for _, user := range users {
+			if other, ok := tenants[user]; ok && other != tenant {
+				return nil, fmt.Errorf("user %q belongs to both tenant %q and tenant %q", user, other, tenant)
+			}
+			tenants[user] = tenant
+		}
+	}
+	return tenants, nil
+}
+
+// AuthMiddleware puts the caller's user and tenant on the request context.
+// The tenant is looked up from the authenticated user, never read from the
+// request, so a caller cannot reach another tenant's data by naming it.
+// Users without a tenant get none and only see tenant-less data.
+func AuthMiddleware(tenantsByUser map[string]string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := withCaller(r.Context(), tenantsByUser, r.Header.Get("Authorization"))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// AuthInterceptor is the gRPC counterpart of AuthMiddleware. Gateway calls
+// reach the service over gRPC, so the user and tenant set on the HTTP request
+// are gone by then; the gateway forwards the Authorization header as metadata
+// and they are derived from it again here.
+func AuthInterceptor(tenantsByUser map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		return handler(withCaller(ctx, tenantsByUser, firstMetadataValue(md, "authorization")), req)
+	}
+}
+
+// withCaller resolves the user from an Authorization header value and the
+// tenant from that user.
+func withCaller(ctx context.Context, tenantsByUser map[string]string, authorization string) context.Context {
+	// Simple auth for demo - in production use proper authentication
+	user := "anonymous"
+	if token, ok := strings.CutPrefix(authorization, "Bearer "); ok && token != "" {
+		user = token
+	}
+
+	ctx = context.WithValue(ctx, "user", user)
+	if tenant, ok := tenantsByUser[user]; ok {
+		ctx = context.WithValue(ctx, "tenant", tenant)
+	}
+	return ctx
+}
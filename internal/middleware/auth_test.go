@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"github.com/bhatti/todo-api-errors/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// authenticate runs a request with the given headers through AuthMiddleware
+// and returns the context it hands to the next handler
+func authenticate(t *testing.T, tenantsByUser map[string]string, headers map[string]string) context.Context {
+	t.Helper()
+	var ctx context.Context
+	handler := AuthMiddleware(tenantsByUser, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tasks", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	return ctx
+}
+
+func TestAuthMiddlewareScopesToCredentialTenant(t *testing.T) {
+	tenantsByUser, err := TenantsByUser(map[string][]string{
+		"acme":   {"bob"},
+		"globex": {"carol"},
+	})
+	if err != nil {
+		t.Fatalf("TenantsByUser: %v", err)
+	}
+	todoService, err := service.NewTodoService(repository.NewInMemoryRepository(false))
+	if err != nil {
+		t.Fatalf("NewTodoService: %v", err)
+	}
+
+	bob := authenticate(t, tenantsByUser, map[string]string{"Authorization": "Bearer bob"})
+	task, err := todoService.CreateTask(bob, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "acme roadmap"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	// Naming the other tenant in a header must not grant access to it
+	carol := authenticate(t, tenantsByUser, map[string]string{"Authorization": "Bearer carol", "X-Tenant-ID": "acme"})
+	if got := carol.Value("tenant"); got != "globex" {
+		t.Errorf("carol's tenant = %v, want globex", got)
+	}
+	if _, err := todoService.GetTask(carol, &todopb.GetTaskRequest{Name: task.Name}); err == nil {
+		t.Error("tenant globex read a task of tenant acme")
+	}
+
+	if _, err := todoService.GetTask(bob, &todopb.GetTaskRequest{Name: task.Name}); err != nil {
+		t.Errorf("owner tenant could not read its task: %v", err)
+	}
+}
+
+func TestTenantsByUserRejectsSharedUsers(t *testing.T) {
+	_, err := TenantsByUser(map[string][]string{
+		"acme":   {"bob"},
+		"globex": {"bob"},
+	})
+	if err == nil {
+		t.Fatal("user in two tenants was accepted")
+	}
+}
+
+func TestAuthInterceptorReadsForwardedAuthorization(t *testing.T) {
+	interceptor := AuthInterceptor(map[string]string{"bob": "acme"})
+	info := &grpc.UnaryServerInfo{FullMethod: todopb.TodoService_ListTaskTemplates_FullMethodName}
+
+	for _, tc := range []struct {
+		name         string
+		md           metadata.MD
+		user, tenant interface{}
+	}{
+		{"bearer with tenant", metadata.Pairs("authorization", "Bearer bob"), "bob", "acme"},
+		{"bearer without tenant", metadata.Pairs("authorization", "Bearer dave"), "dave", nil},
+		{"no metadata", nil, "anonymous", nil},
+		{"tenant header ignored", metadata.Pairs("x-tenant-id", "acme"), "anonymous", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tc.md)
+			}
+			var got context.Context
+			_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				got = ctx
+				return nil, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user := got.Value("user"); user != tc.user {
+				t.Errorf("user = %v, want %v", user, tc.user)
+			}
+			if tenant := got.Value("tenant"); tenant != tc.tenant {
+				t.Errorf("tenant = %v, want %v", tenant, tc.tenant)
+			}
+		})
+	}
+}
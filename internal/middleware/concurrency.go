@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"google.golang.org/grpc"
+)
+
+// ConcurrencyLimitInterceptor handles at most maxInFlight requests at once.
+// A request over the limit waits up to wait for a slot and then fails with
+// RATE_LIMIT_EXCEEDED. A non-positive maxInFlight disables the limit.
+func ConcurrencyLimitInterceptor(maxInFlight int, wait time.Duration) grpc.UnaryServerInterceptor {
+	if maxInFlight <= 0 {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(ctx, req)
+		}
+	}
+
+	slots := make(chan struct{}, maxInFlight)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !acquireSlot(ctx, slots, wait) {
+			traceID := monitoring.TraceIDFromContext(ctx)
+			return nil, apperrors.NewTooManyRequests("The server is handling too many requests. Please retry shortly.", time.Second, traceID)
+		}
+		monitoring.AddInFlightRequests(ctx, 1)
+		defer func() {
+			monitoring.AddInFlightRequests(ctx, -1)
+			<-slots
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// acquireSlot takes a slot, waiting up to wait or until ctx is done
+func acquireSlot(ctx context.Context, slots chan struct{}, wait time.Duration) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestConcurrencyLimitRejectsOverflow(t *testing.T) {
+	interceptor := ConcurrencyLimitInterceptor(2, 10*time.Millisecond)
+	info := &grpc.UnaryServerInfo{FullMethod: "/todo.v1.TodoService/GetTask"}
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	blocking := func(ctx context.Context, req interface{}) (interface{}, error) {
+		started <- struct{}{}
+		<-release
+		return "ok", nil
+	}
+
+	// Saturate both slots
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := interceptor(context.Background(), nil, info, blocking)
+			done <- err
+		}()
+	}
+	<-started
+	<-started
+
+	if got := gaugeValue(t, "todo_api_in_flight_requests"); got < 2 {
+		t.Errorf("in-flight gauge = %v, want at least 2", got)
+	}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Error("overflow request reached the handler")
+		return "ok", nil
+	})
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.GRPCCode != codes.ResourceExhausted ||
+		appErr.AppCode != errorspb.AppErrorCode_RATE_LIMIT_EXCEEDED || appErr.RetryAfter <= 0 {
+		t.Errorf("overflow err = %v, want RATE_LIMIT_EXCEEDED with a retry delay", err)
+	}
+
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("in-limit request: %v", err)
+		}
+	}
+
+	// Freed slots are reused
+	if resp, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}); err != nil || resp != "ok" {
+		t.Errorf("after release = %v, %v; want ok", resp, err)
+	}
+}
+
+func TestConcurrencyLimitDisabled(t *testing.T) {
+	interceptor := ConcurrencyLimitInterceptor(0, 0)
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if err != nil || resp != "ok" {
+		t.Errorf("disabled limiter = %v, %v; want ok", resp, err)
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// EnvelopeMarshaler wraps successful gateway responses in an envelope such as
+// {"data": {...}, "meta": {"timestamp": "..."}} for clients that expect one.
+// Errors are rendered as problem+json by CustomHTTPError without going
+// through the marshaler, so they are never wrapped.
+type EnvelopeMarshaler struct {
+	runtime.Marshaler
+
+	// DataKey and MetaKey name the envelope's fields
+	DataKey string
+	MetaKey string
+}
+
+// Marshal renders v with the wrapped marshaler and places it under DataKey
+func (m *EnvelopeMarshaler) Marshal(v interface{}) ([]byte, error) {
+	data, err := m.Marshaler.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := json.Marshal(map[string]interface{}{
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]json.RawMessage{
+		m.DataKey: data,
+		m.MetaKey: meta,
+	})
+}
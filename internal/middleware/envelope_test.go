@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// envelopeTestService returns one known task and nothing else
+type envelopeTestService struct {
+	todopb.UnimplementedTodoServiceServer
+}
+
+func (envelopeTestService) GetTask(_ context.Context, req *todopb.GetTaskRequest) (*todopb.Task, error) {
+	if req.Name != "tasks/known" {
+		return nil, apperrors.NewNotFound("Task", req.Name, "").ToGRPCStatus().Err()
+	}
+	return &todopb.Task{Name: req.Name, Title: "Wrapped"}, nil
+}
+
+func newEnvelopeTestMux(t *testing.T) *runtime.ServeMux {
+	t.Helper()
+	mux := runtime.NewServeMux(
+		runtime.WithErrorHandler(CustomHTTPError),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &EnvelopeMarshaler{
+			Marshaler: &runtime.JSONPb{},
+			DataKey:   "data",
+			MetaKey:   "meta",
+		}),
+	)
+	if err := todopb.RegisterTodoServiceHandlerServer(context.Background(), mux, envelopeTestService{}); err != nil {
+		t.Fatal(err)
+	}
+	return mux
+}
+
+func TestEnvelopeWrapsSuccessfulResponses(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newEnvelopeTestMux(t).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tasks/known", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Data struct {
+			Name  string `json:"name"`
+			Title string `json:"title"`
+		} `json:"data"`
+		Meta struct {
+			Timestamp string `json:"timestamp"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Data.Name != "tasks/known" || body.Data.Title != "Wrapped" || body.Meta.Timestamp == "" {
+		t.Errorf("body = %s, want the task under data and a timestamp under meta", rec.Body.String())
+	}
+}
+
+func TestEnvelopeLeavesErrorsAlone(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newEnvelopeTestMux(t).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tasks/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("content type = %q, want application/problem+json", ct)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if _, wrapped := body["data"]; wrapped || body["status"] == nil {
+		t.Errorf("error body = %s, want unwrapped problem+json", rec.Body.String())
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"log"
+	"log/slog"
+	"path"
 
 	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -17,18 +21,91 @@ func UnaryErrorInterceptor(ctx context.Context, req interface{}, info *grpc.Unar
 		return resp, nil
 	}
 
+	// Server-side failures are always sampled
+	monitoring.MarkSpanFailed(ctx, grpcCodeOf(err), err.Error())
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
 	var appErr *apperrors.AppError
 	if errors.As(err, &appErr) {
-		if appErr.CausedBy != nil {
-			log.Printf("ERROR: %s, Original cause: %v", appErr.Title, appErr.CausedBy)
-		}
-		return nil, appErr.ToGRPCStatus().Err()
+		appErr.TraceID = traceID
+		logAppError(ctx, info.FullMethod, appErr)
+		logFieldViolations(ctx, info.FullMethod, req, appErr.FieldViolations)
+		runErrorHooks(ctx, appErr)
+		return nil, redactForClient(appErr).ToGRPCStatus().Err()
 	}
 
-	if _, ok := status.FromError(err); ok {
+	if st, ok := status.FromError(err); ok {
+		// RPCs declared in the proto but not yet implemented fall through to
+		// the embedded Unimplemented server, which returns a bare status
+		if st.Code() == codes.Unimplemented && len(st.Details()) == 0 {
+			appErr = apperrors.NewNotImplemented(path.Base(info.FullMethod), traceID)
+			logAppError(ctx, info.FullMethod, appErr)
+			runErrorHooks(ctx, appErr)
+			return nil, appErr.ToGRPCStatus().Err()
+		}
+
+		appErr = apperrors.FromGRPCStatus(st)
+		if appErr.TraceID == "" {
+			appErr.TraceID = traceID
+		}
+		logAppError(ctx, info.FullMethod, appErr)
+		runErrorHooks(ctx, appErr)
+		// A status without an ErrorDetail gets one, so CustomHTTPError knows
+		// the hooks already ran for it
+		if redacted := redactForClient(appErr); redacted != appErr || !hasErrorDetail(st) {
+			return nil, redacted.ToGRPCStatus().Err()
+		}
 		return nil, err // Already a gRPC status
 	}
 
 	log.Printf("UNEXPECTED ERROR: %v", err)
-	return nil, apperrors.NewInternal("An unexpected error occurred", "", err).ToGRPCStatus().Err()
+	appErr = apperrors.NewInternal("An unexpected error occurred", traceID, err)
+	runErrorHooks(ctx, appErr)
+	return nil, redactForClient(appErr).ToGRPCStatus().Err()
+}
+
+// grpcCodeOf is the status code err will reach the client with
+func grpcCodeOf(err error) codes.Code {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.GRPCCode
+	}
+	if st, ok := status.FromError(err); ok {
+		return st.Code()
+	}
+	return codes.Internal
+}
+
+// logAppError logs at the error's severity so expected client errors don't
+// drown out server faults. Repeats of the same code on the same method are
+// sampled.
+func logAppError(ctx context.Context, method string, appErr *apperrors.AppError) {
+	if !errorLogSampler.allow(method + " " + appErr.AppCode.String()) {
+		return
+	}
+
+	attrs := []any{
+		"method", method,
+		"code", appErr.AppCode.String(),
+		"trace_id", appErr.TraceID,
+	}
+	if appErr.CausedBy != nil {
+		attrs = append(attrs, "cause", appErr.CausedBy.Error())
+	}
+	slog.Log(ctx, slogLevel(appErr.Severity()), appErr.Detail, attrs...)
+}
+
+// slogLevel maps an error's severity to the matching slog level
+func slogLevel(severity apperrors.Severity) slog.Level {
+	switch severity {
+	case apperrors.SeverityDebug:
+		return slog.LevelDebug
+	case apperrors.SeverityInfo:
+		return slog.LevelInfo
+	case apperrors.SeverityWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
 }
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/grpc"
+)
+
+// captureSlog sends the default slog logger to a JSON buffer at debug for the
+// rest of the test
+func captureSlog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	saved := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(saved) })
+	return &buf
+}
+
+func TestAppErrorsLogAtTheirSeverity(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/todo.v1.TodoService/GetTask"}
+
+	for _, tc := range []struct {
+		name string
+		err  *apperrors.AppError
+		want string
+	}{
+		{"not found", apperrors.NewNotFound("Task", "tasks/1", ""), "INFO"},
+		{"validation", apperrors.NewInvalidArgument("bad", ""), "INFO"},
+		{"unavailable", apperrors.NewServiceUnavailable("database", "down", 0, ""), "WARN"},
+		{"internal", apperrors.NewInternal("boom", "", errors.New("disk full")), "ERROR"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := captureSlog(t)
+			UnaryErrorInterceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, tc.err
+			})
+
+			var entry struct {
+				Level  string `json:"level"`
+				Msg    string `json:"msg"`
+				Method string `json:"method"`
+				Code   string `json:"code"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("decode log %q: %v", buf.String(), err)
+			}
+			if entry.Level != tc.want {
+				t.Errorf("level = %s, want %s", entry.Level, tc.want)
+			}
+			if entry.Msg != tc.err.Detail || entry.Method != info.FullMethod || entry.Code != tc.err.AppCode.String() {
+				t.Errorf("entry = %+v, want the detail, method and code of %v", entry, tc.err)
+			}
+		})
+	}
+}
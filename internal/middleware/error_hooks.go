@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ErrorHook observes internal errors, e.g. to report them to Sentry. Hooks
+// receive a copy of the error and cannot change the response sent to clients.
+type ErrorHook func(ctx context.Context, appErr *apperrors.AppError)
+
+var (
+	errorHooksMu sync.RWMutex
+	errorHooks   []ErrorHook
+)
+
+// RegisterErrorHook adds a hook that runs once for each internal error, before
+// the response is written: in the gRPC interceptor for errors from the
+// service, and in the HTTP error path for errors the gateway raises itself
+func RegisterErrorHook(hook ErrorHook) {
+	errorHooksMu.Lock()
+	defer errorHooksMu.Unlock()
+	errorHooks = append(errorHooks, hook)
+}
+
+func runErrorHooks(ctx context.Context, appErr *apperrors.AppError) {
+	if !isInternalSeverity(appErr.GRPCCode) {
+		return
+	}
+
+	errorHooksMu.RLock()
+	hooks := errorHooks
+	errorHooksMu.RUnlock()
+
+	for _, hook := range hooks {
+		runErrorHook(ctx, hook, appErr)
+	}
+}
+
+func runErrorHook(ctx context.Context, hook ErrorHook, appErr *apperrors.AppError) {
+	// A misbehaving hook must never break the error response itself
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error hook panicked: %v", r)
+		}
+	}()
+	hook(ctx, copyAppError(appErr))
+}
+
+func copyAppError(appErr *apperrors.AppError) *apperrors.AppError {
+	copied := *appErr
+	copied.FieldViolations = nil
+	for _, fv := range appErr.FieldViolations {
+		copied.FieldViolations = append(copied.FieldViolations, proto.Clone(fv).(*errorspb.FieldViolation))
+	}
+	copied.Extensions = nil
+	if appErr.Extensions != nil {
+		copied.Extensions = make(map[string]*anypb.Any, len(appErr.Extensions))
+		for k, v := range appErr.Extensions {
+			copied.Extensions[k] = proto.Clone(v).(*anypb.Any)
+		}
+	}
+	return &copied
+}
+
+func isInternalSeverity(code codes.Code) bool {
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
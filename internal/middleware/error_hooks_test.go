@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// hookTestService fails GetTask in the way the task name asks for
+type hookTestService struct {
+	todopb.UnimplementedTodoServiceServer
+}
+
+func (hookTestService) GetTask(_ context.Context, req *todopb.GetTaskRequest) (*todopb.Task, error) {
+	switch req.Name {
+	case "tasks/internal":
+		return nil, apperrors.NewInternal("database exploded", "", nil)
+	case "tasks/bare":
+		return nil, status.Error(codes.Internal, "bare internal status")
+	default:
+		return nil, apperrors.NewInvalidArgument("bad task", "")
+	}
+}
+
+// recordErrorHooks replaces the registered hooks with one counting calls per
+// error code for the rest of the test
+func recordErrorHooks(t *testing.T) func() map[codes.Code]int {
+	errorHooksMu.Lock()
+	saved := errorHooks
+	errorHooks = nil
+	errorHooksMu.Unlock()
+	t.Cleanup(func() {
+		errorHooksMu.Lock()
+		errorHooks = saved
+		errorHooksMu.Unlock()
+	})
+
+	var mu sync.Mutex
+	calls := make(map[codes.Code]int)
+	RegisterErrorHook(func(_ context.Context, appErr *apperrors.AppError) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[appErr.GRPCCode]++
+	})
+	return func() map[codes.Code]int {
+		mu.Lock()
+		defer mu.Unlock()
+		snapshot := make(map[codes.Code]int, len(calls))
+		for k, v := range calls {
+			snapshot[k] = v
+		}
+		return snapshot
+	}
+}
+
+func TestErrorHooksFireOncePerInternalError(t *testing.T) {
+	calls := recordErrorHooks(t)
+	handler := newTestGateway(t, hookTestService{}, map[string]string{"bob": ""}, false)
+
+	for _, tc := range []struct {
+		path       string
+		wantStatus int
+		wantCalls  int
+	}{
+		{"/v1/tasks/internal", http.StatusInternalServerError, 1},
+		{"/v1/tasks/bare", http.StatusInternalServerError, 1},
+		{"/v1/tasks/invalid", http.StatusBadRequest, 0},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			before := calls()[codes.Internal]
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer bob")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tc.wantStatus, rec.Body.String())
+			}
+			if got := calls()[codes.Internal] - before; got != tc.wantCalls {
+				t.Errorf("hook ran %d times for INTERNAL, want %d", got, tc.wantCalls)
+			}
+		})
+	}
+	if n := calls()[codes.InvalidArgument]; n != 0 {
+		t.Errorf("hook ran %d times for a validation error, want 0", n)
+	}
+}
+
+func TestErrorHooksFireForGatewayErrors(t *testing.T) {
+	calls := recordErrorHooks(t)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/tasks/1", nil)
+	CustomHTTPError(req.Context(), nil, nil, rec, req, status.Error(codes.Internal, "gateway failure"))
+
+	if n := calls()[codes.Internal]; n != 1 {
+		t.Errorf("hook ran %d times for a gateway INTERNAL, want 1", n)
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import "sync"
+
+var (
+	errorNamingMu        sync.RWMutex
+	snakeCaseErrorFields bool
+)
+
+// SetSnakeCaseErrorFields switches problem+json keys such as traceId to
+// snake_case (trace_id), matching success bodies rendered with proto names.
+// The default is camelCase.
+func SetSnakeCaseErrorFields(enabled bool) {
+	errorNamingMu.Lock()
+	defer errorNamingMu.Unlock()
+	snakeCaseErrorFields = enabled
+}
+
+func useSnakeCaseErrorFields() bool {
+	errorNamingMu.RLock()
+	defer errorNamingMu.RUnlock()
+	return snakeCaseErrorFields
+}
+
+// errorFieldName picks the camelCase or snake_case spelling of a key
+func errorFieldName(camel, snake string) string {
+	if useSnakeCaseErrorFields() {
+		return snake
+	}
+	return camel
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestErrorBodyKeyCase(t *testing.T) {
+	retry, err := anypb.New(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	appErr := apperrors.NewNotFound("Task", "tasks/1", "trace-1")
+	appErr.Extensions = map[string]*anypb.Any{"retry": retry}
+
+	for _, tc := range []struct {
+		snake                 bool
+		traceKey, absentKey   string
+		retryKey, absentRetry string
+	}{
+		{false, "traceId", "trace_id", "retryDelay", "retry_delay"},
+		{true, "trace_id", "traceId", "retry_delay", "retryDelay"},
+	} {
+		SetSnakeCaseErrorFields(tc.snake)
+		t.Cleanup(func() { SetSnakeCaseErrorFields(false) })
+
+		rec := httptest.NewRecorder()
+		CustomHTTPError(context.Background(), nil, nil, rec, httptest.NewRequest(http.MethodGet, "/v1/tasks/1", nil), appErr.ToGRPCStatus().Err())
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if traceID, _ := body[tc.traceKey].(string); traceID == "" {
+			t.Errorf("snake=%v: %s missing from %v", tc.snake, tc.traceKey, body)
+		}
+		if _, ok := body[tc.absentKey]; ok {
+			t.Errorf("snake=%v: unexpected key %s", tc.snake, tc.absentKey)
+		}
+
+		extensions, _ := body["extensions"].(map[string]interface{})
+		retryBody, _ := extensions["retry"].(map[string]interface{})
+		if _, ok := retryBody[tc.retryKey]; !ok {
+			t.Errorf("snake=%v: extension keys %v, want %s", tc.snake, retryBody, tc.retryKey)
+		}
+		if _, ok := retryBody[tc.absentRetry]; ok {
+			t.Errorf("snake=%v: unexpected extension key %s", tc.snake, tc.absentRetry)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"sync"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+)
+
+// redactedInternalDetail replaces the detail of internal errors while
+// redaction is on; the trace ID still lets operators find the logged cause
+const redactedInternalDetail = "An internal error occurred. Please try again later or contact support with the trace ID."
+
+var (
+	errorRedactionMu      sync.RWMutex
+	redactInternalDetails bool
+)
+
+// SetRedactInternalDetails turns on production redaction: internal errors
+// reach clients with a generic detail, so raw error text an operator put in
+// the detail by mistake never leaks. Logs and error hooks still see the
+// original detail and cause.
+func SetRedactInternalDetails(enabled bool) {
+	errorRedactionMu.Lock()
+	defer errorRedactionMu.Unlock()
+	redactInternalDetails = enabled
+}
+
+// redactForClient returns appErr as it may be shown to clients. appErr itself
+// is left untouched for logging.
+func redactForClient(appErr *apperrors.AppError) *apperrors.AppError {
+	errorRedactionMu.RLock()
+	enabled := redactInternalDetails
+	errorRedactionMu.RUnlock()
+
+	if !enabled || !isInternalSeverity(appErr.GRPCCode) {
+		return appErr
+	}
+	redacted := *appErr
+	redacted.Detail = redactedInternalDetail
+	redacted.CausedBy = nil
+	return &redacted
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func TestRedactInternalDetails(t *testing.T) {
+	buf := captureLog(t)
+	SetRedactInternalDetails(true)
+	t.Cleanup(func() { SetRedactInternalDetails(false) })
+
+	const leaky = "query failed: dial tcp 10.0.0.7:5432"
+	info := &grpc.UnaryServerInfo{FullMethod: "/todo.v1.TodoService/GetTask"}
+	_, err := UnaryErrorInterceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, apperrors.NewInternal(leaky, "", errors.New("connection refused"))
+	})
+
+	st := status.Convert(err)
+	if strings.Contains(st.Message(), "10.0.0.7") || apperrors.FromGRPCStatus(st).Detail != redactedInternalDetail {
+		t.Errorf("client saw %q, want the generic detail", st.Message())
+	}
+	if !strings.Contains(buf.String(), leaky) || !strings.Contains(buf.String(), "connection refused") {
+		t.Errorf("log = %q, want the original detail and cause", buf.String())
+	}
+
+	// Client errors keep their detail
+	_, err = UnaryErrorInterceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, apperrors.NewNotFound("Task", "tasks/1", "")
+	})
+	if detail := apperrors.FromGRPCStatus(status.Convert(err)).Detail; detail == redactedInternalDetail {
+		t.Errorf("not found detail was redacted")
+	}
+
+	// Internal errors rendered straight to HTTP are redacted too
+	rec := httptest.NewRecorder()
+	writeAppErrorResponse(rec, httptest.NewRequest(http.MethodGet, "/v1/tasks/1", nil), apperrors.NewInternal(leaky, "", nil))
+	if strings.Contains(rec.Body.String(), "10.0.0.7") {
+		t.Errorf("HTTP body leaks the detail: %s", rec.Body.String())
+	}
+}
+
+func TestInternalDetailsShownWithoutRedaction(t *testing.T) {
+	appErr := apperrors.NewInternal("query failed", "", nil)
+	if got := redactForClient(appErr); got != appErr {
+		t.Errorf("redactForClient changed %v with redaction off", appErr)
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"context"
+	"path"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"google.golang.org/grpc"
+)
+
+// FeatureFlags maps a method name (e.g. "BatchCreateTasks") to whether it is
+// enabled. Methods without an entry are enabled.
+type FeatureFlags map[string]bool
+
+// Enabled reports whether the method may be called
+func (f FeatureFlags) Enabled(method string) bool {
+	enabled, ok := f[method]
+	return !ok || enabled
+}
+
+// FeatureFlagInterceptor rejects calls to disabled RPCs with FEATURE_DISABLED.
+// Gateway routes are proxied over gRPC and HTTP-only routes run through
+// InterceptedHTTPHandler, so this also gates their HTTP paths.
+func FeatureFlagInterceptor(flags FeatureFlags) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method := path.Base(info.FullMethod)
+		if flags.Enabled(method) {
+			return handler(ctx, req)
+		}
+
+		traceID := monitoring.TraceIDFromContext(ctx)
+		return nil, apperrors.NewFeatureDisabled(method, traceID)
+	}
+}
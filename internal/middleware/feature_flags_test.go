@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFeatureFlagInterceptorDisablesMethod(t *testing.T) {
+	interceptor := FeatureFlagInterceptor(FeatureFlags{"BatchCreateTasks": false, "DeleteTask": true})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	for _, tc := range []struct {
+		method   string
+		wantCode codes.Code
+		wantHTTP int
+	}{
+		{"BatchCreateTasks", codes.Unimplemented, http.StatusNotImplemented},
+		{"CreateTask", codes.OK, http.StatusOK},
+		{"DeleteTask", codes.OK, http.StatusOK},
+	} {
+		t.Run(tc.method, func(t *testing.T) {
+			info := &grpc.UnaryServerInfo{FullMethod: "/todo.v1.TodoService/" + tc.method}
+			// Chained the way main.go does, behind the error interceptor
+			resp, err := UnaryErrorInterceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, handler)
+			})
+
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("code = %v, want %v", got, tc.wantCode)
+			}
+			if err == nil {
+				if resp != "ok" {
+					t.Errorf("handler not called, resp = %v", resp)
+				}
+				return
+			}
+
+			rec := httptest.NewRecorder()
+			CustomHTTPError(context.Background(), nil, nil, rec, httptest.NewRequest(http.MethodPost, "/v1/tasks:batchCreate", nil), err)
+			if rec.Code != tc.wantHTTP {
+				t.Errorf("HTTP status = %d, want %d", rec.Code, tc.wantHTTP)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+)
+
+var (
+	trustedProxiesMu sync.RWMutex
+	trustedProxies   []netip.Prefix
+)
+
+// SetTrustedProxies lists the proxies, as IPs or CIDRs, whose X-Forwarded-Path
+// and X-Forwarded-Prefix headers are used for the instance of error bodies.
+// With none set the headers are ignored, since any client could send them.
+func SetTrustedProxies(proxies []string) error {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, p := range proxies {
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(p)
+			if addrErr != nil {
+				return fmt.Errorf("invalid trusted proxy %q: %w", p, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	trustedProxiesMu.Lock()
+	defer trustedProxiesMu.Unlock()
+	trustedProxies = prefixes
+	return nil
+}
+
+func isTrustedProxy(remoteAddr string) bool {
+	trustedProxiesMu.RLock()
+	defer trustedProxiesMu.RUnlock()
+	if len(trustedProxies) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// externalPath is the path the client used, which differs from r.URL.Path when
+// a trusted proxy rewrote it. X-Forwarded-Path gives the full original path;
+// X-Forwarded-Prefix gives a prefix the proxy stripped.
+func externalPath(r *http.Request) string {
+	if !isTrustedProxy(r.RemoteAddr) {
+		return r.URL.Path
+	}
+	if path, ok := forwardedPathHeader(r, "X-Forwarded-Path"); ok {
+		return path
+	}
+	if prefix, ok := forwardedPathHeader(r, "X-Forwarded-Prefix"); ok {
+		return strings.TrimSuffix(prefix, "/") + r.URL.Path
+	}
+	return r.URL.Path
+}
+
+// forwardedPathHeader reads the first, client-facing value of a forwarded
+// path header, ignoring values that are not plain absolute paths
+func forwardedPathHeader(r *http.Request, name string) (string, bool) {
+	value, _, _ := strings.Cut(r.Header.Get(name), ",")
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "/") || strings.ContainsAny(value, "?#") || strings.ContainsFunc(value, isControlRune) {
+		return "", false
+	}
+	return value, true
+}
+
+func isControlRune(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
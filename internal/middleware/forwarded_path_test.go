@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+)
+
+func TestErrorInstanceUsesForwardedPathFromTrustedProxies(t *testing.T) {
+	if err := SetTrustedProxies([]string{"10.0.0.0/8", "192.168.1.5"}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetTrustedProxies(nil)
+
+	for _, tc := range []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{"forwarded path", "10.1.2.3:4000", map[string]string{"X-Forwarded-Path": "/api/v1/tasks/42"}, "/api/v1/tasks/42"},
+		{"forwarded prefix", "192.168.1.5:4000", map[string]string{"X-Forwarded-Prefix": "/todo/"}, "/todo/v1/tasks/42"},
+		{"path wins over prefix", "10.1.2.3:4000", map[string]string{"X-Forwarded-Path": "/a/tasks/42", "X-Forwarded-Prefix": "/b"}, "/a/tasks/42"},
+		{"first of a chain", "10.1.2.3:4000", map[string]string{"X-Forwarded-Path": "/outer/tasks/42, /inner/tasks/42"}, "/outer/tasks/42"},
+		{"untrusted client", "203.0.113.7:4000", map[string]string{"X-Forwarded-Path": "/spoofed"}, "/v1/tasks/42"},
+		{"not a path", "10.1.2.3:4000", map[string]string{"X-Forwarded-Path": "https://evil.example/x"}, "/v1/tasks/42"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/tasks/42", nil)
+			req.RemoteAddr = tc.remoteAddr
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			CustomHTTPError(req.Context(), nil, nil, rec, req, apperrors.NewNotFound("Task", "42", "").ToGRPCStatus().Err())
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["instance"] != tc.want {
+				t.Errorf("instance = %v, want %s", body["instance"], tc.want)
+			}
+		})
+	}
+}
+
+func TestSetTrustedProxiesRejectsGarbage(t *testing.T) {
+	if err := SetTrustedProxies([]string{"not-an-ip"}); err == nil {
+		t.Error("SetTrustedProxies accepted an invalid entry")
+	}
+}
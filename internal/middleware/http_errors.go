@@ -3,15 +3,20 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"net/http"
 	"runtime/debug"
+	"strconv"
+	"strings"
 	"time"
 
 	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
 	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
-	"github.com/google/uuid"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
-	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -19,12 +24,10 @@ import (
 // HTTPErrorHandler handles errors for HTTP endpoints
 func HTTPErrorHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Add trace ID to context
-		traceID := r.Header.Get("X-Trace-ID")
-		if traceID == "" {
-			traceID = uuid.New().String()
-		}
-		ctx := context.WithValue(r.Context(), "traceID", traceID)
+		// Continue any incoming W3C trace context, then add trace ID to context
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		traceID := resolveTraceID(ctx, r)
+		ctx = monitoring.WithTraceID(ctx, traceID)
 		r = r.WithContext(ctx)
 
 		// Create response wrapper to intercept errors
@@ -76,7 +79,8 @@ func handlePanic(w *responseWriter, recovered interface{}) {
 	debug.PrintStack()
 
 	appErr := apperrors.NewInternal("An unexpected error occurred. Please try again later.", w.traceID, nil)
-	writeErrorResponse(w, appErr)
+	runErrorHooks(w.request.Context(), appErr)
+	writeErrorResponse(w, w.request, appErr)
 }
 
 // CustomHTTPError handles gRPC gateway error responses
@@ -84,125 +88,152 @@ func CustomHTTPError(ctx context.Context, mux *runtime.ServeMux,
 	marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
 
 	// Extract trace ID
-	traceID := r.Header.Get("X-Trace-ID")
-	if traceID == "" {
-		if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
-			traceID = span.SpanContext().TraceID().String()
-		} else {
-			traceID = uuid.New().String()
-		}
-	}
+	traceID := resolveTraceID(ctx, r)
 
-	// Convert gRPC error to HTTP response
+	// Convert gRPC error back into an AppError
 	st, _ := status.FromError(err)
+	appErr := apperrors.FromGRPCStatus(st)
 
-	// Check if we have our custom error detail in status details
-	for _, detail := range st.Details() {
-		if errorDetail, ok := detail.(*errorspb.ErrorDetail); ok {
-			// Update the error detail with current request context
-			errorDetail.TraceId = traceID
-			errorDetail.Instance = r.URL.Path
-
-			// Convert to JSON and write response
-			w.Header().Set("Content-Type", "application/problem+json")
-			w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
-
-			// Create a simplified JSON response that matches RFC 7807
-			response := map[string]interface{}{
-				"type":      getTypeForCode(errorDetail.Code),
-				"title":     errorDetail.Title,
-				"status":    runtime.HTTPStatusFromCode(st.Code()),
-				"detail":    errorDetail.Detail,
-				"instance":  errorDetail.Instance,
-				"traceId":   errorDetail.TraceId,
-				"timestamp": errorDetail.Timestamp,
-			}
+	// No ErrorDetail means the status never came from our service; an
+	// Unavailable here is the gateway failing to reach the gRPC backend.
+	if st.Code() == codes.Unavailable && !hasErrorDetail(st) {
+		appErr = apperrors.NewBadGateway(traceID)
+	}
 
-			// Add field violations if present
-			if len(errorDetail.FieldViolations) > 0 {
-				violations := make([]map[string]interface{}, len(errorDetail.FieldViolations))
-				for i, fv := range errorDetail.FieldViolations {
-					violations[i] = map[string]interface{}{
-						"field":   fv.Field,
-						"code":    fv.Code,
-						"message": fv.Description,
-					}
-				}
-				response["errors"] = violations
-			}
+	// The gateway flattens body decode failures into a bare InvalidArgument
+	if st.Code() == codes.InvalidArgument && !hasErrorDetail(st) && strings.HasPrefix(st.Message(), malformedJSONPrefix) {
+		appErr = apperrors.NewMalformedBody(strings.TrimPrefix(st.Message(), malformedJSONPrefix), traceID)
+	}
 
-			// Add extensions if present
-			if len(errorDetail.Extensions) > 0 {
-				extensions := make(map[string]interface{})
-				for k, v := range errorDetail.Extensions {
-					// Convert Any to JSON
-					if jsonBytes, err := protojson.Marshal(v); err == nil {
-						var jsonData interface{}
-						if err := json.Unmarshal(jsonBytes, &jsonData); err == nil {
-							extensions[k] = jsonData
-						}
-					}
-				}
-				if len(extensions) > 0 {
-					response["extensions"] = extensions
-				}
-			}
+	// Update the error with current request context
+	appErr.TraceID = traceID
+	// Statuses with an ErrorDetail passed through UnaryErrorInterceptor, which
+	// already ran the hooks; only errors raised by the gateway itself remain
+	if !hasErrorDetail(st) {
+		runErrorHooks(ctx, appErr)
+	}
+	writeAppErrorResponse(w, r, appErr)
+}
 
-			if err := json.NewEncoder(w).Encode(response); err != nil {
-				http.Error(w, `{"error": "Failed to encode error response"}`, 500)
-			}
-			return
+// Helper functions
+
+// resolveTraceID reuses the ID HTTPErrorHandler stored for this request, and
+// only resolves a new one for requests that never passed through it
+func resolveTraceID(ctx context.Context, r *http.Request) string {
+	if traceID, ok := ctx.Value("traceID").(string); ok && traceID != "" {
+		return traceID
+	}
+	return monitoring.ResolveTraceID(ctx, r.Header.Get(monitoring.TraceIDHeader))
+}
+
+func hasErrorDetail(st *status.Status) bool {
+	for _, detail := range st.Details() {
+		if _, ok := detail.(*errorspb.ErrorDetail); ok {
+			return true
 		}
 	}
+	return false
+}
 
-	// Fallback: create new error response
-	fallbackErr := apperrors.NewInternal(st.Message(), traceID, nil)
-	fallbackErr.GRPCCode = st.Code()
-	writeAppErrorResponse(w, fallbackErr, r.URL.Path)
+// httpStatusFor maps a gRPC code to an HTTP status, allowing app codes that
+// have no direct gRPC equivalent to override the default mapping.
+func httpStatusFor(code codes.Code, appCode string) int {
+	switch appCode {
+	case errorspb.AppErrorCode_UPSTREAM_UNAVAILABLE.String():
+		return http.StatusBadGateway
+	case errorspb.AppErrorCode_METHOD_NOT_ALLOWED.String():
+		return http.StatusMethodNotAllowed
+	case errorspb.AppErrorCode_RESPONSE_TOO_LARGE.String():
+		return http.StatusRequestEntityTooLarge
+	case errorspb.AppErrorCode_INCONSISTENT_STATE.String():
+		return http.StatusConflict
+	default:
+		return runtime.HTTPStatusFromCode(code)
+	}
 }
 
-// Helper functions
 func getTypeForCode(code string) string {
 	switch code {
 	case errorspb.AppErrorCode_VALIDATION_FAILED.String():
 		return "https://api.example.com/errors/validation-failed"
+	case errorspb.AppErrorCode_INVALID_ARGUMENT.String():
+		return "https://api.example.com/errors/invalid-argument"
+	case errorspb.AppErrorCode_INVALID_FILTER.String():
+		return "https://api.example.com/errors/invalid-filter"
+	case errorspb.AppErrorCode_INVALID_FORMAT.String():
+		return "https://api.example.com/errors/invalid-format"
 	case errorspb.AppErrorCode_RESOURCE_NOT_FOUND.String():
 		return "https://api.example.com/errors/resource-not-found"
 	case errorspb.AppErrorCode_RESOURCE_CONFLICT.String():
 		return "https://api.example.com/errors/resource-conflict"
+	case errorspb.AppErrorCode_METHOD_NOT_ALLOWED.String():
+		return "https://api.example.com/errors/method-not-allowed"
+	case errorspb.AppErrorCode_NOT_IMPLEMENTED.String():
+		return "https://api.example.com/errors/not-implemented"
+	case errorspb.AppErrorCode_INCONSISTENT_STATE.String():
+		return "https://api.example.com/errors/inconsistent-state"
 	case errorspb.AppErrorCode_PERMISSION_DENIED.String():
 		return "https://api.example.com/errors/permission-denied"
+	case errorspb.AppErrorCode_REPLAYED_REQUEST.String():
+		return "https://api.example.com/errors/replayed-request"
 	case errorspb.AppErrorCode_INTERNAL_ERROR.String():
 		return "https://api.example.com/errors/internal-error"
+	case errorspb.AppErrorCode_RATE_LIMIT_EXCEEDED.String():
+		return "https://api.example.com/errors/rate-limit-exceeded"
 	case errorspb.AppErrorCode_SERVICE_UNAVAILABLE.String():
 		return "https://api.example.com/errors/service-unavailable"
+	case errorspb.AppErrorCode_UPSTREAM_UNAVAILABLE.String():
+		return "https://api.example.com/errors/upstream-unavailable"
+	case errorspb.AppErrorCode_TIMEOUT.String():
+		return "https://api.example.com/errors/timeout"
+	case errorspb.AppErrorCode_CANCELED.String():
+		return "https://api.example.com/errors/canceled"
+	case errorspb.AppErrorCode_FEATURE_DISABLED.String():
+		return "https://api.example.com/errors/feature-disabled"
+	case errorspb.AppErrorCode_READ_ONLY.String():
+		return "https://api.example.com/errors/read-only"
+	case errorspb.AppErrorCode_RESPONSE_TOO_LARGE.String():
+		return "https://api.example.com/errors/response-too-large"
 	default:
 		return "https://api.example.com/errors/unknown"
 	}
 }
 
-func writeErrorResponse(w http.ResponseWriter, err error) {
-	if appErr, ok := err.(*apperrors.AppError); ok {
-		writeAppErrorResponse(w, appErr, "")
-	} else {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	appErr, ok := err.(*apperrors.AppError)
+	if !ok {
+		appErr = apperrors.NewInternal("An unexpected error occurred. Please try again later.", resolveTraceID(r.Context(), r), err)
 	}
+	writeAppErrorResponse(w, r, appErr)
 }
 
-func writeAppErrorResponse(w http.ResponseWriter, appErr *apperrors.AppError, instance string) {
-	statusCode := runtime.HTTPStatusFromCode(appErr.GRPCCode)
+// writeAppErrorResponse renders appErr as problem+json. Every response names
+// the request it answers: instance is the request path, as the client sent it
+// to any trusted proxy, unless the error set its own, and method is the HTTP
+// verb.
+func writeAppErrorResponse(w http.ResponseWriter, r *http.Request, appErr *apperrors.AppError) {
+	appErr = redactForClient(appErr)
+	statusCode := httpStatusFor(appErr.GRPCCode, appErr.AppCode.String())
+	noteErrorCode(r.Context(), appErr.AppCode.String())
+
+	instance := appErr.Instance
+	if instance == "" {
+		instance = externalPath(r)
+	}
 
 	response := map[string]interface{}{
 		"type":      getTypeForCode(appErr.AppCode.String()),
 		"title":     appErr.Title,
 		"status":    statusCode,
 		"detail":    appErr.Detail,
-		"traceId":   appErr.TraceID,
+		"instance":  instance,
+		"method":    r.Method,
 		"timestamp": time.Now(),
+		"version":   apperrors.SchemaVersion,
 	}
-
-	if instance != "" {
-		response["instance"] = instance
+	response[errorFieldName("traceId", "trace_id")] = appErr.TraceID
+	if appErr.RetryAfter > 0 {
+		response[errorFieldName("retryAfter", "retry_after")] = retryAfterSeconds(appErr.RetryAfter)
 	}
 
 	if len(appErr.FieldViolations) > 0 {
@@ -213,11 +244,43 @@ func writeAppErrorResponse(w http.ResponseWriter, appErr *apperrors.AppError, in
 				"code":    fv.Code,
 				"message": fv.Description,
 			}
+			if fv.RejectedValue != "" {
+				violations[i][errorFieldName("rejectedValue", "rejected_value")] = fv.RejectedValue
+			}
 		}
 		response["errors"] = violations
 	}
 
+	// Add extensions if present
+	if len(appErr.Extensions) > 0 {
+		extensions := make(map[string]interface{})
+		marshaler := protojson.MarshalOptions{UseProtoNames: useSnakeCaseErrorFields()}
+		for k, v := range appErr.Extensions {
+			// Convert Any to JSON
+			if jsonBytes, err := marshaler.Marshal(v); err == nil {
+				var jsonData interface{}
+				if err := json.Unmarshal(jsonBytes, &jsonData); err == nil {
+					extensions[k] = jsonData
+				}
+			}
+		}
+		if len(extensions) > 0 {
+			response["extensions"] = extensions
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/problem+json")
+	if appErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(appErr.RetryAfter)))
+	}
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(response)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, `{"error": "Failed to encode error response"}`, 500)
+	}
+}
+
+// retryAfterSeconds converts a retry delay to the whole seconds Retry-After
+// uses, rounding up so clients never retry early
+func retryAfterSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
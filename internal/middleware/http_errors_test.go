@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestHTTPErrorHandlerContinuesW3CTrace(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	for _, tc := range []struct {
+		name     string
+		headers  map[string]string
+		want     string
+		wantSpan bool
+	}{
+		{"traceparent", map[string]string{"traceparent": traceparent}, "4bf92f3577b34da6a3ce929d0e0e4736", true},
+		{"X-Trace-ID wins over traceparent", map[string]string{"traceparent": traceparent, "X-Trace-ID": "custom-1"}, "custom-1", false},
+		{"X-Trace-ID fallback", map[string]string{"X-Trace-ID": "custom-1"}, "custom-1", false},
+		{"malformed traceparent", map[string]string{"traceparent": "00-zz-00-01", "X-Trace-ID": "custom-2"}, "custom-2", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var spanTraceID, traceID string
+			handler := HTTPErrorHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+					spanTraceID = sc.TraceID().String()
+				}
+				traceID, _ = r.Context().Value("traceID").(string)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/tasks", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if traceID != tc.want {
+				t.Errorf("trace ID = %q, want %q", traceID, tc.want)
+			}
+			if tc.wantSpan && spanTraceID != tc.want {
+				t.Errorf("span trace ID = %q, want %q", spanTraceID, tc.want)
+			}
+		})
+	}
+
+	t.Run("generated when absent", func(t *testing.T) {
+		var traceID string
+		handler := HTTPErrorHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceID, _ = r.Context().Value("traceID").(string)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/tasks", nil))
+		if traceID == "" {
+			t.Error("no trace ID generated")
+		}
+	})
+}
+
+func TestErrorBodiesNameInstanceAndMethod(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		method  string
+		path    string
+		handler http.Handler
+	}{
+		{"panic", http.MethodPost, "/v1/tasks", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})},
+		{"gateway", http.MethodDelete, "/v1/tasks/42", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			CustomHTTPError(r.Context(), nil, nil, w, r, apperrors.NewNotFound("Task", "42", "").ToGRPCStatus().Err())
+		})},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			HTTPErrorHandler(tc.handler).ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v; %s", err, rec.Body.String())
+			}
+			if body["instance"] != tc.path || body["method"] != tc.method {
+				t.Errorf("instance/method = %v/%v, want %s/%s", body["instance"], body["method"], tc.path, tc.method)
+			}
+		})
+	}
+}
+
+func TestErrorBodiesCarrySchemaVersion(t *testing.T) {
+	for name, handler := range map[string]http.Handler{
+		"panic": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}),
+		"gateway": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			CustomHTTPError(r.Context(), nil, nil, w, r, apperrors.NewNotFound("Task", "42", "").ToGRPCStatus().Err())
+		}),
+	} {
+		rec := httptest.NewRecorder()
+		HTTPErrorHandler(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tasks/42", nil))
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: decode body: %v", name, err)
+		}
+		if body["version"] != apperrors.SchemaVersion {
+			t.Errorf("%s: version = %v, want %q", name, body["version"], apperrors.SchemaVersion)
+		}
+	}
+}
+
+func TestGatewayErrorsCarryRetryAfter(t *testing.T) {
+	err := apperrors.NewServiceUnavailable("database", "down", 1500*time.Millisecond, "").ToGRPCStatus().Err()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		CustomHTTPError(r.Context(), nil, nil, w, r, err)
+	})
+
+	rec := httptest.NewRecorder()
+	HTTPErrorHandler(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tasks", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+	// 1.5s rounds up so clients never retry early
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["retryAfter"] != float64(2) {
+		t.Errorf("retryAfter = %v, want 2", body["retryAfter"])
+	}
+}
+
+// decodeProblem checks that rec holds a problem+json body and decodes it
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("Content-Type = %q, want application/problem+json", ct)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+func TestGatewayReturnsBadGatewayWhenBackendIsDown(t *testing.T) {
+	// Reserve a port, then free it so nothing is listening there
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	mux := runtime.NewServeMux(runtime.WithErrorHandler(CustomHTTPError))
+	if err := todopb.RegisterTodoServiceHandler(context.Background(), mux, conn); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	HTTPErrorHandler(mux).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tasks/abc", nil))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want 502; body %s", rec.Code, rec.Body.String())
+	}
+	body := decodeProblem(t, rec)
+	if body["type"] != "https://api.example.com/errors/upstream-unavailable" {
+		t.Errorf("type = %v, want upstream-unavailable", body["type"])
+	}
+	if body["status"] != float64(http.StatusBadGateway) {
+		t.Errorf("status field = %v, want 502", body["status"])
+	}
+	if body["traceId"] == "" || body["traceId"] == nil {
+		t.Error("traceId missing")
+	}
+}
+
+func TestProblemEchoesTitleButNotSSN(t *testing.T) {
+	title := &errorspb.FieldViolation{Field: "title", Code: "TOO_LONG", Description: "Title is too long"}
+	apperrors.EchoRejectedValue(title, "Quarterly planning")
+	ssn := &errorspb.FieldViolation{Field: "ssn", Code: "INVALID_FORMAT", Description: "SSN must be NNN-NN-NNNN"}
+	apperrors.EchoRejectedValue(ssn, "123-45-678")
+
+	rec := httptest.NewRecorder()
+	appErr := apperrors.NewValidationFailed([]*errorspb.FieldViolation{title, ssn}, "trace-echo-1")
+	writeAppErrorResponse(rec, httptest.NewRequest(http.MethodPost, "/v1/tasks", nil), appErr)
+
+	body := decodeProblem(t, rec)
+	violations, _ := body["errors"].([]interface{})
+	if len(violations) != 2 {
+		t.Fatalf("errors = %v, want 2 violations", body["errors"])
+	}
+	if got := violations[0].(map[string]interface{})["rejectedValue"]; got != "Quarterly planning" {
+		t.Errorf("title rejectedValue = %v, want the submitted title", got)
+	}
+	if got, ok := violations[1].(map[string]interface{})["rejectedValue"]; ok {
+		t.Errorf("ssn rejectedValue = %v, want it omitted", got)
+	}
+	if strings.Contains(rec.Body.String(), "123-45-678") {
+		t.Errorf("SSN leaked into the response: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+)
+
+type httpMetricsKey struct{}
+
+// httpMetricsState carries the app code of an error response back out to
+// HTTPMetricsMiddleware, past the response writers wrapped in between
+type httpMetricsState struct {
+	errorCode string
+}
+
+// HTTPMetricsMiddleware records request count, latency and status for every
+// gateway request, including errors raised by the gateway itself (routing,
+// marshaling) that never reach the gRPC server. route maps a request path to
+// its template, e.g. /v1/tasks/{id}.
+func HTTPMetricsMiddleware(route func(path string) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		state := &httpMetricsState{}
+		r = r.WithContext(context.WithValue(r.Context(), httpMetricsKey{}, state))
+
+		wrapped := &metricsResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(wrapped, r)
+
+		template := route(r.URL.Path)
+		monitoring.RecordHTTPRequest(r.Context(), r.Method, template, wrapped.statusCode, time.Since(start))
+		if wrapped.statusCode >= http.StatusBadRequest {
+			errorType := state.errorCode
+			if errorType == "" {
+				errorType = "HTTP_ERROR"
+			}
+			monitoring.RecordError(r.Context(), errorType, wrapped.statusCode, r.Method, template)
+		}
+	})
+}
+
+// noteErrorCode tells HTTPMetricsMiddleware which app code a response carries
+func noteErrorCode(ctx context.Context, code string) {
+	if state, ok := ctx.Value(httpMetricsKey{}).(*httpMetricsState); ok {
+		state.errorCode = code
+	}
+}
+
+type metricsResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (w *metricsResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.statusCode = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
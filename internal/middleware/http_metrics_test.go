@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// counterValue reads a counter from the default Prometheus registry; a series
+// that was never incremented reads as zero
+func counterValue(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if want, ok := labels[label.GetName()]; ok && want != label.GetValue() {
+					continue metrics
+				}
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestHTTPMetricsCountGatewayErrors(t *testing.T) {
+	// An empty gateway mux answers every path with its own routing 404
+	mux := runtime.NewServeMux(runtime.WithErrorHandler(CustomHTTPError))
+	handler := HTTPErrorHandler(HTTPMetricsMiddleware(func(string) string { return "unmatched" }, mux))
+
+	requests := map[string]string{"method": http.MethodGet, "route": "unmatched", "status_code": "404"}
+	errorLabels := map[string]string{"error_type": "RESOURCE_NOT_FOUND", "status_code": "404", "method": http.MethodGet, "endpoint": "unmatched"}
+	requestsBefore := counterValue(t, "todo_api_http_requests_total", requests)
+	errorsBefore := counterValue(t, "todo_api_errors_total", errorLabels)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/nowhere", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+
+	if got := counterValue(t, "todo_api_http_requests_total", requests) - requestsBefore; got != 1 {
+		t.Errorf("http requests counter grew by %v, want 1", got)
+	}
+	if got := counterValue(t, "todo_api_errors_total", errorLabels) - errorsBefore; got != 1 {
+		t.Errorf("errors counter grew by %v, want 1", got)
+	}
+}
+
+func gaugeValue(t *testing.T, name string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() == name && len(family.GetMetric()) > 0 {
+			return family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// HTTPOnlyHandler serves a route that has no RPC, such as a file download.
+// It writes successful responses itself and returns errors to be rendered
+// like any gateway error.
+type HTTPOnlyHandler func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) error
+
+// ChainUnaryInterceptors composes interceptors into one, outermost first, the
+// way grpc.ChainUnaryInterceptor does for a server
+func ChainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// InterceptedHTTPHandler serves an HTTP-only route through the same
+// interceptor chain as the gRPC server, as if it were the RPC fullMethod
+// (e.g. "/todo.v1.TodoService/ExportCSV"). Auth, feature flags, read-only
+// mode, limits, logging and error handling then apply to it exactly as they
+// do to gateway routes. Headers reach the chain as incoming metadata, the way
+// the gateway forwards them over gRPC. A nil interceptor serves the route
+// directly, for routes that are deliberately exempt.
+func InterceptedHTTPHandler(mux *runtime.ServeMux, interceptor grpc.UnaryServerInterceptor, fullMethod string, handler HTTPOnlyHandler) runtime.HandlerFunc {
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		if interceptor == nil {
+			if err := handler(w, r, pathParams); err != nil {
+				writeHTTPOnlyError(r.Context(), mux, w, r, err)
+			}
+			return
+		}
+
+		ctx, err := runtime.AnnotateIncomingContext(r.Context(), mux, r, fullMethod)
+		if err != nil {
+			writeHTTPOnlyError(r.Context(), mux, w, r, err)
+			return
+		}
+
+		_, err = interceptor(ctx, &emptypb.Empty{}, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			return nil, handler(w, r.WithContext(ctx), pathParams)
+		})
+		if err != nil {
+			writeHTTPOnlyError(ctx, mux, w, r, err)
+		}
+	}
+}
+
+// writeHTTPOnlyError renders an error the way the gateway renders errors
+// coming back over gRPC
+func writeHTTPOnlyError(ctx context.Context, mux *runtime.ServeMux, w http.ResponseWriter, r *http.Request, err error) {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		err = appErr.ToGRPCStatus().Err()
+	}
+	CustomHTTPError(ctx, mux, nil, w, r, err)
+}
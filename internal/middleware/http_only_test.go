@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+)
+
+// serveHTTPOnly runs handler as fullMethod behind interceptors, chained the
+// way main.go chains them for the gateway's HTTP-only routes
+func serveHTTPOnly(t *testing.T, fullMethod string, handler HTTPOnlyHandler, interceptors ...grpc.UnaryServerInterceptor) *httptest.ResponseRecorder {
+	t.Helper()
+	chain := ChainUnaryInterceptors(append([]grpc.UnaryServerInterceptor{
+		UnaryTraceIDInterceptor,
+		AuthInterceptor(map[string]string{"bob": "acme"}),
+		UnaryErrorInterceptor,
+	}, interceptors...)...)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tasks:csv", nil)
+	req.Header.Set("Authorization", "Bearer bob")
+	rec := httptest.NewRecorder()
+	InterceptedHTTPHandler(runtime.NewServeMux(), chain, fullMethod, handler)(rec, req, nil)
+	return rec
+}
+
+func okHTTPOnly(called *bool) HTTPOnlyHandler {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+		*called = true
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func TestInterceptedHTTPHandlerAppliesFeatureFlags(t *testing.T) {
+	flags := FeatureFlagInterceptor(FeatureFlags{"ExportCSV": false})
+
+	var called bool
+	rec := serveHTTPOnly(t, "/todo.v1.TodoService/ExportCSV", okHTTPOnly(&called), flags)
+	if called || rec.Code != http.StatusNotImplemented {
+		t.Errorf("disabled route: called = %v, status = %d; want 501 without calling the handler", called, rec.Code)
+	}
+
+	rec = serveHTTPOnly(t, "/todo.v1.TodoService/ExportCalendar", okHTTPOnly(&called), flags)
+	if !called || rec.Code != http.StatusOK {
+		t.Errorf("enabled route: called = %v, status = %d; want 200", called, rec.Code)
+	}
+}
+
+func TestInterceptedHTTPHandlerAppliesReadOnly(t *testing.T) {
+	readOnly := ReadOnlyInterceptor(true, 30*time.Second)
+
+	var called bool
+	rec := serveHTTPOnly(t, todopb.TodoService_CreateTask_FullMethodName, okHTTPOnly(&called), readOnly)
+	if called || rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("mutating route: called = %v, status = %d; want 503 without calling the handler", called, rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+
+	rec = serveHTTPOnly(t, "/todo.v1.TodoService/ExportCSV", okHTTPOnly(&called), readOnly)
+	if !called || rec.Code != http.StatusOK {
+		t.Errorf("read route: called = %v, status = %d; want 200", called, rec.Code)
+	}
+}
+
+func TestInterceptedHTTPHandlerResolvesCaller(t *testing.T) {
+	var user, tenant interface{}
+	rec := serveHTTPOnly(t, "/todo.v1.TodoService/ExportCSV", func(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+		user, tenant = r.Context().Value("user"), r.Context().Value("tenant")
+		return nil
+	})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	if user != "bob" || tenant != "acme" {
+		t.Errorf("caller = %v/%v, want bob/acme from the Authorization header", user, tenant)
+	}
+}
+
+func TestInterceptedHTTPHandlerRendersHandlerErrors(t *testing.T) {
+	rec := serveHTTPOnly(t, "/todo.v1.TodoService/GetTaskHistory", func(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+		return apperrors.NewNotFound("Task", "tasks/1", "")
+	})
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404; body %s", rec.Code, rec.Body.String())
+	}
+	if problem := decodeProblem(t, rec); problem["traceId"] == "" || problem["traceId"] == nil {
+		t.Errorf("problem %v has no traceId; the chain's trace ID was not applied", problem)
+	}
+}
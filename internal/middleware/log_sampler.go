@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// errorLogSampler keeps a misbehaving client from flooding the logs with the
+// same error over and over
+var errorLogSampler = newLogSampler(10, time.Minute)
+
+// logSampler lets the first limit occurrences of each key through per window
+// and counts the rest. Suppressed counts are summarized when the window rolls
+// over.
+type logSampler struct {
+	mu          sync.Mutex
+	limit       int
+	window      time.Duration
+	windowStart time.Time
+	counts      map[string]int
+}
+
+func newLogSampler(limit int, window time.Duration) *logSampler {
+	return &logSampler{
+		limit:       limit,
+		window:      window,
+		windowStart: time.Now(),
+		counts:      make(map[string]int),
+	}
+}
+
+// allow records an occurrence of key and reports whether it should be logged
+func (s *logSampler) allow(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := time.Now(); now.Sub(s.windowStart) >= s.window {
+		s.flush()
+		s.windowStart = now
+	}
+
+	s.counts[key]++
+	return s.counts[key] <= s.limit
+}
+
+// flush logs a summary of suppressed occurrences and starts a new window.
+// Callers must hold s.mu.
+func (s *logSampler) flush() {
+	for key, count := range s.counts {
+		if suppressed := count - s.limit; suppressed > 0 {
+			log.Printf("INFO: suppressed %d repeated %s errors in the last %s", suppressed, key, s.window)
+		}
+	}
+	s.counts = make(map[string]int)
+}
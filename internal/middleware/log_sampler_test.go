@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/grpc"
+)
+
+// captureLog redirects the standard logger for the rest of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestRepeatedErrorLogsAreBounded(t *testing.T) {
+	buf := captureLog(t)
+	errorLogSampler = newLogSampler(10, time.Hour)
+	t.Cleanup(func() { errorLogSampler = newLogSampler(10, time.Minute) })
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/todo.v1.TodoService/CreateTask"}
+	failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, apperrors.NewValidationFailed([]*errorspb.FieldViolation{{Field: "title"}}, "")
+	}
+	for i := 0; i < 500; i++ {
+		UnaryErrorInterceptor(context.Background(), nil, info, failing)
+	}
+
+	if lines := strings.Count(buf.String(), "VALIDATION_FAILED"); lines != 10 {
+		t.Errorf("logged %d lines for 500 identical errors, want 10", lines)
+	}
+}
+
+func TestLogSamplerKeysAndSummary(t *testing.T) {
+	buf := captureLog(t)
+	s := newLogSampler(2, 20*time.Millisecond)
+
+	var allowed int
+	for i := 0; i < 5; i++ {
+		if s.allow("GetTask NOT_FOUND") {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("allowed %d of 5, want 2", allowed)
+	}
+	if !s.allow("GetTask PERMISSION_DENIED") {
+		t.Error("a different key was sampled by another key's count")
+	}
+
+	// The next occurrence after the window rolls over is logged again and
+	// the suppressed ones are summarized
+	time.Sleep(30 * time.Millisecond)
+	if !s.allow("GetTask NOT_FOUND") {
+		t.Error("not allowed after the window reset")
+	}
+	if !strings.Contains(buf.String(), "suppressed 3 repeated GetTask NOT_FOUND errors") {
+		t.Errorf("summary missing from log: %q", buf.String())
+	}
+}
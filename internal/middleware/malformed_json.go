@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// malformedJSONPrefix starts every decode error MalformedJSONMarshaler returns,
+// so CustomHTTPError can tell them apart from other InvalidArgument statuses
+const malformedJSONPrefix = "malformed JSON body"
+
+// protojsonPosition matches the position protojson puts in its errors
+var protojsonPosition = regexp.MustCompile(`^proto: (?:syntax error )?\(line (\d+):(\d+)\): `)
+
+// MalformedJSONMarshaler wraps the gateway marshaler so request bodies that
+// fail to decode report where they went wrong. The gateway turns decode errors
+// into an InvalidArgument status carrying only the message, which
+// CustomHTTPError renders as a 400 INVALID_FORMAT.
+type MalformedJSONMarshaler struct {
+	runtime.Marshaler
+}
+
+// NewDecoder decodes with the wrapped marshaler, keeping the bytes read so a
+// syntax error's offset can be turned into a line and column
+func (m *MalformedJSONMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	var read bytes.Buffer
+	d := m.Marshaler.NewDecoder(io.TeeReader(r, &read))
+	return runtime.DecoderFunc(func(v interface{}) error {
+		err := d.Decode(v)
+		if err == nil || err == io.EOF {
+			// An empty body is io.EOF, which the gateway accepts
+			return err
+		}
+		return malformedJSON(read.Bytes(), err)
+	})
+}
+
+// malformedJSON describes a decode failure, with its position when known
+func malformedJSON(body []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &syntaxErr):
+		// Offset counts the bytes read up to and including the bad one
+		line, column := lineColumn(body, max(int(syntaxErr.Offset)-1, 0))
+		return fmt.Errorf("%s at line %d, column %d: %s", malformedJSONPrefix, line, column, syntaxErr.Error())
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		line, column := lineColumn(body, len(body))
+		return fmt.Errorf("%s at line %d, column %d: unexpected end of input", malformedJSONPrefix, line, column)
+	}
+
+	msg := err.Error()
+	if m := protojsonPosition.FindStringSubmatch(msg); m != nil {
+		return fmt.Errorf("%s at line %s, column %s: %s", malformedJSONPrefix, m[1], m[2], strings.TrimPrefix(msg, m[0]))
+	}
+	return fmt.Errorf("%s: %s", malformedJSONPrefix, strings.TrimPrefix(msg, "proto: "))
+}
+
+// lineColumn converts a 0-based byte index into body to a 1-based line and
+// column
+func lineColumn(body []byte, index int) (int, int) {
+	if index > len(body) {
+		index = len(body)
+	}
+	before := body[:index]
+	return bytes.Count(before, []byte("\n")) + 1, index - bytes.LastIndexByte(before, '\n')
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+func TestMalformedJSONBodiesAreStructured400s(t *testing.T) {
+	mux := runtime.NewServeMux(
+		runtime.WithErrorHandler(CustomHTTPError),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &MalformedJSONMarshaler{Marshaler: &runtime.JSONPb{}}),
+	)
+	if err := todopb.RegisterTodoServiceHandlerServer(context.Background(), mux, envelopeTestService{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		body string
+		want string
+	}{
+		{"truncated", `{"task": {"title": "Buy`, "at line 1, column"},
+		{"bad token on second line", "{\"task\": {\n  \"title\": x}}", "at line 2, column"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/tasks", strings.NewReader(tc.body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400; body %s", rec.Code, rec.Body.String())
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["type"] != "https://api.example.com/errors/invalid-format" {
+				t.Errorf("type = %v, want invalid-format", body["type"])
+			}
+			if detail, _ := body["detail"].(string); !strings.Contains(detail, tc.want) {
+				t.Errorf("detail = %q, want it to contain %q", detail, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"google.golang.org/grpc"
+)
+
+// mutatingMethods are the RPCs blocked while the service is read-only
+var mutatingMethods = map[string]bool{
+	todopb.TodoService_CreateTask_FullMethodName:             true,
+	todopb.TodoService_UpdateTask_FullMethodName:             true,
+	todopb.TodoService_DeleteTask_FullMethodName:             true,
+	todopb.TodoService_BatchCreateTasks_FullMethodName:       true,
+	todopb.TodoService_UndoLastOperation_FullMethodName:      true,
+	todopb.TodoService_RenameTag_FullMethodName:              true,
+	todopb.TodoService_ReassignUserTasks_FullMethodName:      true,
+	todopb.TodoService_CreateTaskTemplate_FullMethodName:     true,
+	todopb.TodoService_CreateTaskFromTemplate_FullMethodName: true,
+}
+
+// ReadOnlyInterceptor rejects writes with a READ_ONLY error during
+// maintenance while letting reads through. Gateway routes are proxied over
+// gRPC, so this also covers their HTTP paths.
+func ReadOnlyInterceptor(enabled bool, retryAfter time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !enabled || !mutatingMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		traceID := monitoring.TraceIDFromContext(ctx)
+		return nil, apperrors.NewReadOnly(retryAfter, traceID)
+	}
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"github.com/bhatti/todo-api-errors/internal/service"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// newTestGateway serves todoService over an in-memory gRPC connection behind
+// the gateway, with the interceptors a template request passes through
+func newTestGateway(t *testing.T, todoService todopb.TodoServiceServer, tenantsByUser map[string]string, readOnly bool) http.Handler {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		UnaryTraceIDInterceptor,
+		AuthInterceptor(tenantsByUser),
+		UnaryErrorInterceptor,
+		ReadOnlyInterceptor(readOnly, 30*time.Second),
+	))
+	todopb.RegisterTodoServiceServer(server, todoService)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	mux := runtime.NewServeMux(runtime.WithErrorHandler(CustomHTTPError))
+	if err := todopb.RegisterTodoServiceHandler(context.Background(), mux, conn); err != nil {
+		t.Fatal(err)
+	}
+	return HTTPErrorHandler(mux)
+}
+
+func TestReadOnlyBlocksTemplateWritesThroughGateway(t *testing.T) {
+	todoService, err := service.NewTodoService(repository.NewInMemoryRepository(false))
+	if err != nil {
+		t.Fatalf("NewTodoService: %v", err)
+	}
+	ctx := context.WithValue(context.WithValue(context.Background(), "user", "bob"), "tenant", "acme")
+	tmpl, err := todoService.CreateTaskTemplate(ctx, &todopb.CreateTaskTemplateRequest{
+		Template: &todopb.TaskTemplate{TitlePattern: "Standup {date}"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTaskTemplate: %v", err)
+	}
+	handler := newTestGateway(t, todoService, map[string]string{"bob": "acme"}, true)
+
+	for _, tc := range []struct {
+		name, path, body string
+	}{
+		{"create template", "/v1/taskTemplates", `{"title_pattern":"Retro"}`},
+		{"task from template", "/v1/tasks:fromTemplate", `{"template":"` + tmpl.Name + `"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Authorization", "Bearer bob")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusServiceUnavailable {
+				t.Fatalf("status = %d, want 503; body %s", rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Retry-After"); got != "30" {
+				t.Errorf("Retry-After = %q, want 30", got)
+			}
+			body := decodeProblem(t, rec)
+			if !strings.Contains(body["type"].(string), "read-only") {
+				t.Errorf("problem type = %v, want read-only", body["type"])
+			}
+		})
+	}
+
+	// Reads still work and are scoped to the tenant of the bearer
+	for _, tc := range []struct {
+		authorization string
+		want          int
+	}{
+		{"Bearer bob", 1},
+		{"", 0},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/taskTemplates", nil)
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("list status = %d, want 200; body %s", rec.Code, rec.Body.String())
+		}
+		var resp todopb.ListTaskTemplatesResponse
+		if err := protojson.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode %q: %v", rec.Body.String(), err)
+		}
+		if len(resp.Templates) != tc.want {
+			t.Errorf("Authorization %q listed %d templates, want %d", tc.authorization, len(resp.Templates), tc.want)
+		}
+	}
+}
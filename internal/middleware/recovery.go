@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// UnaryRecoveryInterceptor turns a panicking handler into an INTERNAL_ERROR.
+// Chain it inside UnaryErrorInterceptor so the panic gets the usual error
+// envelope and trace ID.
+func UnaryRecoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Recovered from panic: %v", r)
+			monitoring.RecordPanicRecovery(ctx)
+
+			cause := fmt.Errorf("panic in %s: %v", info.FullMethod, r)
+			trace.SpanFromContext(ctx).RecordError(cause, trace.WithStackTrace(true))
+
+			traceID := monitoring.TraceIDFromContext(ctx)
+			err = apperrors.NewInternal("Internal server error", traceID, cause)
+		}
+	}()
+
+	return handler(ctx, req)
+}
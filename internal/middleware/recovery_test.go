@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPanicBecomesInternalErrorWithTraceID(t *testing.T) {
+	ctx := monitoring.WithTraceID(context.Background(), "trace-panic-1")
+	info := &grpc.UnaryServerInfo{FullMethod: "/todo.v1.TodoService/GetTask"}
+	panicking := func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("nil map write")
+	}
+
+	// Same nesting as the server's interceptor chain
+	_, err := UnaryErrorInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return UnaryRecoveryInterceptor(ctx, req, info, panicking)
+	})
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Internal {
+		t.Fatalf("got %v, want an INTERNAL status", err)
+	}
+	appErr := apperrors.FromGRPCStatus(st)
+	if appErr.AppCode != errorspb.AppErrorCode_INTERNAL_ERROR {
+		t.Errorf("app code = %v, want INTERNAL_ERROR", appErr.AppCode)
+	}
+	if appErr.TraceID != "trace-panic-1" {
+		t.Errorf("trace ID = %q, want trace-panic-1", appErr.TraceID)
+	}
+	if strings.Contains(st.Message(), "nil map write") {
+		t.Errorf("panic value leaked to the client: %q", st.Message())
+	}
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metadata keys (and HTTP headers, forwarded by the gateway) for signed nonces
+const (
+	NonceMetadataKey          = "x-request-nonce"
+	NonceTimestampMetadataKey = "x-request-timestamp"
+	NonceSignatureMetadataKey = "x-request-signature"
+)
+
+// SignNonce returns the signature clients send with a nonce: the hex
+// HMAC-SHA256 of "<nonce>.<unix timestamp>.<method>.<body hash>" keyed with
+// the shared secret. method is the full gRPC method name, e.g.
+// "/todo.v1.TodoService/CreateTask", and bodyHash is RequestBodyHash of the
+// request, so a captured signature can't be reused for another call.
+func SignNonce(secret []byte, nonce string, timestamp int64, method, bodyHash string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(nonce + "." + strconv.FormatInt(timestamp, 10) + "." + method + "." + bodyHash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RequestBodyHash is the hex SHA-256 of the request's deterministic protobuf
+// encoding. Gateway requests are hashed after JSON decoding, so HTTP clients
+// sign the RPC request their route maps to.
+func RequestBodyHash(req proto.Message) (string, error) {
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// nonceStore remembers nonces until their validity window has passed
+type nonceStore struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time // nonce -> expiry
+	nextSweep time.Time
+}
+
+// claim records the nonce and reports false if it was already used
+func (s *nonceStore) claim(nonce string, expires, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.After(s.nextSweep) {
+		for n, exp := range s.seen {
+			if now.After(exp) {
+				delete(s.seen, n)
+			}
+		}
+		s.nextSweep = now.Add(time.Minute)
+	}
+
+	if exp, ok := s.seen[nonce]; ok && !now.After(exp) {
+		return false
+	}
+	s.seen[nonce] = expires
+	return true
+}
+
+// ReplayProtectionInterceptor rejects replays of mutating requests that carry
+// a signed nonce. Nonces are optional; when present the signature must match
+// the method and body, the timestamp must be within window of now, and the
+// nonce must not have been used before. Unlike an idempotency key, a repeated
+// nonce is rejected outright rather than returning the earlier result.
+func ReplayProtectionInterceptor(secret []byte, window time.Duration) grpc.UnaryServerInterceptor {
+	store := &nonceStore{seen: make(map[string]time.Time)}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		nonce := firstMetadataValue(md, NonceMetadataKey)
+		if !mutatingMethods[info.FullMethod] || nonce == "" {
+			return handler(ctx, req)
+		}
+
+		traceID := monitoring.TraceIDFromContext(ctx)
+		timestamp, err := strconv.ParseInt(firstMetadataValue(md, NonceTimestampMetadataKey), 10, 64)
+		if err != nil {
+			return nil, apperrors.NewReplayedRequest("The request nonce must come with a Unix timestamp in "+NonceTimestampMetadataKey+".", traceID)
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return nil, apperrors.NewInternal("Cannot hash a request that is not a protobuf message", traceID, nil)
+		}
+		bodyHash, err := RequestBodyHash(msg)
+		if err != nil {
+			return nil, apperrors.NewInternal("Failed to hash the request body", traceID, err)
+		}
+		signature := firstMetadataValue(md, NonceSignatureMetadataKey)
+		if !hmac.Equal([]byte(signature), []byte(SignNonce(secret, nonce, timestamp, info.FullMethod, bodyHash))) {
+			return nil, apperrors.NewReplayedRequest("The request nonce signature is invalid.", traceID)
+		}
+
+		now := time.Now()
+		issued := time.Unix(timestamp, 0)
+		if issued.Before(now.Add(-window)) || issued.After(now.Add(window)) {
+			return nil, apperrors.NewReplayedRequest("The request nonce has expired. Sign a new nonce with the current time.", traceID)
+		}
+
+		if !store.claim(nonce, issued.Add(window), now) {
+			return nil, apperrors.NewNonceReused(traceID)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// GatewayHeaderMatcher forwards the nonce headers to gRPC in addition to the
+// headers the gateway forwards by default
+func GatewayHeaderMatcher(key string) (string, bool) {
+	switch lower := strings.ToLower(key); lower {
+	case NonceMetadataKey, NonceTimestampMetadataKey, NonceSignatureMetadataKey:
+		return lower, true
+	default:
+		return runtime.DefaultHeaderMatcher(key)
+	}
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
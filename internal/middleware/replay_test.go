@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	stderrors "errors"
+	"strconv"
+	"testing"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+var replaySecret = []byte("nonce-secret")
+
+// signedContext carries a nonce signed for method and req at timestamp
+func signedContext(t *testing.T, nonce string, timestamp time.Time, method string, req *todopb.CreateTaskRequest) context.Context {
+	t.Helper()
+	bodyHash, err := RequestBodyHash(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := timestamp.Unix()
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		NonceMetadataKey, nonce,
+		NonceTimestampMetadataKey, strconv.FormatInt(ts, 10),
+		NonceSignatureMetadataKey, SignNonce(replaySecret, nonce, ts, method, bodyHash),
+	))
+}
+
+func TestReplayProtection(t *testing.T) {
+	interceptor := ReplayProtectionInterceptor(replaySecret, 5*time.Minute)
+	info := &grpc.UnaryServerInfo{FullMethod: todopb.TodoService_CreateTask_FullMethodName}
+	req := &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Pay invoice"}}
+	var calls int
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return &todopb.Task{}, nil
+	}
+	call := func(ctx context.Context, req *todopb.CreateTaskRequest) error {
+		_, err := interceptor(ctx, req, info, handler)
+		return err
+	}
+	wantRejected := func(t *testing.T, err error, code codes.Code) {
+		t.Helper()
+		var appErr *apperrors.AppError
+		if !stderrors.As(err, &appErr) || appErr.GRPCCode != code || appErr.AppCode != errorspb.AppErrorCode_REPLAYED_REQUEST {
+			t.Errorf("err = %v, want REPLAYED_REQUEST with %v", err, code)
+		}
+	}
+
+	t.Run("fresh nonce", func(t *testing.T) {
+		if err := call(signedContext(t, "n-fresh", time.Now(), info.FullMethod, req), req); err != nil {
+			t.Fatalf("fresh nonce rejected: %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("handler ran %d times, want 1", calls)
+		}
+	})
+
+	t.Run("replayed nonce", func(t *testing.T) {
+		ctx := signedContext(t, "n-replayed", time.Now(), info.FullMethod, req)
+		if err := call(ctx, req); err != nil {
+			t.Fatalf("first use rejected: %v", err)
+		}
+		before := calls
+		wantRejected(t, call(ctx, req), codes.AlreadyExists)
+		if calls != before {
+			t.Error("handler ran for a replayed nonce")
+		}
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		wantRejected(t, call(signedContext(t, "n-stale", time.Now().Add(-10*time.Minute), info.FullMethod, req), req), codes.Unauthenticated)
+		wantRejected(t, call(signedContext(t, "n-future", time.Now().Add(10*time.Minute), info.FullMethod, req), req), codes.Unauthenticated)
+	})
+
+	t.Run("signature bound to body", func(t *testing.T) {
+		ctx := signedContext(t, "n-body", time.Now(), info.FullMethod, req)
+		tampered := &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Pay attacker"}}
+		wantRejected(t, call(ctx, tampered), codes.Unauthenticated)
+	})
+
+	t.Run("signature bound to method", func(t *testing.T) {
+		ctx := signedContext(t, "n-method", time.Now(), todopb.TodoService_DeleteTask_FullMethodName, req)
+		wantRejected(t, call(ctx, req), codes.Unauthenticated)
+	})
+
+	t.Run("unsigned requests pass", func(t *testing.T) {
+		if err := call(context.Background(), req); err != nil {
+			t.Errorf("request without a nonce rejected: %v", err)
+		}
+	})
+}
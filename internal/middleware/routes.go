@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Route is an HTTP verb and path template served by the gateway, e.g.
+// GET /v1/tasks/{id}
+type Route struct {
+	Method   string
+	Template string
+}
+
+// routeVariable matches a path variable such as {name=tasks/*} or {id}
+var routeVariable = regexp.MustCompile(`\{[^}=]+(=([^}]*))?\}`)
+
+// ServiceRoutes reads the HTTP routes of every method of a gRPC service from
+// its google.api.http annotations, so they always match what the generated
+// gateway registers. A bound resource name such as {name=tasks/*} becomes
+// tasks/{id}.
+func ServiceRoutes(sd protoreflect.ServiceDescriptor) []Route {
+	var routes []Route
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		rule, ok := proto.GetExtension(methods.Get(i).Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		for _, r := range append([]*annotations.HttpRule{rule}, rule.AdditionalBindings...) {
+			if route, ok := httpRuleRoute(r); ok {
+				routes = append(routes, route)
+			}
+		}
+	}
+	return routes
+}
+
+func httpRuleRoute(rule *annotations.HttpRule) (Route, bool) {
+	var method, template string
+	switch p := rule.Pattern.(type) {
+	case *annotations.HttpRule_Get:
+		method, template = http.MethodGet, p.Get
+	case *annotations.HttpRule_Post:
+		method, template = http.MethodPost, p.Post
+	case *annotations.HttpRule_Put:
+		method, template = http.MethodPut, p.Put
+	case *annotations.HttpRule_Patch:
+		method, template = http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Delete:
+		method, template = http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Custom:
+		method, template = p.Custom.Kind, p.Custom.Path
+	default:
+		return Route{}, false
+	}
+
+	template = routeVariable.ReplaceAllStringFunc(template, func(v string) string {
+		m := routeVariable.FindStringSubmatch(v)
+		if m[1] == "" {
+			return v
+		}
+		return strings.ReplaceAll(m[2], "*", "{id}")
+	})
+	return Route{Method: method, Template: template}, true
+}
+
+// RouteTable matches request paths against the registered routes, for the
+// Allow header of 405 responses and for metric labels
+type RouteTable struct {
+	routes []Route
+}
+
+// NewRouteTable creates a table of the given routes
+func NewRouteTable(routes ...Route) *RouteTable {
+	return &RouteTable{routes: routes}
+}
+
+// AllowedMethods reports the verbs registered for a path, in table order
+func (t *RouteTable) AllowedMethods(path string) []string {
+	var methods []string
+	seen := make(map[string]bool)
+	for _, route := range t.routes {
+		if matchRoute(route.Template, path) && !seen[route.Method] {
+			seen[route.Method] = true
+			methods = append(methods, route.Method)
+		}
+	}
+	return methods
+}
+
+// Template maps a request path to the template of the route it matches.
+// Paths matching no route share one label so scanners can't inflate label
+// cardinality.
+func (t *RouteTable) Template(path string) string {
+	for _, route := range t.routes {
+		if matchRoute(route.Template, path) {
+			return route.Template
+		}
+	}
+	return "unmatched"
+}
+
+// matchRoute reports whether path fits template. A variable matches one
+// non-empty segment, and a trailing :verb must match exactly.
+func matchRoute(template, path string) bool {
+	templateSegments, templateVerb := splitVerb(template)
+	pathSegments, pathVerb := splitVerb(path)
+	if templateVerb != pathVerb || len(templateSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitVerb splits a path into its segments and the custom verb after the
+// last segment's colon, if any
+func splitVerb(path string) ([]string, string) {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	last := segments[len(segments)-1]
+	verb := ""
+	if i := strings.LastIndex(last, ":"); i >= 0 {
+		segments[len(segments)-1], verb = last[:i], last[i+1:]
+	}
+	return segments, verb
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+)
+
+func TestRouteTableFollowsServiceAnnotations(t *testing.T) {
+	routes := ServiceRoutes(todopb.File_api_proto_todo_v1_todo_proto.Services().ByName("TodoService"))
+	table := NewRouteTable(append(routes,
+		Route{Method: http.MethodGet, Template: "/v1/tasks/{id}:history"},
+		Route{Method: http.MethodGet, Template: "/v1/debug/errors/{code}"},
+	)...)
+
+	allowed := []struct {
+		path string
+		want []string
+	}{
+		{"/v1/tasks", []string{http.MethodPost, http.MethodGet}},
+		{"/v1/tasks/abc", []string{http.MethodGet, http.MethodPatch, http.MethodDelete}},
+		{"/v1/tasks/abc:history", []string{http.MethodGet}},
+		{"/v1/tasks:batchCreate", []string{http.MethodPost}},
+		{"/v1/tasks:batchGet", []string{http.MethodGet}},
+		{"/v1/tasks:nope", nil},
+		{"/v1/tasks/abc/def", nil},
+	}
+	for _, tt := range allowed {
+		if got := table.AllowedMethods(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AllowedMethods(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	templates := map[string]string{
+		"/v1/tasks":             "/v1/tasks",
+		"/v1/tasks/abc":         "/v1/tasks/{id}",
+		"/v1/tasks/abc:history": "/v1/tasks/{id}:history",
+		"/v1/tasks:stats":       "/v1/tasks:stats",
+		"/v1/debug/errors/404":  "/v1/debug/errors/{code}",
+		"/wp-admin/setup.php":   "unmatched",
+	}
+	for path, want := range templates {
+		if got := table.Template(path); got != want {
+			t.Errorf("Template(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
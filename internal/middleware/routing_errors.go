@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// RoutingErrorHandler renders a path that exists but doesn't accept the
+// request's verb as a 405 with an Allow header. allowedMethods reports the
+// verbs registered for a path, since the gateway mux doesn't expose them.
+// Other routing errors keep the gateway's default handling.
+func RoutingErrorHandler(allowedMethods func(path string) []string) runtime.RoutingErrorHandlerFunc {
+	return func(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, httpStatus int) {
+		allowed := allowedMethods(r.URL.Path)
+		if httpStatus != http.StatusMethodNotAllowed || len(allowed) == 0 {
+			runtime.DefaultRoutingErrorHandler(ctx, mux, marshaler, w, r, httpStatus)
+			return
+		}
+
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		appErr := apperrors.NewMethodNotAllowed(r.Method, r.URL.Path, allowed, resolveTraceID(ctx, r))
+		CustomHTTPError(ctx, mux, marshaler, w, r, appErr.ToGRPCStatus().Err())
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryTraceIDInterceptor resolves the request's trace ID once, preferring
+// the ID forwarded by the HTTP gateway, and stores it in the context for the
+// service, logs and error responses.
+func UnaryTraceIDInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var supplied string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(monitoring.TraceIDMetadataKey); len(values) > 0 {
+			supplied = values[0]
+		}
+	}
+
+	return handler(monitoring.WithTraceID(ctx, monitoring.ResolveTraceID(ctx, supplied)), req)
+}
+
+// GatewayTraceIDMetadata forwards the trace ID resolved by HTTPErrorHandler to
+// the gRPC backend so both halves of a request share one ID
+func GatewayTraceIDMetadata(ctx context.Context, _ *http.Request) metadata.MD {
+	if traceID := monitoring.TraceIDFromContext(ctx); traceID != "" {
+		return metadata.Pairs(monitoring.TraceIDMetadataKey, traceID)
+	}
+	return nil
+}
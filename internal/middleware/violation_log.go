@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	violationLoggerMu sync.RWMutex
+	violationLogger   = newViolationLogger(slog.LevelInfo)
+	violationHashKey  = randomHashKey()
+)
+
+// SetViolationLogLevel sets the minimum level of the structured analytics
+// log. Field violations are logged at debug, so they are only written when
+// the level is slog.LevelDebug.
+func SetViolationLogLevel(level slog.Level) {
+	violationLoggerMu.Lock()
+	defer violationLoggerMu.Unlock()
+	violationLogger = newViolationLogger(level)
+}
+
+// SetViolationHashKey sets the server secret that keys the value hashes in the
+// field violation log. Without one, a random key is used, so hashes only
+// group repeated values within a single process.
+func SetViolationHashKey(key []byte) {
+	violationLoggerMu.Lock()
+	defer violationLoggerMu.Unlock()
+	violationHashKey = key
+}
+
+func randomHashKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("generate violation hash key: %v", err))
+	}
+	return key
+}
+
+// hashViolationValue keys the hash with a server secret, so short or common
+// values (a PIN, a phone number) can't be recovered by hashing guesses
+func hashViolationValue(key, repr []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(repr)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newViolationLogger(level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+}
+
+// logFieldViolations records which fields clients get wrong. Values are never
+// logged, only their length and an HMAC so repeated values can be grouped. Field paths are resolved against the request, or against a message
+// field of the request (e.g. CreateTaskRequest.task) for task-level rules.
+func logFieldViolations(ctx context.Context, method string, req interface{}, violations []*errorspb.FieldViolation) {
+	violationLoggerMu.RLock()
+	logger, key := violationLogger, violationHashKey
+	violationLoggerMu.RUnlock()
+
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	msg, _ := req.(proto.Message)
+	for _, fv := range violations {
+		attrs := []any{
+			"method", method,
+			"field", fv.Field,
+			"code", fv.Code,
+			"trace_id", monitoring.TraceIDFromContext(ctx),
+		}
+		if msg != nil {
+			if repr, length, ok := violationValue(msg.ProtoReflect(), fv.Field); ok {
+				attrs = append(attrs, "value_length", length, "value_hash", hashViolationValue(key, repr))
+			}
+		}
+		logger.DebugContext(ctx, "field violation", attrs...)
+	}
+}
+
+// violationValue finds the value at path and returns a byte representation to
+// hash and its length in characters (or items for a list)
+func violationValue(msg protoreflect.Message, path string) ([]byte, int, bool) {
+	if repr, length, ok := resolveFieldPath(msg, path); ok {
+		return repr, length, true
+	}
+
+	// Task-level rules report paths relative to the task, not the request
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() || !msg.Has(fd) {
+			continue
+		}
+		if repr, length, ok := resolveFieldPath(msg.Get(fd).Message(), path); ok {
+			return repr, length, true
+		}
+	}
+	return nil, 0, false
+}
+
+// resolveFieldPath walks a path such as "requests[2].task.title"
+func resolveFieldPath(msg protoreflect.Message, path string) ([]byte, int, bool) {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		name, index := segment, -1
+		if open := strings.Index(segment, "["); open > 0 && strings.HasSuffix(segment, "]") {
+			n, err := strconv.Atoi(segment[open+1 : len(segment)-1])
+			if err != nil {
+				return nil, 0, false
+			}
+			name, index = segment[:open], n
+		}
+
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil || fd.IsMap() {
+			return nil, 0, false
+		}
+
+		value := msg.Get(fd)
+		last := i == len(segments)-1
+		if fd.IsList() {
+			list := value.List()
+			if index < 0 {
+				if !last {
+					return nil, 0, false
+				}
+				var repr []byte
+				for j := 0; j < list.Len(); j++ {
+					elem, _ := scalarRepr(fd, list.Get(j))
+					repr = append(append(repr, elem...), 0)
+				}
+				return repr, list.Len(), true
+			}
+			if index >= list.Len() {
+				return nil, 0, false
+			}
+			value = list.Get(index)
+		} else if index >= 0 {
+			return nil, 0, false
+		}
+
+		if last {
+			repr, length := scalarRepr(fd, value)
+			return repr, length, true
+		}
+		if fd.Kind() != protoreflect.MessageKind {
+			return nil, 0, false
+		}
+		msg = value.Message()
+	}
+	return nil, 0, false
+}
+
+func scalarRepr(fd protoreflect.FieldDescriptor, value protoreflect.Value) ([]byte, int) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return []byte(value.String()), utf8.RuneCountInString(value.String())
+	case protoreflect.BytesKind:
+		return value.Bytes(), len(value.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		repr, _ := protojson.Marshal(value.Message().Interface())
+		return repr, len(repr)
+	default:
+		repr := fmt.Sprint(value.Interface())
+		return []byte(repr), len(repr)
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+)
+
+// captureViolationLog sends the violation log to a buffer at debug, keyed
+// with key, for the rest of the test
+func captureViolationLog(t *testing.T, key []byte) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	violationLoggerMu.Lock()
+	savedLogger, savedKey := violationLogger, violationHashKey
+	violationLogger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	violationHashKey = key
+	violationLoggerMu.Unlock()
+	t.Cleanup(func() {
+		violationLoggerMu.Lock()
+		violationLogger, violationHashKey = savedLogger, savedKey
+		violationLoggerMu.Unlock()
+	})
+	return &buf
+}
+
+func TestFieldViolationLogNeverContainsRawValues(t *testing.T) {
+	const secret = "4111-1111-1111-1111"
+	req := &todopb.CreateTaskRequest{Task: &todopb.Task{
+		Title:       secret,
+		Description: "note " + secret,
+		Tags:        []string{"ok", secret},
+	}}
+	violations := []*errorspb.FieldViolation{
+		{Field: "title", Code: "INVALID_FORMAT"},
+		{Field: "task.description", Code: "INVALID_FORMAT"},
+		{Field: "tags[1]", Code: "DUPLICATE_TAG"},
+		{Field: "tags", Code: "DUPLICATE_TAG"},
+	}
+
+	buf := captureViolationLog(t, []byte("server-secret"))
+	logFieldViolations(context.Background(), "/todo.v1.TodoService/CreateTask", req, violations)
+
+	out := buf.String()
+	if strings.Contains(out, secret) {
+		t.Fatalf("violation log leaks the raw value:\n%s", out)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != len(violations) {
+		t.Fatalf("logged %d lines, want %d:\n%s", len(lines), len(violations), out)
+	}
+	var first struct {
+		Field       string `json:"field"`
+		ValueLength int    `json:"value_length"`
+		ValueHash   string `json:"value_hash"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("decode %s: %v", lines[0], err)
+	}
+	if first.ValueLength != len(secret) || first.ValueHash != hashViolationValue([]byte("server-secret"), []byte(secret)) {
+		t.Errorf("first line = %+v, want the length and keyed hash of the title", first)
+	}
+}
+
+func TestViolationHashDependsOnServerKey(t *testing.T) {
+	value := []byte("+14155550100")
+	a := hashViolationValue([]byte("key-a"), value)
+	if a != hashViolationValue([]byte("key-a"), value) {
+		t.Error("hash is not stable for one key")
+	}
+	if a == hashViolationValue([]byte("key-b"), value) {
+		t.Error("hash is the same under different keys")
+	}
+	if len(a) != 64 {
+		t.Errorf("hash length = %d, want the full 32-byte HMAC in hex", len(a))
+	}
+}
@@ -49,6 +49,50 @@ var (
 			Help: "Total number of recovered panics",
 		},
 	)
+
+	// Security metrics
+	securityEventCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "todo_api_security_events_total",
+			Help: "Total number of security events such as blocked PII searches",
+		},
+		[]string{"event"},
+	)
+
+	// PII access metrics
+	piiAccessCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pii_access_total",
+			Help: "Total number of audited PII accesses by action and sensitivity",
+		},
+		[]string{"action", "sensitivity"},
+	)
+
+	// Concurrency metrics
+	inFlightGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "todo_api_in_flight_requests",
+			Help: "Number of gRPC requests currently being handled",
+		},
+	)
+
+	// HTTP gateway metrics
+	httpRequestCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "todo_api_http_requests_total",
+			Help: "Total number of HTTP gateway requests by method, route and status",
+		},
+		[]string{"method", "route", "status_code"},
+	)
+
+	httpRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "todo_api_http_request_duration_seconds",
+			Help:    "Time taken to serve HTTP gateway requests",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "route"},
+	)
 )
 
 // OpenTelemetry metrics instruments
@@ -58,6 +102,11 @@ var (
 	otelValidationCounter     metric.Int64Counter
 	otelResponseTimeHistogram metric.Float64Histogram
 	otelPanicCounter          metric.Int64Counter
+	otelSecurityEventCounter  metric.Int64Counter
+	otelPIIAccessCounter      metric.Int64Counter
+	otelInFlight              metric.Int64UpDownCounter
+	otelHTTPRequestCounter    metric.Int64Counter
+	otelHTTPDuration          metric.Float64Histogram
 	otelInitOnce              sync.Once
 )
 
@@ -100,6 +149,50 @@ func InitOpenTelemetryMetrics() error {
 			"api.panic_recovery.total",
 			metric.WithDescription("Total number of recovered panics"),
 		)
+		if err != nil {
+			return
+		}
+
+		// Create security event counter
+		otelSecurityEventCounter, err = otelMeter.Int64Counter(
+			"api.security_events.total",
+			metric.WithDescription("Total number of security events"),
+		)
+		if err != nil {
+			return
+		}
+
+		// Create PII access counter
+		otelPIIAccessCounter, err = otelMeter.Int64Counter(
+			"api.pii_access.total",
+			metric.WithDescription("Total number of audited PII accesses"),
+		)
+		if err != nil {
+			return
+		}
+
+		// Create in-flight request gauge
+		otelInFlight, err = otelMeter.Int64UpDownCounter(
+			"api.in_flight_requests",
+			metric.WithDescription("Number of gRPC requests currently being handled"),
+		)
+		if err != nil {
+			return
+		}
+
+		// Create HTTP gateway request instruments
+		otelHTTPRequestCounter, err = otelMeter.Int64Counter(
+			"api.http_requests.total",
+			metric.WithDescription("Total number of HTTP gateway requests"),
+		)
+		if err != nil {
+			return
+		}
+
+		otelHTTPDuration, err = otelMeter.Float64Histogram(
+			"api.http_request_duration.seconds",
+			metric.WithDescription("Time taken to serve HTTP gateway requests"),
+		)
 	})
 	return err
 }
@@ -165,6 +258,70 @@ func RecordPanicRecovery(ctx context.Context) {
 	}
 }
 
+// RecordSecurityEvent records a security-relevant event such as a blocked PII search
+func RecordSecurityEvent(ctx context.Context, event string) {
+	// Record Prometheus metrics
+	securityEventCounter.WithLabelValues(event).Inc()
+
+	// Record OpenTelemetry metrics (if initialized)
+	if otelSecurityEventCounter != nil {
+		otelSecurityEventCounter.Add(ctx, 1,
+			metric.WithAttributes(
+				attribute.String("security.event", event),
+			),
+		)
+	}
+}
+
+// RecordPIIAccess records an audited PII access so spikes in HIGH-sensitivity
+// access can be alerted on
+func RecordPIIAccess(ctx context.Context, action, sensitivity string) {
+	// Record Prometheus metrics
+	piiAccessCounter.WithLabelValues(action, sensitivity).Inc()
+
+	// Record OpenTelemetry metrics (if initialized)
+	if otelPIIAccessCounter != nil {
+		otelPIIAccessCounter.Add(ctx, 1,
+			metric.WithAttributes(
+				attribute.String("pii.action", action),
+				attribute.String("pii.sensitivity", sensitivity),
+			),
+		)
+	}
+}
+
+// AddInFlightRequests adjusts the in-flight request gauge by delta
+func AddInFlightRequests(ctx context.Context, delta int) {
+	// Record Prometheus metrics
+	inFlightGauge.Add(float64(delta))
+
+	// Record OpenTelemetry metrics (if initialized)
+	if otelInFlight != nil {
+		otelInFlight.Add(ctx, int64(delta))
+	}
+}
+
+// RecordHTTPRequest records the outcome and latency of an HTTP gateway request.
+// route should be a path template, not the raw path, to bound label cardinality.
+func RecordHTTPRequest(ctx context.Context, method, route string, statusCode int, duration time.Duration) {
+	// Record Prometheus metrics
+	httpRequestCounter.WithLabelValues(method, route, fmt.Sprintf("%d", statusCode)).Inc()
+	httpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+
+	// Record OpenTelemetry metrics (if initialized)
+	attrs := metric.WithAttributes(
+		attribute.String("http.method", method),
+		attribute.String("http.route", route),
+		attribute.Int("http.status_code", statusCode),
+	)
+	if otelHTTPRequestCounter != nil {
+		otelHTTPRequestCounter.Add(ctx, 1, attrs)
+	}
+	if otelHTTPDuration != nil {
+		otelHTTPDuration.Record(ctx, duration.Seconds(), attrs)
+	}
+}
+
 // RecordValidationErrors records multiple validation errors at once
 func RecordValidationErrors(ctx context.Context, validationErrors []ValidationError, endpoint string) {
 	for _, ve := range validationErrors {
@@ -0,0 +1,47 @@
+package monitoring
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDHeader lets HTTP clients supply their own trace ID
+	TraceIDHeader = "X-Trace-ID"
+
+	// TraceIDMetadataKey carries the resolved trace ID from the gateway to gRPC
+	TraceIDMetadataKey = "x-trace-id"
+)
+
+// ResolveTraceID picks the trace ID for a request: one supplied by the
+// client, then the trace carried in ctx, and finally a new UUID. It is called
+// once at the edge and the result stored with WithTraceID, so logs and error
+// responses always report the same ID.
+func ResolveTraceID(ctx context.Context, supplied string) string {
+	if supplied != "" {
+		return supplied
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return uuid.New().String()
+}
+
+// WithTraceID stores the request's resolved trace ID in ctx
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, "traceID", traceID)
+}
+
+// TraceIDFromContext returns the trace ID stored by WithTraceID, falling back
+// to the current span for contexts that never passed through the edge
+func TraceIDFromContext(ctx context.Context) string {
+	if traceID, ok := ctx.Value("traceID").(string); ok && traceID != "" {
+		return traceID
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
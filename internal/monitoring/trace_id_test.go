@@ -0,0 +1,34 @@
+package monitoring
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestResolveTraceIDPriority(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	withSpan := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	if got := ResolveTraceID(withSpan, "client-1"); got != "client-1" {
+		t.Errorf("supplied ID: got %q, want client-1", got)
+	}
+	if got := ResolveTraceID(withSpan, ""); got != traceID.String() {
+		t.Errorf("span: got %q, want %s", got, traceID)
+	}
+	if got := ResolveTraceID(context.Background(), ""); uuid.Validate(got) != nil {
+		t.Errorf("fallback: got %q, want a UUID", got)
+	}
+}
+
+func TestTraceIDFromContext(t *testing.T) {
+	if got := TraceIDFromContext(context.Background()); got != "" {
+		t.Errorf("empty context: got %q", got)
+	}
+	if got := TraceIDFromContext(WithTraceID(context.Background(), "resolved-1")); got != "resolved-1" {
+		t.Errorf("stored ID: got %q, want resolved-1", got)
+	}
+}
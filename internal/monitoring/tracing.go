@@ -0,0 +1,113 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/bhatti/todo-api-errors/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+)
+
+var (
+	forceSampleMu    sync.RWMutex
+	forceSampleCodes = map[grpccodes.Code]bool{}
+)
+
+// InitOpenTelemetryTracing installs a global tracer provider that samples successful
+// requests at the configured ratio while always keeping spans that fail with one
+// of the configured force-sample codes. The returned function flushes and shuts
+// down the provider.
+func InitOpenTelemetryTracing(cfg config.TracingConfig, exporters ...sdktrace.SpanExporter) (func(context.Context) error, error) {
+	forced := make(map[grpccodes.Code]bool, len(cfg.ForceSampleCodes))
+	for _, name := range cfg.ForceSampleCodes {
+		var code grpccodes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil || code == grpccodes.OK {
+			return nil, fmt.Errorf("invalid force-sample code %q", name)
+		}
+		forced[code] = true
+	}
+	forceSampleMu.Lock()
+	forceSampleCodes = forced
+	forceSampleMu.Unlock()
+
+	sampler := errorAwareSampler{ratio: sdktrace.TraceIDRatioBased(cfg.SampleRatio)}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler,
+			sdktrace.WithRemoteParentNotSampled(sampler),
+			sdktrace.WithLocalParentNotSampled(sampler),
+		)),
+	}
+	for _, exporter := range exporters {
+		opts = append(opts, sdktrace.WithSpanProcessor(
+			errorSpanProcessor{SpanProcessor: sdktrace.NewBatchSpanProcessor(exporter)},
+		))
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// MarkSpanFailed records that the request behind ctx's span failed with code.
+// Only force-sample codes, by default server-side faults, give the span an
+// error status and so bypass the sample ratio; client errors such as NOT_FOUND
+// are left to the ratio, so a client sending bad requests can't force every
+// trace through.
+func MarkSpanFailed(ctx context.Context, code grpccodes.Code, msg string) {
+	forceSampleMu.RLock()
+	forced := forceSampleCodes[code]
+	forceSampleMu.RUnlock()
+
+	if forced {
+		trace.SpanFromContext(ctx).SetStatus(codes.Error, msg)
+	}
+}
+
+// errorAwareSampler applies a ratio to decide which spans are exported, but keeps
+// recording the rest so that a span which later fails can still be exported.
+type errorAwareSampler struct {
+	ratio sdktrace.Sampler
+}
+
+func (s errorAwareSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.ratio.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+func (s errorAwareSampler) Description() string {
+	return "ErrorAware{" + s.ratio.Description() + "}"
+}
+
+// errorSpanProcessor forces spans with an error status through to the exporter
+// even when the ratio sampler did not select them.
+type errorSpanProcessor struct {
+	sdktrace.SpanProcessor
+}
+
+func (p errorSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() && s.Status().Code == codes.Error {
+		s = sampledSpan{ReadOnlySpan: s}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// sampledSpan reports a recorded span as sampled
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
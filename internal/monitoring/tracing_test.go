@@ -0,0 +1,61 @@
+package monitoring
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bhatti/todo-api-errors/internal/config"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	grpccodes "google.golang.org/grpc/codes"
+)
+
+func TestErroredRequestsAreSampledAtZeroRatio(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown, err := InitOpenTelemetryTracing(config.TracingConfig{
+		SampleRatio:      0,
+		ForceSampleCodes: []string{"INTERNAL", "UNAVAILABLE"},
+	}, exporter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracer := otel.Tracer("test")
+	for name, code := range map[string]grpccodes.Code{
+		"internal":  grpccodes.Internal,
+		"not-found": grpccodes.NotFound,
+		"success":   grpccodes.OK,
+	} {
+		ctx, span := tracer.Start(context.Background(), name)
+		if code != grpccodes.OK {
+			MarkSpanFailed(ctx, code, name)
+		}
+		span.End()
+	}
+	// Shutting down would also reset the in-memory exporter, so flush instead
+	defer shutdown(context.Background())
+	if err := otel.GetTracerProvider().(*sdktrace.TracerProvider).ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	exported := make(map[string]bool)
+	for _, span := range exporter.GetSpans() {
+		exported[span.Name] = true
+	}
+	if !exported["internal"] {
+		t.Error("span failing with INTERNAL was not exported")
+	}
+	if exported["not-found"] {
+		t.Error("span failing with NOT_FOUND was exported despite a zero ratio")
+	}
+	if exported["success"] {
+		t.Error("successful span was exported despite a zero ratio")
+	}
+}
+
+func TestInitOpenTelemetryTracingRejectsUnknownCodes(t *testing.T) {
+	if _, err := InitOpenTelemetryTracing(config.TracingConfig{ForceSampleCodes: []string{"BROKEN"}}); err == nil {
+		t.Fatal("expected an error for an unknown code")
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// AccountRepository defines the interface for account storage
+type AccountRepository interface {
+	CreateAccount(ctx context.Context, account *pii.Account) error
+	GetAccount(ctx context.Context, id string) (*pii.Account, error)
+	UpdateAccount(ctx context.Context, account *pii.Account) error
+	DeleteAccount(ctx context.Context, id string) error
+	ListAccounts(ctx context.Context) ([]*pii.Account, error)
+	SearchAccounts(ctx context.Context, match func(*pii.Account) bool) ([]*pii.Account, error)
+}
+
+// InMemoryAccountRepository is a simple in-memory implementation. Accounts are
+// cloned on the way in and out so callers never share the stored copy.
+type InMemoryAccountRepository struct {
+	mu       sync.RWMutex
+	accounts map[string]*pii.Account
+}
+
+// NewInMemoryAccountRepository creates a new in-memory account repository
+func NewInMemoryAccountRepository() *InMemoryAccountRepository {
+	return &InMemoryAccountRepository{
+		accounts: make(map[string]*pii.Account),
+	}
+}
+
+func (r *InMemoryAccountRepository) CreateAccount(_ context.Context, account *pii.Account) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.accounts[account.Id]; exists {
+		return ErrAlreadyExists
+	}
+
+	r.accounts[account.Id] = cloneAccount(account)
+	return nil
+}
+
+func (r *InMemoryAccountRepository) GetAccount(_ context.Context, id string) (*pii.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	account, exists := r.accounts[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	return cloneAccount(account), nil
+}
+
+func (r *InMemoryAccountRepository) UpdateAccount(_ context.Context, account *pii.Account) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.accounts[account.Id]; !exists {
+		return ErrNotFound
+	}
+
+	r.accounts[account.Id] = cloneAccount(account)
+	return nil
+}
+
+func (r *InMemoryAccountRepository) DeleteAccount(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.accounts[id]; !exists {
+		return ErrNotFound
+	}
+
+	delete(r.accounts, id)
+	return nil
+}
+
+func (r *InMemoryAccountRepository) ListAccounts(ctx context.Context) ([]*pii.Account, error) {
+	return r.SearchAccounts(ctx, func(*pii.Account) bool { return true })
+}
+
+func (r *InMemoryAccountRepository) SearchAccounts(_ context.Context, match func(*pii.Account) bool) ([]*pii.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []*pii.Account
+	for _, account := range r.accounts {
+		if match(account) {
+			matches = append(matches, cloneAccount(account))
+		}
+	}
+
+	// Sort by ID so pagination is stable across calls
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Id < matches[j].Id
+	})
+
+	return matches, nil
+}
+
+func cloneAccount(account *pii.Account) *pii.Account {
+	return proto.Clone(account).(*pii.Account)
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// CoalescingRepository wraps a TodoRepository so concurrent GetTask calls for
+// the same task share a single call to the underlying repository. Every
+// caller gets its own copy of the result.
+type CoalescingRepository struct {
+	TodoRepository
+
+	mu    sync.Mutex
+	calls map[string]*getTaskCall
+}
+
+// getTaskCall is an in-flight GetTask that callers wait on
+type getTaskCall struct {
+	done chan struct{}
+	task *todopb.Task
+	err  error
+}
+
+// NewCoalescingRepository creates a repository that coalesces identical reads
+func NewCoalescingRepository(repo TodoRepository) *CoalescingRepository {
+	return &CoalescingRepository{
+		TodoRepository: repo,
+		calls:          make(map[string]*getTaskCall),
+	}
+}
+
+// GetTask joins an in-flight read of the same task if there is one. The
+// shared read runs detached from any one caller's cancellation, so a caller
+// that gives up returns its own context error without failing the others.
+func (r *CoalescingRepository) GetTask(ctx context.Context, tenantID, id string) (*todopb.Task, error) {
+	key := scopedKey(tenantID, id)
+
+	r.mu.Lock()
+	call, inFlight := r.calls[key]
+	if !inFlight {
+		call = &getTaskCall{done: make(chan struct{})}
+		r.calls[key] = call
+		go r.getTask(context.WithoutCancel(ctx), call, key, tenantID, id)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if call.err != nil {
+		return nil, call.err
+	}
+	return proto.Clone(call.task).(*todopb.Task), nil
+}
+
+// getTask performs the shared read and releases its waiters, even if the
+// underlying repository panics
+func (r *CoalescingRepository) getTask(ctx context.Context, call *getTaskCall, key, tenantID, id string) {
+	defer func() {
+		if p := recover(); p != nil {
+			call.task, call.err = nil, fmt.Errorf("get task %s panicked: %v", id, p)
+		}
+
+		r.mu.Lock()
+		delete(r.calls, key)
+		r.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.task, call.err = r.TodoRepository.GetTask(ctx, tenantID, id)
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+)
+
+// blockingRepository counts GetTask calls and holds each one until release
+// is closed, so a test can pile up callers behind a single read
+type blockingRepository struct {
+	TodoRepository
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+	panics  bool
+}
+
+func newBlockingRepository(t *testing.T) *blockingRepository {
+	store := NewInMemoryRepository(false)
+	seedTasks(t, store, 1, "shared")
+	return &blockingRepository{
+		TodoRepository: store,
+		started:        make(chan struct{}, 1),
+		release:        make(chan struct{}),
+	}
+}
+
+func (r *blockingRepository) GetTask(ctx context.Context, tenantID, id string) (*todopb.Task, error) {
+	r.calls.Add(1)
+	r.started <- struct{}{}
+	<-r.release
+	if r.panics {
+		panic("storage exploded")
+	}
+	return r.TodoRepository.GetTask(ctx, tenantID, id)
+}
+
+// startGetTask calls GetTask in the background and returns a channel that
+// receives its error
+func startGetTask(ctx context.Context, repo TodoRepository, got chan<- *todopb.Task) <-chan error {
+	errc := make(chan error, 1)
+	go func() {
+		task, err := repo.GetTask(ctx, "t1", "task-000")
+		if got != nil && err == nil {
+			got <- task
+		}
+		errc <- err
+	}()
+	return errc
+}
+
+func TestCoalescingRepositorySharesConcurrentReads(t *testing.T) {
+	inner := newBlockingRepository(t)
+	repo := NewCoalescingRepository(inner)
+
+	const callers = 10
+	got := make(chan *todopb.Task, callers)
+	errcs := []<-chan error{startGetTask(context.Background(), repo, got)}
+	<-inner.started
+	for i := 1; i < callers; i++ {
+		errcs = append(errcs, startGetTask(context.Background(), repo, got))
+	}
+	// Give the later callers time to join the read in flight
+	time.Sleep(50 * time.Millisecond)
+	close(inner.release)
+
+	for _, errc := range errcs {
+		if err := <-errc; err != nil {
+			t.Fatalf("GetTask: %v", err)
+		}
+	}
+	if n := inner.calls.Load(); n != 1 {
+		t.Errorf("underlying GetTask called %d times, want 1", n)
+	}
+
+	// Each caller owns its copy
+	seen := make(map[*todopb.Task]bool)
+	for i := 0; i < callers; i++ {
+		task := <-got
+		if task.Title != "shared" || seen[task] {
+			t.Fatalf("caller got %v, want its own copy of the task", task)
+		}
+		seen[task] = true
+	}
+}
+
+func TestCoalescingRepositoryFirstCallerCancellationIsNotShared(t *testing.T) {
+	inner := newBlockingRepository(t)
+	repo := NewCoalescingRepository(inner)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := startGetTask(ctx, repo, nil)
+	<-inner.started
+	second := startGetTask(context.Background(), repo, nil)
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller got %v, want context.Canceled", err)
+	}
+
+	close(inner.release)
+	if err := <-second; err != nil {
+		t.Errorf("waiting caller failed with the first caller's cancellation: %v", err)
+	}
+}
+
+func TestCoalescingRepositoryReleasesWaitersOnPanic(t *testing.T) {
+	inner := newBlockingRepository(t)
+	inner.panics = true
+	repo := NewCoalescingRepository(inner)
+
+	errcs := []<-chan error{startGetTask(context.Background(), repo, nil)}
+	<-inner.started
+	errcs = append(errcs, startGetTask(context.Background(), repo, nil))
+	time.Sleep(50 * time.Millisecond)
+	close(inner.release)
+
+	for _, errc := range errcs {
+		select {
+		case err := <-errc:
+			if err == nil {
+				t.Error("GetTask succeeded although the repository panicked")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("caller still waiting after the shared read panicked")
+		}
+	}
+
+	// The failed read is not cached
+	inner.panics = false
+	go func() { <-inner.started }()
+	if _, err := repo.GetTask(context.Background(), "t1", "task-000"); err != nil {
+		t.Errorf("GetTask after a panic: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Mutation records one change to a task for debugging
+type Mutation struct {
+	Action   string    `json:"action"` // CREATE, UPDATE or DELETE
+	TaskID   string    `json:"task_id"`
+	TenantID string    `json:"tenant_id"`
+	User     string    `json:"user,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// MutationLog keeps the most recent task mutations in a fixed-size ring, so
+// reports like "my task vanished" can be traced without unbounded memory
+type MutationLog struct {
+	mu      sync.Mutex
+	entries []Mutation
+	next    int  // slot the next mutation is written to
+	full    bool // whether the ring has wrapped
+}
+
+// NewMutationLog keeps up to size mutations; size must be positive
+func NewMutationLog(size int) *MutationLog {
+	return &MutationLog{entries: make([]Mutation, size)}
+}
+
+// record appends a mutation, overwriting the oldest once the log is full.
+// The user is taken from the request context when there is one.
+func (l *MutationLog) record(ctx context.Context, action, tenantID, taskID string) {
+	if l == nil {
+		return
+	}
+	user, _ := ctx.Value("user").(string)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[l.next] = Mutation{
+		Action:   action,
+		TaskID:   taskID,
+		TenantID: tenantID,
+		User:     user,
+		Time:     time.Now().UTC(),
+	}
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Recent returns the logged mutations for tenantID, oldest first
+func (l *MutationLog) Recent(tenantID string) []Mutation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ordered := l.entries[:l.next]
+	if l.full {
+		ordered = append(append([]Mutation(nil), l.entries[l.next:]...), l.entries[:l.next]...)
+	}
+
+	mutations := make([]Mutation, 0, len(ordered))
+	for _, m := range ordered {
+		if m.TenantID == tenantID {
+			mutations = append(mutations, m)
+		}
+	}
+	return mutations
+}
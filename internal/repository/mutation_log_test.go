@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+)
+
+func TestMutationLogKeepsRecentWritesInOrder(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user", "bob")
+	repo := NewInMemoryRepository(true)
+	log := NewMutationLog(3)
+	repo.SetMutationLog(log)
+
+	for _, name := range []string{"tasks/a", "tasks/b"} {
+		if err := repo.CreateTask(ctx, &todopb.Task{Name: name, Title: name, TenantId: "t1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.UpdateTask(ctx, &todopb.Task{Name: "tasks/a", Title: "renamed", TenantId: "t1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.DeleteTask(ctx, "t1", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateTask(ctx, &todopb.Task{Name: "tasks/other", Title: "other", TenantId: "t2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The log holds three entries, so the first create has been overwritten
+	var got []string
+	for _, m := range log.Recent("t1") {
+		if m.User != "bob" || m.Time.IsZero() {
+			t.Errorf("mutation %+v lacks its user or time", m)
+		}
+		got = append(got, m.Action+" "+m.TaskID)
+	}
+	if want := []string{"UPDATE a", "DELETE b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("t1 mutations = %v, want %v", got, want)
+	}
+	if other := log.Recent("t2"); len(other) != 1 || other[0].TaskID != "other" {
+		t.Errorf("t2 mutations = %+v, want the one create", other)
+	}
+}
+
+func TestMutationLogRecordsNothingWhenUnset(t *testing.T) {
+	repo := NewInMemoryRepository(true)
+	if err := repo.CreateTask(context.Background(), &todopb.Task{Name: "tasks/a", Title: "a"}); err != nil {
+		t.Fatalf("CreateTask without a mutation log: %v", err)
+	}
+}
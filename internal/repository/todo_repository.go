@@ -2,11 +2,20 @@ package repository
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/duedate"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Common errors
@@ -14,17 +23,47 @@ var (
 	ErrNotFound      = errors.New("not found")
 	ErrAlreadyExists = errors.New("already exists")
 	ErrConnection    = errors.New("connection error")
+
+	// ErrIDExists and ErrTitleExists say which uniqueness rule a write broke;
+	// both also match ErrAlreadyExists
+	ErrIDExists    = fmt.Errorf("task ID %w", ErrAlreadyExists)
+	ErrTitleExists = fmt.Errorf("task title %w", ErrAlreadyExists)
+
+	// ErrInvalidPageToken is returned for a page token that is malformed or
+	// was issued for a different order or filter
+	ErrInvalidPageToken = errors.New("invalid page token")
 )
 
-// TodoRepository defines the interface for task storage
+// ctxCheckInterval is how many tasks a scan visits between context checks
+const ctxCheckInterval = 256
+
+// TodoRepository defines the interface for task storage. Every query is
+// scoped to a tenant: tasks are stored under their TenantId, and lookups only
+// see tasks in the tenant they are given, so IDs and titles may repeat across
+// tenants.
 type TodoRepository interface {
 	CreateTask(ctx context.Context, task *todopb.Task) error
-	GetTask(ctx context.Context, id string) (*todopb.Task, error)
-	GetTaskByTitle(ctx context.Context, title string) (*todopb.Task, error)
+	GetTask(ctx context.Context, tenantID, id string) (*todopb.Task, error)
+	GetTaskByTitle(ctx context.Context, tenantID, title string) (*todopb.Task, error)
 	UpdateTask(ctx context.Context, task *todopb.Task) error
-	DeleteTask(ctx context.Context, id string) error
+	DeleteTask(ctx context.Context, tenantID, id string) error
 	ListTasks(ctx context.Context, opts ListOptions) ([]*todopb.Task, string, error)
-	CountTasks(ctx context.Context, filter map[string]interface{}, userID string) (int, error)
+	CountTasks(ctx context.Context, filter map[string]interface{}, tenantID, userID string) (int, error)
+	TagCounts(ctx context.Context, tenantID, userID string) (map[string]int, error)
+	TaskStats(ctx context.Context, tenantID, userID string, now time.Time) (*TaskStats, error)
+
+	// Ping checks that the backing store is reachable, returning an error
+	// matching ErrConnection when it is not. Used by readiness checks.
+	Ping(ctx context.Context) error
+}
+
+// TaskStats holds aggregate counts over a user's tasks
+type TaskStats struct {
+	Total      int
+	ByStatus   map[todopb.Status]int
+	ByPriority map[todopb.Priority]int
+	Overdue    int
+	NextDue    *time.Time // earliest due date at or after now, nil if none
 }
 
 // ListOptions contains options for listing tasks
@@ -33,52 +72,118 @@ type ListOptions struct {
 	PageToken string
 	Filter    map[string]interface{}
 	OrderBy   string
+	TenantID  string
 	UserID    string
 }
 
 // InMemoryRepository is a simple in-memory implementation
 type InMemoryRepository struct {
 	mu    sync.RWMutex
-	tasks map[string]*todopb.Task
-	index map[string]string // title -> id index
+	tasks map[string]*todopb.Task // tenant-scoped id -> task
+	index map[string]string       // tenant-scoped title -> id index; nil when titles may repeat
+
+	mutations *MutationLog // recent writes for debugging; nil when disabled
 }
 
-// NewInMemoryRepository creates a new in-memory repository
-func NewInMemoryRepository() *InMemoryRepository {
-	return &InMemoryRepository{
+// NewInMemoryRepository creates a new in-memory repository. With uniqueTitles
+// set, titles must be unique within a tenant and are indexed for lookups;
+// otherwise no index is kept and GetTaskByTitle scans the tenant's tasks.
+func NewInMemoryRepository(uniqueTitles bool) *InMemoryRepository {
+	r := &InMemoryRepository{
 		tasks: make(map[string]*todopb.Task),
-		index: make(map[string]string),
 	}
+	if uniqueTitles {
+		r.index = make(map[string]string)
+	}
+	return r
 }
 
-func (r *InMemoryRepository) CreateTask(_ context.Context, task *todopb.Task) error {
+// SetMutationLog records every later create, update and delete in log
+func (r *InMemoryRepository) SetMutationLog(log *MutationLog) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mutations = log
+}
+
+func (r *InMemoryRepository) CreateTask(ctx context.Context, task *todopb.Task) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	id := extractID(task.Name)
+	key := scopedKey(task.TenantId, id)
 
 	// Check if already exists
-	if _, exists := r.tasks[id]; exists {
-		return ErrAlreadyExists
+	if _, exists := r.tasks[key]; exists {
+		return ErrIDExists
 	}
 
 	// Check title uniqueness
-	if existingID, exists := r.index[task.Title]; exists && existingID != id {
-		return ErrAlreadyExists
+	titleKey := scopedKey(task.TenantId, task.Title)
+	if existingID, exists := r.index[titleKey]; exists && existingID != id {
+		return ErrTitleExists
 	}
 
 	// Store task
-	r.tasks[id] = task
-	r.index[task.Title] = id
+	r.tasks[key] = task
+	if r.index != nil {
+		r.index[titleKey] = id
+	}
+	r.mutations.record(ctx, "CREATE", task.TenantId, id)
 
 	return nil
 }
 
-func (r *InMemoryRepository) GetTask(_ context.Context, id string) (*todopb.Task, error) {
+// Ping always succeeds; an in-memory store cannot be unreachable
+func (r *InMemoryRepository) Ping(_ context.Context) error {
+	return nil
+}
+
+// Seed bulk-loads tasks for fixtures, demos and benchmarks. It skips service
+// validation and defaults, so tasks are stored exactly as given, but the ID and
+// title uniqueness rules still apply and the title index is kept in step.
+// Nothing is stored if any task conflicts.
+func (r *InMemoryRepository) Seed(_ context.Context, tasks []*todopb.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make(map[string]bool, len(tasks))
+	titles := make(map[string]bool, len(tasks))
+	for i, task := range tasks {
+		id := extractID(task.Name)
+		if id == "" {
+			return fmt.Errorf("seed task %d has no name", i)
+		}
+		key := scopedKey(task.TenantId, id)
+		if _, exists := r.tasks[key]; exists || keys[key] {
+			return fmt.Errorf("seed task %s: %w", task.Name, ErrIDExists)
+		}
+		keys[key] = true
+
+		if r.index != nil {
+			titleKey := scopedKey(task.TenantId, task.Title)
+			if _, exists := r.index[titleKey]; exists || titles[titleKey] {
+				return fmt.Errorf("seed task %s: %w", task.Name, ErrTitleExists)
+			}
+			titles[titleKey] = true
+		}
+	}
+
+	for _, task := range tasks {
+		id := extractID(task.Name)
+		r.tasks[scopedKey(task.TenantId, id)] = task
+		if r.index != nil {
+			r.index[scopedKey(task.TenantId, task.Title)] = id
+		}
+	}
+
+	return nil
+}
+
+func (r *InMemoryRepository) GetTask(_ context.Context, tenantID, id string) (*todopb.Task, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	task, exists := r.tasks[id]
+	task, exists := r.tasks[scopedKey(tenantID, id)]
 	if !exists {
 		return nil, ErrNotFound
 	}
@@ -86,85 +191,128 @@ func (r *InMemoryRepository) GetTask(_ context.Context, id string) (*todopb.Task
 	return task, nil
 }
 
-func (r *InMemoryRepository) GetTaskByTitle(ctx context.Context, title string) (*todopb.Task, error) {
+func (r *InMemoryRepository) GetTaskByTitle(ctx context.Context, tenantID, title string) (*todopb.Task, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	id, exists := r.index[title]
+	if r.index == nil {
+		return r.findTaskByTitle(tenantID, title)
+	}
+
+	id, exists := r.index[scopedKey(tenantID, title)]
 	if !exists {
 		return nil, ErrNotFound
 	}
 
-	return r.GetTask(ctx, id)
+	return r.GetTask(ctx, tenantID, id)
 }
 
-func (r *InMemoryRepository) UpdateTask(_ context.Context, task *todopb.Task) error {
+// findTaskByTitle returns the oldest of the tenant's tasks with the title.
+// Used when there is no title index. Callers must hold r.mu.
+func (r *InMemoryRepository) findTaskByTitle(tenantID, title string) (*todopb.Task, error) {
+	var found *todopb.Task
+	for _, task := range r.tasks {
+		if task.TenantId != tenantID || task.Title != title {
+			continue
+		}
+		if found == nil || task.CreateTime.AsTime().Before(found.CreateTime.AsTime()) {
+			found = task
+		}
+	}
+
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return found, nil
+}
+
+func (r *InMemoryRepository) UpdateTask(ctx context.Context, task *todopb.Task) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	id := extractID(task.Name)
+	key := scopedKey(task.TenantId, id)
 
-	existing, exists := r.tasks[id]
+	existing, exists := r.tasks[key]
 	if !exists {
 		return ErrNotFound
 	}
 
 	// Update title index if changed
-	if existing.Title != task.Title {
-		delete(r.index, existing.Title)
+	if r.index != nil && existing.Title != task.Title {
+		titleKey := scopedKey(task.TenantId, task.Title)
 
 		// Check new title uniqueness
-		if existingID, exists := r.index[task.Title]; exists && existingID != id {
-			return ErrAlreadyExists
+		if existingID, exists := r.index[titleKey]; exists && existingID != id {
+			return ErrTitleExists
 		}
 
-		r.index[task.Title] = id
+		delete(r.index, scopedKey(task.TenantId, existing.Title))
+		r.index[titleKey] = id
 	}
 
-	r.tasks[id] = task
+	r.tasks[key] = task
+	r.mutations.record(ctx, "UPDATE", task.TenantId, id)
 	return nil
 }
 
-func (r *InMemoryRepository) DeleteTask(_ context.Context, id string) error {
+func (r *InMemoryRepository) DeleteTask(ctx context.Context, tenantID, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	task, exists := r.tasks[id]
+	key := scopedKey(tenantID, id)
+	task, exists := r.tasks[key]
 	if !exists {
 		return ErrNotFound
 	}
 
-	delete(r.tasks, id)
-	delete(r.index, task.Title)
+	delete(r.tasks, key)
+	if r.index != nil {
+		delete(r.index, scopedKey(tenantID, task.Title))
+	}
+	r.mutations.record(ctx, "DELETE", tenantID, id)
 
 	return nil
 }
 
-func (r *InMemoryRepository) ListTasks(_ context.Context, opts ListOptions) ([]*todopb.Task, string, error) {
+func (r *InMemoryRepository) ListTasks(ctx context.Context, opts ListOptions) ([]*todopb.Task, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Filter tasks
+	// Filter tasks, giving up early if the caller has gone away
 	var filtered []*todopb.Task
+	scanned := 0
 	for _, task := range r.tasks {
-		if r.matchesFilter(task, opts.Filter, opts.UserID) {
+		if scanned++; scanned%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, "", err
+			}
+		}
+		if r.matchesFilter(task, opts.Filter, opts.TenantID, opts.UserID) {
 			filtered = append(filtered, task)
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
 
 	// Sort tasks
 	sortTasks(filtered, opts.OrderBy)
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
 
-	// Paginate
+	// Paginate from the first task sorting after the cursor, so deleting the
+	// task a page ended on doesn't skip or repeat anything
+	cursor, err := decodePageToken(opts.PageToken, opts)
+	if err != nil {
+		return nil, "", err
+	}
 	start := 0
-	if opts.PageToken != "" {
-		// In production, decode proper page token
-		for i, task := range filtered {
-			if extractID(task.Name) == opts.PageToken {
-				start = i + 1
-				break
-			}
-		}
+	if cursor != nil {
+		start = sort.Search(len(filtered), func(i int) bool {
+			return taskLess(cursor, filtered[i], opts.OrderBy)
+		})
 	}
 
 	end := start + opts.PageSize
@@ -174,19 +322,25 @@ func (r *InMemoryRepository) ListTasks(_ context.Context, opts ListOptions) ([]*
 
 	var nextToken string
 	if end < len(filtered) {
-		nextToken = extractID(filtered[end-1].Name)
+		nextToken = encodePageToken(filtered[end-1], opts)
 	}
 
 	return filtered[start:end], nextToken, nil
 }
 
-func (r *InMemoryRepository) CountTasks(_ context.Context, filter map[string]interface{}, userID string) (int, error) {
+func (r *InMemoryRepository) CountTasks(ctx context.Context, filter map[string]interface{}, tenantID, userID string) (int, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	count := 0
+	scanned := 0
 	for _, task := range r.tasks {
-		if r.matchesFilter(task, filter, userID) {
+		if scanned++; scanned%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+		if r.matchesFilter(task, filter, tenantID, userID) {
 			count++
 		}
 	}
@@ -194,9 +348,69 @@ func (r *InMemoryRepository) CountTasks(_ context.Context, filter map[string]int
 	return count, nil
 }
 
+// TagCounts returns how many of the user's tasks carry each tag
+func (r *InMemoryRepository) TagCounts(_ context.Context, tenantID, userID string) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, task := range r.tasks {
+		if !r.matchesFilter(task, nil, tenantID, userID) {
+			continue
+		}
+		for _, tag := range task.Tags {
+			counts[tag]++
+		}
+	}
+
+	return counts, nil
+}
+
+// TaskStats aggregates the user's tasks. Completed and cancelled tasks are
+// counted by status and priority but are never overdue or upcoming.
+func (r *InMemoryRepository) TaskStats(_ context.Context, tenantID, userID string, now time.Time) (*TaskStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := &TaskStats{
+		ByStatus:   make(map[todopb.Status]int),
+		ByPriority: make(map[todopb.Priority]int),
+	}
+	for _, task := range r.tasks {
+		if !r.matchesFilter(task, nil, tenantID, userID) {
+			continue
+		}
+
+		stats.Total++
+		stats.ByStatus[task.Status]++
+		stats.ByPriority[task.Priority]++
+
+		if task.DueDate == nil || isFinished(task) {
+			continue
+		}
+		due := duedate.Deadline(task)
+		if due.Before(now) {
+			stats.Overdue++
+		} else if stats.NextDue == nil || due.Before(*stats.NextDue) {
+			stats.NextDue = &due
+		}
+	}
+
+	return stats, nil
+}
+
 // Helper functions
 
-func (r *InMemoryRepository) matchesFilter(task *todopb.Task, filter map[string]interface{}, userID string) bool {
+func isFinished(task *todopb.Task) bool {
+	return task.Status == todopb.Status_STATUS_COMPLETED || task.Status == todopb.Status_STATUS_CANCELLED
+}
+
+func (r *InMemoryRepository) matchesFilter(task *todopb.Task, filter map[string]interface{}, tenantID, userID string) bool {
+	// Never cross tenants, not even for admins
+	if task.TenantId != tenantID {
+		return false
+	}
+
 	// Check user access
 	if userID != "" && task.CreatedBy != userID && userID != "admin" {
 		return false
@@ -223,6 +437,11 @@ func (r *InMemoryRepository) matchesFilter(task *todopb.Task, filter map[string]
 	return true
 }
 
+// scopedKey namespaces an ID or title by tenant so tenants never collide
+func scopedKey(tenantID, key string) string {
+	return tenantID + "/" + key
+}
+
 func extractID(name string) string {
 	parts := strings.Split(name, "/")
 	if len(parts) == 2 {
@@ -231,36 +450,167 @@ func extractID(name string) string {
 	return name
 }
 
+// SortOrders lists the ListOptions.OrderBy values tasks can be sorted by.
+// Any other value sorts by title.
+var SortOrders = []string{"title", "create_time", "-create_time", "due_date", "priority", "-priority"}
+
+// IsSortOrder reports whether orderBy is one of SortOrders
+func IsSortOrder(orderBy string) bool {
+	for _, order := range SortOrders {
+		if order == orderBy {
+			return true
+		}
+	}
+	return false
+}
+
 func sortTasks(tasks []*todopb.Task, orderBy string) {
-	// Simple sorting implementation
 	sort.Slice(tasks, func(i, j int) bool {
-		switch orderBy {
-		case "create_time":
-			return tasks[i].CreateTime.AsTime().Before(tasks[j].CreateTime.AsTime())
-		case "-create_time":
-			return tasks[i].CreateTime.AsTime().After(tasks[j].CreateTime.AsTime())
-		case "due_date":
-			if tasks[i].DueDate == nil {
-				return false
-			}
-			if tasks[j].DueDate == nil {
-				return true
-			}
-			return tasks[i].DueDate.AsTime().Before(tasks[j].DueDate.AsTime())
-		default:
-			return tasks[i].Title < tasks[j].Title
-		}
+		return taskLess(tasks[i], tasks[j], orderBy)
 	})
 }
 
+// taskLess orders tasks by orderBy, breaking ties on the task ID so the order
+// is total and a page token can name an exact position in it
+func taskLess(a, b *todopb.Task, orderBy string) bool {
+	if sortKeyLess(a, b, orderBy) {
+		return true
+	}
+	if sortKeyLess(b, a, orderBy) {
+		return false
+	}
+	return extractID(a.Name) < extractID(b.Name)
+}
+
+func sortKeyLess(a, b *todopb.Task, orderBy string) bool {
+	switch orderBy {
+	case "create_time":
+		return timestampLess(a.CreateTime, b.CreateTime, false)
+	case "-create_time":
+		return timestampLess(a.CreateTime, b.CreateTime, true)
+	case "due_date":
+		return timestampLess(a.DueDate, b.DueDate, false)
+	case "priority":
+		return priorityRank[a.Priority] < priorityRank[b.Priority]
+	case "-priority":
+		return priorityRank[a.Priority] > priorityRank[b.Priority]
+	default:
+		return a.Title < b.Title
+	}
+}
+
+// priorityRank orders priorities by urgency. Sorting by the enum's name would
+// put HIGH before LOW before MEDIUM; unspecified ranks below LOW.
+var priorityRank = map[todopb.Priority]int{
+	todopb.Priority_PRIORITY_UNSPECIFIED: 0,
+	todopb.Priority_PRIORITY_LOW:         1,
+	todopb.Priority_PRIORITY_MEDIUM:      2,
+	todopb.Priority_PRIORITY_HIGH:        3,
+	todopb.Priority_PRIORITY_CRITICAL:    4,
+}
+
+// pageToken is the decoded form of a page token. It records the order and
+// filter of the query it was issued for, so it can't be replayed against a
+// different one where its position would mean something else.
+type pageToken struct {
+	Cursor  []byte `json:"c"`
+	OrderBy string `json:"o"`
+	Filter  string `json:"f"`
+}
+
+// encodePageToken captures the sort keys of the last task on a page. The next
+// page resumes after those values rather than after the task itself, so the
+// token stays valid if that task is deleted.
+func encodePageToken(task *todopb.Task, opts ListOptions) string {
+	cursor, err := proto.Marshal(&todopb.Task{
+		Name:       task.Name,
+		Title:      task.Title,
+		CreateTime: task.CreateTime,
+		DueDate:    task.DueDate,
+		Priority:   task.Priority,
+	})
+	if err != nil {
+		return ""
+	}
+	b, err := json.Marshal(pageToken{Cursor: cursor, OrderBy: opts.OrderBy, Filter: filterFingerprint(opts.Filter)})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodePageToken returns the cursor held in token, or nil for an empty token,
+// which starts from the first page. A token that can't be read or was issued
+// for a different order or filter than opts yields ErrInvalidPageToken.
+func decodePageToken(token string, opts ListOptions) (*todopb.Task, error) {
+	if token == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: not base64", ErrInvalidPageToken)
+	}
+	var decoded pageToken
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidPageToken)
+	}
+	if decoded.OrderBy != opts.OrderBy || decoded.Filter != filterFingerprint(opts.Filter) {
+		return nil, fmt.Errorf("%w: issued for a different order or filter", ErrInvalidPageToken)
+	}
+	cursor := &todopb.Task{}
+	if err := proto.Unmarshal(decoded.Cursor, cursor); err != nil || cursor.Name == "" {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPageToken)
+	}
+	return cursor, nil
+}
+
+// filterFingerprint identifies a parsed filter without putting its values in
+// the token. JSON encoding sorts map keys, so equal filters hash the same.
+func filterFingerprint(filter map[string]interface{}) string {
+	if len(filter) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(filter)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+// timestampLess orders timestamps ascending, or descending when desc is set.
+// Missing timestamps (e.g. on imported tasks) always sort last.
+func timestampLess(a, b *timestamppb.Timestamp, desc bool) bool {
+	if a == nil || b == nil {
+		return a != nil
+	}
+	if desc {
+		return a.AsTime().After(b.AsTime())
+	}
+	return a.AsTime().Before(b.AsTime())
+}
+
 func IsNotFound(err error) bool {
 	return errors.Is(err, ErrNotFound)
 }
 
+// IsInvalidPageToken reports whether a list failed because of its page token
+func IsInvalidPageToken(err error) bool {
+	return errors.Is(err, ErrInvalidPageToken)
+}
+
 func IsAlreadyExists(err error) bool {
 	return errors.Is(err, ErrAlreadyExists)
 }
 
+func IsIDExists(err error) bool {
+	return errors.Is(err, ErrIDExists)
+}
+
+func IsTitleExists(err error) bool {
+	return errors.Is(err, ErrTitleExists)
+}
+
 func IsConnectionError(err error) bool {
 	return errors.Is(err, ErrConnection)
 }
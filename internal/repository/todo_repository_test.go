@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSortTasksWithMissingTimestamps(t *testing.T) {
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) *timestamppb.Timestamp {
+		return timestamppb.New(base.Add(time.Duration(hours) * time.Hour))
+	}
+	newTasks := func() []*todopb.Task {
+		return []*todopb.Task{
+			{Name: "tasks/imported", CreateTime: nil, DueDate: at(1)},
+			{Name: "tasks/late", CreateTime: at(2), DueDate: nil},
+			{Name: "tasks/early", CreateTime: at(1), DueDate: at(3)},
+		}
+	}
+
+	for _, tc := range []struct {
+		orderBy string
+		want    []string
+	}{
+		{"create_time", []string{"tasks/early", "tasks/late", "tasks/imported"}},
+		{"-create_time", []string{"tasks/late", "tasks/early", "tasks/imported"}},
+		{"due_date", []string{"tasks/imported", "tasks/early", "tasks/late"}},
+	} {
+		t.Run(tc.orderBy, func(t *testing.T) {
+			tasks := newTasks()
+			sortTasks(tasks, tc.orderBy)
+
+			var got []string
+			for _, task := range tasks {
+				got = append(got, task.Name)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("sortTasks(%s) = %v, want %v", tc.orderBy, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCreateTaskConflictSentinels(t *testing.T) {
+	ctx := context.Background()
+	r := NewInMemoryRepository(true)
+	if err := r.CreateTask(ctx, &todopb.Task{Name: "tasks/a", Title: "Report", TenantId: "acme"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := r.CreateTask(ctx, &todopb.Task{Name: "tasks/a", Title: "Other", TenantId: "acme"})
+	if !IsIDExists(err) || IsTitleExists(err) || !IsAlreadyExists(err) {
+		t.Errorf("same ID = %v, want ErrIDExists", err)
+	}
+
+	err = r.CreateTask(ctx, &todopb.Task{Name: "tasks/b", Title: "Report", TenantId: "acme"})
+	if !IsTitleExists(err) || IsIDExists(err) || !IsAlreadyExists(err) {
+		t.Errorf("same title = %v, want ErrTitleExists", err)
+	}
+}
+
+func TestSeedThousandTasksAndPageThroughThem(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryRepository(true)
+
+	const n = 1000
+	tasks := make([]*todopb.Task, n)
+	for i := range tasks {
+		tasks[i] = &todopb.Task{
+			Name:     fmt.Sprintf("tasks/seed-%04d", i),
+			TenantId: "t1",
+			Title:    fmt.Sprintf("Seeded task %04d", i),
+		}
+	}
+	if err := repo.Seed(ctx, tasks); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+
+	opts := ListOptions{PageSize: 100, OrderBy: "title", TenantID: "t1"}
+	seen := make(map[string]bool)
+	var pages int
+	for {
+		page, next, err := repo.ListTasks(ctx, opts)
+		if err != nil {
+			t.Fatalf("ListTasks page %d: %v", pages, err)
+		}
+		pages++
+		for _, task := range page {
+			if seen[task.Name] {
+				t.Fatalf("%s returned twice", task.Name)
+			}
+			seen[task.Name] = true
+		}
+		if next == "" {
+			break
+		}
+		if len(page) != 100 {
+			t.Errorf("page %d has %d tasks but a next token, want a full page", pages, len(page))
+		}
+		if next == opts.PageToken {
+			t.Fatalf("page %d returned its own token %q again", pages, next)
+		}
+		opts.PageToken = next
+	}
+	if len(seen) != n || pages != n/100 {
+		t.Errorf("listed %d tasks over %d pages, want %d over %d", len(seen), pages, n, n/100)
+	}
+
+	// The title index is kept in step with the seeded tasks
+	if task, err := repo.GetTaskByTitle(ctx, "t1", "Seeded task 0500"); err != nil || task.Name != "tasks/seed-0500" {
+		t.Errorf("GetTaskByTitle = %v, %v; want tasks/seed-0500", task, err)
+	}
+}
+
+func TestSeedStoresNothingOnConflict(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryRepository(true)
+
+	err := repo.Seed(ctx, []*todopb.Task{
+		{Name: "tasks/a", TenantId: "t1", Title: "Same"},
+		{Name: "tasks/b", TenantId: "t1", Title: "Same"},
+	})
+	if !IsTitleExists(err) {
+		t.Fatalf("Seed with a repeated title = %v, want ErrTitleExists", err)
+	}
+	if _, err := repo.GetTask(ctx, "t1", "a"); !IsNotFound(err) {
+		t.Errorf("GetTask after a failed seed = %v, want not found", err)
+	}
+}
+
+// seedTasks stores n tasks in tenant "t1", cycling through titles so that
+// several tasks share each title and creation time
+func seedTasks(t *testing.T, repo *InMemoryRepository, n int, titles ...string) {
+	t.Helper()
+	created := timestamppb.Now()
+	tasks := make([]*todopb.Task, n)
+	for i := range tasks {
+		tasks[i] = &todopb.Task{
+			Name:       fmt.Sprintf("tasks/task-%03d", i),
+			TenantId:   "t1",
+			Title:      titles[i%len(titles)],
+			CreateTime: created,
+		}
+	}
+	if err := repo.Seed(context.Background(), tasks); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+}
+
+// listAll pages through every task for opts, failing on a repeated task
+func listAll(t *testing.T, repo *InMemoryRepository, opts ListOptions) []string {
+	t.Helper()
+	var names []string
+	seen := make(map[string]bool)
+	for page := 0; ; page++ {
+		if page > 100 {
+			t.Fatal("paging did not terminate")
+		}
+		tasks, next, err := repo.ListTasks(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("ListTasks: %v", err)
+		}
+		for _, task := range tasks {
+			if seen[task.Name] {
+				t.Fatalf("%s returned twice", task.Name)
+			}
+			seen[task.Name] = true
+			names = append(names, task.Name)
+		}
+		if next == "" {
+			return names
+		}
+		opts.PageToken = next
+	}
+}
+
+func TestListTasksPagesStablyOverDuplicateTitles(t *testing.T) {
+	for _, orderBy := range []string{"title", "create_time", "-create_time"} {
+		t.Run(orderBy, func(t *testing.T) {
+			repo := NewInMemoryRepository(false)
+			seedTasks(t, repo, 25, "Groceries", "Laundry", "Taxes")
+
+			opts := ListOptions{PageSize: 4, OrderBy: orderBy, TenantID: "t1"}
+			first := listAll(t, repo, opts)
+			if len(first) != 25 {
+				t.Fatalf("got %d tasks, want 25", len(first))
+			}
+
+			// Map iteration order varies, so repeated listings catch any
+			// order that depends on it
+			for i := 0; i < 5; i++ {
+				again := listAll(t, repo, opts)
+				for j := range first {
+					if again[j] != first[j] {
+						t.Fatalf("listing %d: position %d is %s, want %s", i, j, again[j], first[j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryPingAlwaysSucceeds(t *testing.T) {
+	if err := NewInMemoryRepository(true).Ping(context.Background()); err != nil {
+		t.Errorf("Ping = %v, want nil", err)
+	}
+}
+
+func TestListTasksSortsByPriorityRank(t *testing.T) {
+	repo := NewInMemoryRepository(true)
+	var tasks []*todopb.Task
+	for i, p := range []todopb.Priority{
+		todopb.Priority_PRIORITY_MEDIUM, todopb.Priority_PRIORITY_LOW, todopb.Priority_PRIORITY_CRITICAL,
+		todopb.Priority_PRIORITY_HIGH, todopb.Priority_PRIORITY_LOW,
+	} {
+		tasks = append(tasks, &todopb.Task{Name: fmt.Sprintf("tasks/p%d", i), Title: fmt.Sprintf("Task %d", i), TenantId: "t1", Priority: p})
+	}
+	if err := repo.Seed(context.Background(), tasks); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+
+	priorities := func(orderBy string) []string {
+		var got []string
+		opts := ListOptions{PageSize: 2, OrderBy: orderBy, TenantID: "t1"}
+		for _, name := range listAll(t, repo, opts) {
+			task, err := repo.GetTask(context.Background(), "t1", strings.TrimPrefix(name, "tasks/"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, strings.TrimPrefix(task.Priority.String(), "PRIORITY_"))
+		}
+		return got
+	}
+
+	if got, want := priorities("-priority"), []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "LOW"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("-priority = %v, want %v", got, want)
+	}
+	if got, want := priorities("priority"), []string{"LOW", "LOW", "MEDIUM", "HIGH", "CRITICAL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("priority = %v, want %v", got, want)
+	}
+}
+
+func TestListTasksResumesAfterDeletedBoundaryTask(t *testing.T) {
+	repo := NewInMemoryRepository(false)
+	seedTasks(t, repo, 6, "a", "b", "c", "d", "e", "f")
+	opts := ListOptions{PageSize: 2, OrderBy: "title", TenantID: "t1"}
+
+	first, next, err := repo.ListTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	boundary := first[len(first)-1]
+	if err := repo.DeleteTask(context.Background(), "t1", extractID(boundary.Name)); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+
+	opts.PageToken = next
+	second, _, err := repo.ListTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("ListTasks after deleting %s: %v", boundary.Name, err)
+	}
+	if len(second) != 2 || second[0].Title != "c" || second[1].Title != "d" {
+		var titles []string
+		for _, task := range second {
+			titles = append(titles, task.Title)
+		}
+		t.Errorf("second page = %v, want [c d]", titles)
+	}
+}
+
+func TestListTasksRejectsInvalidPageTokens(t *testing.T) {
+	repo := NewInMemoryRepository(false)
+	seedTasks(t, repo, 6, "a", "b", "c", "d", "e", "f")
+	opts := ListOptions{PageSize: 2, OrderBy: "title", TenantID: "t1"}
+	_, token, err := repo.ListTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts ListOptions
+	}{
+		{"not base64", ListOptions{PageSize: 2, OrderBy: "title", TenantID: "t1", PageToken: "!!!"}},
+		{"garbage", ListOptions{PageSize: 2, OrderBy: "title", TenantID: "t1", PageToken: "bm90IGEgdG9rZW4"}},
+		{"truncated", ListOptions{PageSize: 2, OrderBy: "title", TenantID: "t1", PageToken: token[:len(token)/2]}},
+		{"other order", ListOptions{PageSize: 2, OrderBy: "-create_time", TenantID: "t1", PageToken: token}},
+		{"other filter", ListOptions{PageSize: 2, OrderBy: "title", TenantID: "t1", PageToken: token,
+			Filter: map[string]interface{}{"status": "STATUS_PENDING"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := repo.ListTasks(context.Background(), tt.opts); !IsInvalidPageToken(err) {
+				t.Errorf("ListTasks = %v, want ErrInvalidPageToken", err)
+			}
+		})
+	}
+}
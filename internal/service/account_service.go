@@ -3,49 +3,120 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
+	"os"
 	"sync"
 	"time"
 
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
 	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/fieldmask"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"github.com/bhatti/todo-api-errors/internal/validation"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// Purposes a caller may give for reading PII
+const (
+	PurposeBilling = "BILLING"
+	PurposeSupport = "SUPPORT"
+	PurposeFraud   = "FRAUD"
+)
+
+var allowedPurposes = map[string]bool{
+	PurposeBilling: true,
+	PurposeSupport: true,
+	PurposeFraud:   true,
+}
+
+// purposeRoles names the role a caller needs to state a purpose. Purposes not
+// listed are open to any caller.
+var purposeRoles = map[string]string{
+	PurposeFraud: adminRole,
+}
+
+// immutableAccountFields are system fields that cannot be set through an update mask
+var immutableAccountFields = []string{"id", "account_number", "created_at", "updated_at"}
+
+// SSN searches are far riskier than ordinary API calls, so they get their own
+// much stricter per-caller limit
+const (
+	ssnSearchLimit  = 5
+	ssnSearchWindow = time.Minute
+)
+
 // AccountService implements the account CRUD operations
 // This is a demo service to showcase PII handling
 type AccountService struct {
 	pii.UnimplementedAccountServiceServer
-	mu       sync.RWMutex
-	accounts map[string]*pii.Account
+	writeMu    sync.Mutex // serializes read-modify-write cycles against repo
+	repo       repository.AccountRepository
+	tokenizer  Tokenizer // optional; when set, card numbers are stored as tokens
+	ssnLimiter *callerRateLimiter
+	maskPolicy MaskPolicy
+	audit      *AuditLogger
 }
 
 // NewAccountService creates a new account service
-func NewAccountService() *AccountService {
+func NewAccountService(repo repository.AccountRepository) *AccountService {
 	return &AccountService{
-		accounts: make(map[string]*pii.Account),
+		repo:       repo,
+		ssnLimiter: newCallerRateLimiter(ssnSearchLimit, ssnSearchWindow),
+		maskPolicy: DefaultMaskPolicy,
+		audit:      NewAuditLogger(os.Stdout, false),
 	}
 }
 
+// NewAccountServiceWithTokenizer creates an account service that tokenizes
+// credit card numbers on write to keep raw card data out of storage
+func NewAccountServiceWithTokenizer(repo repository.AccountRepository, tokenizer Tokenizer) *AccountService {
+	s := NewAccountService(repo)
+	s.tokenizer = tokenizer
+	return s
+}
+
+// SetMaskPolicy changes the mask character and how many trailing characters
+// of identifiers stay visible in masked responses
+func (s *AccountService) SetMaskPolicy(policy MaskPolicy) {
+	s.maskPolicy = policy
+}
+
+// SetAuditLogger replaces the PII audit log, e.g. with a hash-chained one
+// for tamper evidence
+func (s *AccountService) SetAuditLogger(audit *AuditLogger) {
+	s.audit = audit
+}
+
 // CreateAccount creates a new account
 func (s *AccountService) CreateAccount(ctx context.Context, req *pii.CreateAccountRequest) (*pii.Account, error) {
 	if req.Account == nil {
 		return nil, status.Error(codes.InvalidArgument, "account is required")
 	}
-
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	if err := validation.ValidateAccount(req.Account, monitoring.TraceIDFromContext(ctx)); err != nil {
+		return nil, err
+	}
 
 	// Generate ID if not provided
 	if req.Account.Id == "" {
 		req.Account.Id = uuid.New().String()
 	}
 
-	// Check for duplicate
-	if _, exists := s.accounts[req.Account.Id]; exists {
-		return nil, status.Errorf(codes.AlreadyExists, "account %s already exists", req.Account.Id)
+	// Replace the raw card number before it is stored
+	if err := s.tokenizeCard(ctx, req.Account); err != nil {
+		return nil, status.Error(codes.Internal, "failed to tokenize credit card number")
+	}
+
+	// New accounts start their lifecycle as PENDING unless given a status
+	if req.Account.Status == pii.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED {
+		req.Account.Status = pii.AccountStatus_PENDING
 	}
 
 	// Set timestamps
@@ -54,7 +125,9 @@ func (s *AccountService) CreateAccount(ctx context.Context, req *pii.CreateAccou
 	req.Account.UpdatedAt = now
 
 	// Store account
-	s.accounts[req.Account.Id] = req.Account
+	if err := s.repo.CreateAccount(ctx, req.Account); err != nil {
+		return nil, accountStoreError(err, req.Account.Id)
+	}
 
 	// Log PII access (in production, this would go to audit log)
 	s.logPIIAccess(ctx, "CREATE", req.Account.Id, "HIGH")
@@ -67,13 +140,25 @@ func (s *AccountService) GetAccount(ctx context.Context, req *pii.GetAccountRequ
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
+	if err := validatePurpose(req.Purpose); err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, "purpose", req.Purpose)
+	if err := s.authorizePurpose(ctx, req.Id, req.Purpose); err != nil {
+		return nil, err
+	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	// A path that names no field would otherwise come back as an empty
+	// value, indistinguishable from a masked one
+	if err := fieldmask.Validate(&pii.Account{}, req.ReadMask); err != nil {
+		return nil, errors.NewViolations().
+			Add("read_mask", errorspb.AppErrorCode_INVALID_FIELD_MASK, err.Error()).
+			Err(monitoring.TraceIDFromContext(ctx))
+	}
 
-	account, exists := s.accounts[req.Id]
-	if !exists {
-		return nil, status.Errorf(codes.NotFound, "account %s not found", req.Id)
+	account, err := s.repo.GetAccount(ctx, req.Id)
+	if err != nil {
+		return nil, accountStoreError(err, req.Id)
 	}
 
 	// Log PII access
@@ -84,10 +169,36 @@ func (s *AccountService) GetAccount(ctx context.Context, req *pii.GetAccountRequ
 	if !req.IncludeSensitiveData {
 		// In production, we would mask fields marked as HIGH sensitivity
 		// This is where PII masking logic would be applied
-		return s.maskSensitiveData(account), nil
+		return s.applyReadMask(s.maskSensitiveData(account), req.ReadMask), nil
+	}
+
+	// The stated purpose decides which fields come back unmasked
+	result := s.revealForPurpose(account, req.Purpose)
+
+	// Only admins may see the raw card number behind a token
+	if s.tokenizer != nil && isToken(result.CreditCardNumber) && s.isAdmin(ctx) {
+		cardNumber, err := s.tokenizer.Detokenize(ctx, result.CreditCardNumber)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to detokenize credit card number")
+		}
+		s.logPIIAccess(ctx, "DETOKENIZE", req.Id, "HIGH")
+		result.CreditCardNumber = cardNumber
 	}
 
-	return account, nil
+	return s.applyReadMask(result, req.ReadMask), nil
+}
+
+// applyReadMask keeps only the fields named in mask; an empty mask keeps all.
+// The mask must already have been validated.
+func (s *AccountService) applyReadMask(account *pii.Account, mask *fieldmaskpb.FieldMask) *pii.Account {
+	if len(mask.GetPaths()) == 0 {
+		return account
+	}
+	projected := &pii.Account{}
+	if err := fieldmask.Apply(projected, account, mask); err != nil {
+		return account
+	}
+	return projected
 }
 
 // UpdateAccount updates an existing account
@@ -96,31 +207,69 @@ func (s *AccountService) UpdateAccount(ctx context.Context, req *pii.UpdateAccou
 		return nil, status.Error(codes.InvalidArgument, "account with id is required")
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
-	existing, exists := s.accounts[req.Account.Id]
-	if !exists {
-		return nil, status.Errorf(codes.NotFound, "account %s not found", req.Account.Id)
+	existing, err := s.repo.GetAccount(ctx, req.Account.Id)
+	if err != nil {
+		return nil, accountStoreError(err, req.Account.Id)
 	}
 
-	// Apply updates based on field mask
+	// Build the new version on a clone so both paths behave the same way
+	var updated *pii.Account
 	if req.UpdateMask != nil && len(req.UpdateMask.Paths) > 0 {
-		s.applyFieldMask(existing, req.Account, req.UpdateMask.Paths)
+		// Apply updates based on field mask
+		updated = proto.Clone(existing).(*pii.Account)
+		if err := s.applyFieldMask(updated, req.Account, req.UpdateMask); err != nil {
+			return nil, err
+		}
 	} else {
 		// Full update
-		req.Account.CreatedAt = existing.CreatedAt
-		s.accounts[req.Account.Id] = req.Account
-		existing = req.Account
+		updated = proto.Clone(req.Account).(*pii.Account)
+	}
+
+	// System fields are never taken from the request, whichever path was used
+	if err := fieldmask.Apply(updated, existing, &fieldmaskpb.FieldMask{Paths: immutableAccountFields}); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	updated.UpdatedAt = timestamppb.Now()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	// Status only moves along the lifecycle; a full update without one keeps it
+	if updated.Status == pii.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED {
+		updated.Status = existing.Status
+	}
+	if reason := checkAccountTransition(existing.Status, updated.Status, s.isAdmin(ctx)); reason != "" {
+		return nil, errors.NewInconsistentState("account", reason, traceID)
+	}
+
+	if err := validation.ValidateAccount(updated, traceID); err != nil {
+		return nil, err
+	}
+
+	// Replace any newly supplied raw card number
+	if err := s.tokenizeCard(ctx, updated); err != nil {
+		return nil, status.Error(codes.Internal, "failed to tokenize credit card number")
+	}
+
+	if err := s.repo.UpdateAccount(ctx, updated); err != nil {
+		return nil, accountStoreError(err, updated.Id)
 	}
 
-	// Update timestamp
-	existing.UpdatedAt = timestamppb.Now()
+	// A replaced or cleared card leaves its raw number in the vault. It is
+	// only deleted once the update is stored, so a failed update keeps the
+	// card the stored account still points at.
+	if existing.CreditCardNumber != updated.CreditCardNumber {
+		if err := s.forgetCard(ctx, existing); err != nil {
+			log.Printf("Failed to delete replaced card token for account %s: %v", updated.Id, err)
+		}
+	}
 
 	// Log PII access
 	s.logPIIAccess(ctx, "UPDATE", req.Account.Id, "HIGH")
 
-	return existing, nil
+	return updated, nil
 }
 
 // DeleteAccount deletes an account
@@ -129,21 +278,39 @@ func (s *AccountService) DeleteAccount(ctx context.Context, req *pii.DeleteAccou
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	// Hard delete permanently destroys PII, so it is restricted to admins
+	// and every attempt is audited whether or not it is allowed
+	if req.HardDelete {
+		s.logPIIAccess(ctx, "HARD_DELETE_ATTEMPT", req.Id, "HIGH")
+		if !s.isAdmin(ctx) {
+			return nil, status.Error(codes.PermissionDenied, "hard delete requires admin privileges")
+		}
+	}
 
-	if _, exists := s.accounts[req.Id]; !exists {
-		return nil, status.Errorf(codes.NotFound, "account %s not found", req.Id)
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	account, err := s.repo.GetAccount(ctx, req.Id)
+	if err != nil {
+		return nil, accountStoreError(err, req.Id)
 	}
 
 	if req.HardDelete {
-		// Permanently delete
-		delete(s.accounts, req.Id)
+		// Permanently delete, including the raw card number in the vault
+		if err := s.forgetCard(ctx, account); err != nil {
+			return nil, status.Error(codes.Internal, "failed to delete tokenized credit card number")
+		}
+		if err := s.repo.DeleteAccount(ctx, req.Id); err != nil {
+			return nil, accountStoreError(err, req.Id)
+		}
 		s.logPIIAccess(ctx, "HARD_DELETE", req.Id, "LOW")
 	} else {
 		// Soft delete - just mark as closed
-		s.accounts[req.Id].Status = pii.AccountStatus_CLOSED
-		s.accounts[req.Id].UpdatedAt = timestamppb.Now()
+		account.Status = pii.AccountStatus_CLOSED
+		account.UpdatedAt = timestamppb.Now()
+		if err := s.repo.UpdateAccount(ctx, account); err != nil {
+			return nil, accountStoreError(err, req.Id)
+		}
 		s.logPIIAccess(ctx, "SOFT_DELETE", req.Id, "LOW")
 	}
 
@@ -152,9 +319,6 @@ func (s *AccountService) DeleteAccount(ctx context.Context, req *pii.DeleteAccou
 
 // ListAccounts lists all accounts with pagination
 func (s *AccountService) ListAccounts(ctx context.Context, req *pii.ListAccountsRequest) (*pii.ListAccountsResponse, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Simple pagination (in production, use proper cursor-based pagination)
 	pageSize := req.PageSize
 	if pageSize <= 0 {
@@ -164,13 +328,12 @@ func (s *AccountService) ListAccounts(ctx context.Context, req *pii.ListAccounts
 		pageSize = 100
 	}
 
-	var accounts []*pii.Account
-	for _, account := range s.accounts {
-		// Apply filter if provided
-		if req.Filter != "" && !s.matchesFilter(account, req.Filter) {
-			continue
-		}
-		accounts = append(accounts, account)
+	// Apply filter if provided
+	accounts, err := s.repo.SearchAccounts(ctx, func(account *pii.Account) bool {
+		return req.Filter == "" || s.matchesFilter(account, req.Filter)
+	})
+	if err != nil {
+		return nil, accountStoreError(err, "")
 	}
 
 	// Log PII access
@@ -200,15 +363,101 @@ func (s *AccountService) ListAccounts(ctx context.Context, req *pii.ListAccounts
 	}, nil
 }
 
+// BatchCreateAccounts creates each account in turn. An account that fails
+// does not stop the rest; its result carries the error instead. Each created
+// account is audited as its own CREATE.
+func (s *AccountService) BatchCreateAccounts(ctx context.Context, req *pii.BatchCreateAccountsRequest) (*pii.BatchAccountsResponse, error) {
+	if err := validation.ValidateBatchCreateAccounts(req, monitoring.TraceIDFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	results := make([]*pii.BatchAccountResult, len(req.Requests))
+	for i, createReq := range req.Requests {
+		account, err := s.CreateAccount(ctx, createReq)
+		results[i] = batchAccountResult(createReq.Account.GetId(), account, err)
+	}
+
+	return &pii.BatchAccountsResponse{Results: results}, nil
+}
+
+// BatchGetAccounts reads each account with the same purpose and masking as
+// GetAccount, so every account read is audited on its own
+func (s *AccountService) BatchGetAccounts(ctx context.Context, req *pii.BatchGetAccountsRequest) (*pii.BatchAccountsResponse, error) {
+	if err := validatePurpose(req.Purpose); err != nil {
+		return nil, err
+	}
+	if err := s.authorizePurpose(context.WithValue(ctx, "purpose", req.Purpose), "batch", req.Purpose); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateBatchGetAccounts(req, monitoring.TraceIDFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	results := make([]*pii.BatchAccountResult, len(req.Ids))
+	for i, id := range req.Ids {
+		account, err := s.GetAccount(ctx, &pii.GetAccountRequest{Id: id, Purpose: req.Purpose})
+		results[i] = batchAccountResult(id, account, err)
+	}
+
+	return &pii.BatchAccountsResponse{Results: results}, nil
+}
+
+// batchAccountResult reports one batch item, naming the error's app code when
+// it has one and its gRPC code otherwise
+func batchAccountResult(id string, account *pii.Account, err error) *pii.BatchAccountResult {
+	if err == nil {
+		return &pii.BatchAccountResult{Id: account.Id, Account: account}
+	}
+
+	result := &pii.BatchAccountResult{Id: id}
+	if appErr, ok := err.(*errors.AppError); ok {
+		result.ErrorCode = appErr.AppCode.String()
+		result.ErrorMessage = appErr.Detail
+	} else {
+		st := status.Convert(err)
+		result.ErrorCode = st.Code().String()
+		result.ErrorMessage = st.Message()
+	}
+	return result
+}
+
 // SearchAccounts searches accounts by PII fields
 func (s *AccountService) SearchAccounts(ctx context.Context, req *pii.SearchAccountsRequest) (*pii.SearchAccountsResponse, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	if err := validatePurpose(req.Purpose); err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, "purpose", req.Purpose)
+	if err := s.authorizePurpose(ctx, "search", req.Purpose); err != nil {
+		return nil, err
+	}
+	traceID := monitoring.TraceIDFromContext(ctx)
+	if err := validation.ValidateAccountSearch(req, traceID); err != nil {
+		return nil, err
+	}
 
-	var matches []*pii.Account
+	// Filter clauses must all match, on top of any single-field search below
+	filter, err := parseFilterExpression(req.Filter, accountFilterFields)
+	if err != nil {
+		if syntaxErr, ok := err.(*filterSyntaxError); ok {
+			return nil, errors.NewInvalidFilter(syntaxErr.token, syntaxErr.position, syntaxErr.reason, traceID)
+		}
+		return nil, errors.NewInvalidArgument(fmt.Sprintf("Failed to parse filter: %v", err), traceID)
+	}
+	fieldSearch := req.Name != "" || req.Email != "" || req.Phone != "" || req.Ssn != "" ||
+		req.DateOfBirth != "" || req.CreditCardLast4 != ""
+
+	// Throttle SSN lookups per caller to slow down enumeration
+	if req.Ssn != "" {
+		caller := s.callerID(ctx)
+		if allowed, retryAfter := s.ssnLimiter.Allow(caller); !allowed {
+			monitoring.RecordSecurityEvent(ctx, "ssn_search_rate_limited")
+			s.logPIIAccess(ctx, "SEARCH_SSN_BLOCKED", fmt.Sprintf("caller=%s blocked=%d", caller, s.ssnLimiter.Blocked(caller)), "HIGH")
+			return nil, errors.NewTooManyRequests("Too many SSN searches; please try again later", retryAfter, traceID)
+		}
+	}
 
 	// Search through accounts (simplified for demo)
-	for _, account := range s.accounts {
+	found, err := s.repo.SearchAccounts(ctx, func(account *pii.Account) bool {
 		matched := false
 
 		// Check each search field
@@ -229,10 +478,24 @@ func (s *AccountService) SearchAccounts(ctx context.Context, req *pii.SearchAcco
 		if req.DateOfBirth != "" && account.DateOfBirth == req.DateOfBirth {
 			matched = true
 		}
+		// Cards are matched on their last 4 digits, which tokens keep in the clear
+		if req.CreditCardLast4 != "" && account.CreditCardNumber != "" && lastN(account.CreditCardNumber, 4) == req.CreditCardLast4 {
+			matched = true
+			s.logPIIAccess(ctx, "SEARCH_CARD", account.Id, "HIGH")
+		}
 
-		if matched {
-			matches = append(matches, account)
+		if len(filter) > 0 {
+			return (matched || !fieldSearch) && matchesAccountFilter(account, filter)
 		}
+		return matched
+	})
+	if err != nil {
+		return nil, accountStoreError(err, "")
+	}
+
+	matches := make([]*pii.Account, len(found))
+	for i, account := range found {
+		matches[i] = s.revealForPurpose(account, req.Purpose)
 	}
 
 	// Log PII access
@@ -247,7 +510,14 @@ func (s *AccountService) SearchAccounts(ctx context.Context, req *pii.SearchAcco
 		pageSize = 100
 	}
 
-	end := int(pageSize)
+	start := 0
+	if req.PageToken != "" {
+		if _, err := fmt.Sscanf(req.PageToken, "search_%d", &start); err != nil || start < 0 || start > len(matches) {
+			return nil, errors.NewInvalidArgument(fmt.Sprintf("Invalid page token '%s'", req.PageToken), traceID)
+		}
+	}
+
+	end := start + int(pageSize)
 	if end > len(matches) {
 		end = len(matches)
 	}
@@ -258,58 +528,239 @@ func (s *AccountService) SearchAccounts(ctx context.Context, req *pii.SearchAcco
 	}
 
 	return &pii.SearchAccountsResponse{
-		Accounts:      matches[:end],
+		Accounts:      matches[start:end],
 		NextPageToken: nextPageToken,
 		TotalMatches:  int32(len(matches)),
 	}, nil
 }
 
+// ExportAccountData returns everything held about an account, unmasked, with
+// its audit history, to support GDPR data portability requests from the
+// account owner. Credential secrets are left out: they are not the subject's
+// personal data to port and would only weaken the account if the bundle leaked.
+func (s *AccountService) ExportAccountData(ctx context.Context, req *pii.ExportAccountDataRequest) (*pii.ExportAccountDataResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	account, err := s.repo.GetAccount(ctx, req.Id)
+	if err != nil {
+		return nil, accountStoreError(err, req.Id)
+	}
+
+	// Only the data subject or an admin may export the full record
+	if !s.isAdmin(ctx) && !s.isOwner(ctx, account) {
+		s.logPIIAccess(ctx, "EXPORT_DENIED", req.Id, "HIGH")
+		return nil, status.Error(codes.PermissionDenied, "export requires admin privileges or account ownership")
+	}
+
+	exported := proto.Clone(account).(*pii.Account)
+	exported.PasswordHash = ""
+	exported.CreditCardCvv = ""
+	exported.SecurityAnswer = ""
+	exported.ApiKey = ""
+	exported.AccessToken = ""
+	if s.tokenizer != nil && isToken(exported.CreditCardNumber) {
+		cardNumber, err := s.tokenizer.Detokenize(ctx, exported.CreditCardNumber)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to detokenize credit card number")
+		}
+		exported.CreditCardNumber = cardNumber
+	}
+
+	// Log PII access; the bundle's history includes this export
+	s.logPIIAccess(ctx, "EXPORT", req.Id, "HIGH")
+
+	history := s.audit.History(req.Id)
+	events := make([]*pii.AccountAuditEvent, len(history))
+	for i, event := range history {
+		events[i] = &pii.AccountAuditEvent{
+			Timestamp:   timestamppb.New(event.Timestamp),
+			Action:      event.Action,
+			Sensitivity: event.Sensitivity,
+			Purpose:     event.Purpose,
+			Caller:      event.Caller,
+		}
+	}
+
+	return &pii.ExportAccountDataResponse{
+		Account:     exported,
+		ExportedAt:  timestamppb.Now(),
+		AuditEvents: events,
+	}, nil
+}
+
+// EraseAccountData scrubs every PII field on an account but, unlike a hard
+// delete, keeps the record as a tombstone so references and audit history
+// still resolve
+func (s *AccountService) EraseAccountData(ctx context.Context, req *pii.EraseAccountDataRequest) (*pii.Account, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	account, err := s.repo.GetAccount(ctx, req.Id)
+	if err != nil {
+		return nil, accountStoreError(err, req.Id)
+	}
+
+	// Only the data subject or an admin may erase the record
+	if !s.isAdmin(ctx) && !s.isOwner(ctx, account) {
+		s.logPIIAccess(ctx, "ERASE_DENIED", req.Id, "HIGH")
+		return nil, status.Error(codes.PermissionDenied, "erasure requires admin privileges or account ownership")
+	}
+
+	// The raw card number lives in the vault, not on the record
+	if err := s.forgetCard(ctx, account); err != nil {
+		return nil, status.Error(codes.Internal, "failed to delete tokenized credit card number")
+	}
+
+	// Keep only system fields, plus a marker recording when the PII was erased
+	now := timestamppb.Now()
+	tombstone := &pii.Account{
+		Id:            account.Id,
+		AccountNumber: account.AccountNumber,
+		Status:        pii.AccountStatus_CLOSED,
+		CreatedAt:     account.CreatedAt,
+		UpdatedAt:     now,
+		Metadata: map[string]string{
+			"erased_at": now.AsTime().Format(time.RFC3339),
+		},
+	}
+	if err := s.repo.UpdateAccount(ctx, tombstone); err != nil {
+		return nil, accountStoreError(err, req.Id)
+	}
+
+	// Log PII access
+	s.logPIIAccess(ctx, "ERASE", req.Id, "HIGH")
+
+	return tombstone, nil
+}
+
 // Helper methods
 
 func (s *AccountService) maskSensitiveData(account *pii.Account) *pii.Account {
 	// Create a copy to avoid modifying the original
-	masked := *account
+	masked := proto.Clone(account).(*pii.Account)
+
+	p := s.maskPolicy
 
 	// Mask HIGH sensitivity fields
 	if masked.Ssn != "" {
-		masked.Ssn = "XXX-XX-" + lastN(masked.Ssn, 4)
+		masked.Ssn = p.identifier(masked.Ssn)
 	}
 	if masked.TaxId != "" {
-		masked.TaxId = "XX-XXX" + lastN(masked.TaxId, 4)
+		masked.TaxId = p.identifier(masked.TaxId)
 	}
 	if masked.PassportNumber != "" {
-		masked.PassportNumber = "XXXX" + lastN(masked.PassportNumber, 4)
+		masked.PassportNumber = p.identifier(masked.PassportNumber)
 	}
 	if masked.DriversLicense != "" {
-		masked.DriversLicense = "XXXX" + lastN(masked.DriversLicense, 4)
+		masked.DriversLicense = p.identifier(masked.DriversLicense)
 	}
 	if masked.BankAccountNumber != "" {
-		masked.BankAccountNumber = "****" + lastN(masked.BankAccountNumber, 4)
+		masked.BankAccountNumber = p.identifier(masked.BankAccountNumber)
 	}
 	if masked.CreditCardNumber != "" {
-		masked.CreditCardNumber = "****-****-****-" + lastN(masked.CreditCardNumber, 4)
+		masked.CreditCardNumber = p.identifier(masked.CreditCardNumber)
 	}
-	masked.CreditCardCvv = "***"
-	masked.PasswordHash = "********"
-	masked.SecurityAnswer = "********"
-	masked.ApiKey = "****"
-	masked.AccessToken = "****"
+	masked.CreditCardCvv = p.secret()
+	masked.PasswordHash = p.secret()
+	masked.SecurityAnswer = p.secret()
+	masked.ApiKey = p.secret()
+	masked.AccessToken = p.secret()
 
 	// Mask MEDIUM sensitivity fields partially
 	if masked.Email != "" {
-		masked.Email = maskEmail(masked.Email)
+		masked.Email = p.email(masked.Email)
 	}
 	if masked.PersonalEmail != "" {
-		masked.PersonalEmail = maskEmail(masked.PersonalEmail)
+		masked.PersonalEmail = p.email(masked.PersonalEmail)
 	}
 	if masked.Phone != "" {
-		masked.Phone = maskPhone(masked.Phone)
+		masked.Phone = p.identifier(masked.Phone)
 	}
 	if masked.MobilePhone != "" {
-		masked.MobilePhone = maskPhone(masked.MobilePhone)
+		masked.MobilePhone = p.identifier(masked.MobilePhone)
 	}
 
-	return &masked
+	return masked
+}
+
+// revealForPurpose returns a copy of the account with only the fields the
+// purpose justifies left unmasked. Credential secrets such as the password
+// hash, CVV and API keys stay masked for every purpose.
+func (s *AccountService) revealForPurpose(account *pii.Account, purpose string) *pii.Account {
+	revealed := s.maskSensitiveData(account)
+	switch purpose {
+	case PurposeFraud:
+		// Fraud investigations need every identifier, but never credentials
+		revealed.Ssn = account.Ssn
+		revealed.TaxId = account.TaxId
+		revealed.PassportNumber = account.PassportNumber
+		revealed.DriversLicense = account.DriversLicense
+		revealed.BankAccountNumber = account.BankAccountNumber
+		revealed.RoutingNumber = account.RoutingNumber
+		revealed.CreditCardNumber = account.CreditCardNumber
+		revealed.CreditCardExpiry = account.CreditCardExpiry
+		revealed.Email = account.Email
+		revealed.PersonalEmail = account.PersonalEmail
+		revealed.Phone = account.Phone
+		revealed.MobilePhone = account.MobilePhone
+	case PurposeBilling:
+		revealed.BankAccountNumber = account.BankAccountNumber
+		revealed.RoutingNumber = account.RoutingNumber
+		revealed.CreditCardNumber = account.CreditCardNumber
+		revealed.CreditCardExpiry = account.CreditCardExpiry
+	case PurposeSupport:
+		revealed.Email = account.Email
+		revealed.PersonalEmail = account.PersonalEmail
+		revealed.Phone = account.Phone
+		revealed.MobilePhone = account.MobilePhone
+	}
+	return revealed
+}
+
+// authorizePurpose checks that the caller holds the role the purpose needs.
+// Refusals are audited against resourceID.
+func (s *AccountService) authorizePurpose(ctx context.Context, resourceID, purpose string) error {
+	role, ok := purposeRoles[purpose]
+	if !ok || s.callerID(ctx) == role {
+		return nil
+	}
+	s.logPIIAccess(ctx, "PURPOSE_DENIED", resourceID, "HIGH")
+	return errors.NewPermissionDenied("account", "read for "+purpose, role, monitoring.TraceIDFromContext(ctx))
+}
+
+// accountFilterFields are the fields accepted in SearchAccounts filters
+var accountFilterFields = []FilterField{
+	{Name: "name", Type: "string", Operators: []string{"="}},
+	{Name: "email", Type: "string", Operators: []string{"="}},
+	{Name: "status", Type: "enum", Operators: []string{"="}, Values: enumValues(pii.AccountStatus_name)},
+}
+
+// matchesAccountFilter reports whether an account matches every parsed filter
+// clause. Names match either the first or last name, emails either address.
+func matchesAccountFilter(account *pii.Account, filter map[string]interface{}) bool {
+	for key, value := range filter {
+		switch key {
+		case "name":
+			if account.FirstName != value && account.LastName != value {
+				return false
+			}
+		case "email":
+			if account.Email != value && account.PersonalEmail != value {
+				return false
+			}
+		case "status":
+			if account.Status.String() != value {
+				return false
+			}
+		}
+	}
+	return true
 }
 
 func (s *AccountService) matchesFilter(account *pii.Account, filter string) bool {
@@ -321,64 +772,97 @@ func (s *AccountService) matchesFilter(account *pii.Account, filter string) bool
 	return true
 }
 
-func (s *AccountService) applyFieldMask(target, source *pii.Account, paths []string) {
-	// Apply field mask updates
-	for _, path := range paths {
-		switch path {
-		case "first_name":
-			target.FirstName = source.FirstName
-		case "last_name":
-			target.LastName = source.LastName
-		case "email":
-			target.Email = source.Email
-		case "phone":
-			target.Phone = source.Phone
-		case "home_address":
-			target.HomeAddress = source.HomeAddress
-		// Add more fields as needed
-		}
+// applyFieldMask copies the masked fields from source onto target. System
+// fields are immutable and unknown paths are rejected rather than ignored.
+func (s *AccountService) applyFieldMask(target, source *pii.Account, mask *fieldmaskpb.FieldMask) error {
+	if err := fieldmask.Apply(target, source, mask, immutableAccountFields...); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+func (s *AccountService) tokenizeCard(ctx context.Context, account *pii.Account) error {
+	if s.tokenizer == nil || account.CreditCardNumber == "" || isToken(account.CreditCardNumber) {
+		return nil
+	}
+
+	token, err := s.tokenizer.Tokenize(ctx, account.CreditCardNumber)
+	if err != nil {
+		return err
+	}
+	account.CreditCardNumber = token
+	return nil
+}
+
+// forgetCard deletes the vaulted card number behind the account's token, if any
+func (s *AccountService) forgetCard(ctx context.Context, account *pii.Account) error {
+	if s.tokenizer == nil || !isToken(account.CreditCardNumber) {
+		return nil
 	}
+	return s.tokenizer.Delete(ctx, account.CreditCardNumber)
+}
+
+func (s *AccountService) isAdmin(ctx context.Context) bool {
+	// In a real implementation, this would check roles from the auth context
+	user, _ := ctx.Value("user").(string)
+	return user == "admin"
+}
+
+func (s *AccountService) callerID(ctx context.Context) string {
+	if user, ok := ctx.Value("user").(string); ok && user != "" {
+		return user
+	}
+	return "anonymous"
+}
+
+func (s *AccountService) isOwner(ctx context.Context, account *pii.Account) bool {
+	user, _ := ctx.Value("user").(string)
+	return user != "" && user == account.Username
 }
 
 func (s *AccountService) logPIIAccess(ctx context.Context, action, resourceID, sensitivity string) {
-	// In production, this would write to an audit log
-	// For demo, we'll just print to stdout
-	timestamp := time.Now().Format(time.RFC3339)
-	fmt.Printf("[PII_AUDIT] %s | Action=%s | Resource=%s | Sensitivity=%s | Context=%v\n",
-		timestamp, action, resourceID, sensitivity, ctx)
+	monitoring.RecordPIIAccess(ctx, action, sensitivity)
+
+	purpose, _ := ctx.Value("purpose").(string)
+	if err := s.audit.Log(AuditEvent{
+		Timestamp:   time.Now().UTC(),
+		Action:      action,
+		Resource:    resourceID,
+		Sensitivity: sensitivity,
+		Purpose:     purpose,
+		Caller:      s.callerID(ctx),
+	}); err != nil {
+		log.Printf("Failed to write PII audit event %s on %s: %v", action, resourceID, err)
+	}
 }
 
 // Utility functions
 
-func lastN(s string, n int) string {
-	if len(s) <= n {
-		return s
+// accountStoreError maps repository errors onto gRPC statuses
+func accountStoreError(err error, id string) error {
+	switch {
+	case repository.IsNotFound(err):
+		return status.Errorf(codes.NotFound, "account %s not found", id)
+	case repository.IsAlreadyExists(err):
+		return status.Errorf(codes.AlreadyExists, "account %s already exists", id)
+	default:
+		return status.Error(codes.Internal, "failed to access account storage")
 	}
-	return s[len(s)-n:]
 }
 
-func maskEmail(email string) string {
-	// Simple email masking: show first 2 chars and domain
-	if len(email) < 3 {
-		return "***"
-	}
-	atIndex := -1
-	for i, ch := range email {
-		if ch == '@' {
-			atIndex = i
-			break
-		}
+func validatePurpose(purpose string) error {
+	if purpose == "" {
+		return status.Error(codes.InvalidArgument, "purpose is required")
 	}
-	if atIndex < 0 {
-		return email[:2] + "***"
+	if !allowedPurposes[purpose] {
+		return status.Errorf(codes.InvalidArgument, "purpose %q is not allowed; must be one of BILLING, SUPPORT, FRAUD", purpose)
 	}
-	return email[:2] + "***" + email[atIndex:]
+	return nil
 }
 
-func maskPhone(phone string) string {
-	// Simple phone masking: show last 4 digits
-	if len(phone) <= 4 {
-		return phone
+func lastN(s string, n int) string {
+	if len(s) <= n {
+		return s
 	}
-	return "XXX-XXX-" + lastN(phone, 4)
-}
\ No newline at end of file
+	return s[len(s)-n:]
+}
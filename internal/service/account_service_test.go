@@ -0,0 +1,619 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newTestAccountService returns a service over an empty in-memory store whose
+// audit events are discarded
+func newTestAccountService() *AccountService {
+	s := NewAccountService(repository.NewInMemoryAccountRepository())
+	s.SetAuditLogger(NewAuditLogger(io.Discard, false))
+	return s
+}
+
+func asUser(user string) context.Context {
+	return context.WithValue(context.Background(), "user", user)
+}
+
+func TestSearchAccountsCombinesFilterClausesAndPages(t *testing.T) {
+	s := newTestAccountService()
+	for i, status := range []pii.AccountStatus{
+		pii.AccountStatus_ACTIVE, pii.AccountStatus_SUSPENDED, pii.AccountStatus_ACTIVE,
+		pii.AccountStatus_ACTIVE, pii.AccountStatus_CLOSED,
+	} {
+		if _, err := s.CreateAccount(asUser("admin"), &pii.CreateAccountRequest{Account: &pii.Account{
+			Username:  fmt.Sprintf("smith%d", i),
+			FirstName: "Pat",
+			LastName:  "Smith",
+			Ssn:       "123-45-6789",
+			Status:    status,
+		}}); err != nil {
+			t.Fatalf("CreateAccount: %v", err)
+		}
+	}
+	if _, err := s.CreateAccount(asUser("admin"), &pii.CreateAccountRequest{Account: &pii.Account{
+		Username: "jones", FirstName: "Pat", LastName: "Jones", Status: pii.AccountStatus_ACTIVE,
+	}}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	req := &pii.SearchAccountsRequest{Purpose: PurposeSupport, Filter: "name=Smith AND status=ACTIVE", PageSize: 2}
+	var seen []string
+	for page := 0; ; page++ {
+		resp, err := s.SearchAccounts(asUser("support"), req)
+		if err != nil {
+			t.Fatalf("SearchAccounts page %d: %v", page, err)
+		}
+		if resp.TotalMatches != 3 {
+			t.Errorf("page %d: total matches = %d, want 3", page, resp.TotalMatches)
+		}
+		for _, account := range resp.Accounts {
+			if account.LastName != "Smith" || account.Status != pii.AccountStatus_ACTIVE {
+				t.Errorf("unexpected match %s %s %v", account.Username, account.LastName, account.Status)
+			}
+			if account.Ssn == "123-45-6789" {
+				t.Errorf("%s: SSN returned unmasked for a SUPPORT search", account.Username)
+			}
+			seen = append(seen, account.Username)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	if len(seen) != 3 {
+		t.Errorf("paged through %v, want 3 accounts", seen)
+	}
+}
+
+func TestSearchAccountsRejectsBadFilter(t *testing.T) {
+	s := newTestAccountService()
+	_, err := s.SearchAccounts(asUser("support"), &pii.SearchAccountsRequest{Purpose: PurposeSupport, Filter: "status=GONE"})
+	if err == nil {
+		t.Fatal("SearchAccounts with an unknown status = nil error, want one")
+	}
+}
+
+func TestGetAccountRejectsUnknownReadMaskPath(t *testing.T) {
+	s := newTestAccountService()
+	account, err := s.CreateAccount(asUser("alice"), &pii.CreateAccountRequest{Account: &pii.Account{
+		Username: "alice", FirstName: "Alice", LastName: "Smith", Email: "alice@example.com",
+	}})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	_, err = s.GetAccount(asUser("alice"), &pii.GetAccountRequest{
+		Id: account.Id, Purpose: PurposeSupport, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"first_name", "favourite_colour"}},
+	})
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || len(appErr.FieldViolations) != 1 ||
+		appErr.FieldViolations[0].Code != errorspb.AppErrorCode_INVALID_FIELD_MASK.String() ||
+		!strings.Contains(appErr.FieldViolations[0].Description, "favourite_colour") {
+		t.Fatalf("err = %v, want an INVALID_FIELD_MASK violation naming the path", err)
+	}
+
+	got, err := s.GetAccount(asUser("alice"), &pii.GetAccountRequest{
+		Id: account.Id, Purpose: PurposeSupport, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"first_name"}},
+	})
+	if err != nil {
+		t.Fatalf("GetAccount: %v", err)
+	}
+	if got.FirstName != "Alice" || got.LastName != "" || got.Email != "" {
+		t.Errorf("masked read = %v, want only first_name", got)
+	}
+}
+
+func TestBatchCreateAccountsReportsEachItem(t *testing.T) {
+	var audit bytes.Buffer
+	s := NewAccountService(repository.NewInMemoryAccountRepository())
+	s.SetAuditLogger(NewAuditLogger(&audit, false))
+
+	if _, err := s.CreateAccount(asUser("admin"), &pii.CreateAccountRequest{Account: &pii.Account{Id: "taken", Username: "first"}}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	audit.Reset()
+
+	resp, err := s.BatchCreateAccounts(asUser("admin"), &pii.BatchCreateAccountsRequest{Requests: []*pii.CreateAccountRequest{
+		{Account: &pii.Account{Id: "new-1", Username: "one"}},
+		{Account: &pii.Account{Id: "taken", Username: "two"}},
+		{Account: &pii.Account{Id: "new-2", Username: "three"}},
+	}})
+	if err != nil {
+		t.Fatalf("BatchCreateAccounts: %v", err)
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("results = %v, want 3", resp.Results)
+	}
+	for i, wantErr := range []bool{false, true, false} {
+		result := resp.Results[i]
+		if (result.ErrorCode != "") != wantErr || (result.Account == nil) != wantErr {
+			t.Errorf("result %d = %v, want error %v", i, result, wantErr)
+		}
+	}
+	if resp.Results[1].Id != "taken" {
+		t.Errorf("failed result id = %q, want taken", resp.Results[1].Id)
+	}
+
+	// Each created account is audited on its own
+	if creates := strings.Count(audit.String(), `"action":"CREATE"`); creates != 2 {
+		t.Errorf("%d CREATE audit events, want 2:\n%s", creates, audit.String())
+	}
+}
+
+func TestBatchCreateAccountsRejectsInvalidBatch(t *testing.T) {
+	s := newTestAccountService()
+	_, err := s.BatchCreateAccounts(asUser("admin"), &pii.BatchCreateAccountsRequest{Requests: []*pii.CreateAccountRequest{
+		{Account: &pii.Account{Username: "ok"}},
+		{},
+	}})
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || len(appErr.FieldViolations) != 1 || appErr.FieldViolations[0].Field != "requests[1].account" {
+		t.Errorf("err = %v, want a requests[1].account violation", err)
+	}
+}
+
+// piiAccessCount reads the pii_access_total counter for one action and sensitivity
+func piiAccessCount(t *testing.T, action, sensitivity string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "pii_access_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["action"] == action && labels["sensitivity"] == sensitivity {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestGetAccountCountsHighSensitivityAccess(t *testing.T) {
+	s := newTestAccountService()
+	account, err := s.CreateAccount(asUser("alice"), &pii.CreateAccountRequest{Account: &pii.Account{Username: "alice"}})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	before := piiAccessCount(t, "READ", "HIGH")
+	if _, err := s.GetAccount(asUser("alice"), &pii.GetAccountRequest{Id: account.Id, Purpose: PurposeSupport}); err != nil {
+		t.Fatalf("GetAccount: %v", err)
+	}
+	if got := piiAccessCount(t, "READ", "HIGH") - before; got != 1 {
+		t.Errorf("pii_access_total{action=READ,sensitivity=HIGH} rose by %v, want 1", got)
+	}
+}
+
+// createTestAccount stores an account owned by "alice" with every kind of PII
+func createTestAccount(t *testing.T, s *AccountService) *pii.Account {
+	t.Helper()
+	account, err := s.CreateAccount(asUser("alice"), &pii.CreateAccountRequest{Account: &pii.Account{
+		Username:          "alice",
+		FirstName:         "Alice",
+		LastName:          "Smith",
+		Email:             "alice@example.com",
+		Phone:             "+14155552671",
+		Ssn:               "123-45-6789",
+		PassportNumber:    "X1234567",
+		BankAccountNumber: "000123456789",
+		CreditCardNumber:  "4111111111111111",
+		CreditCardCvv:     "123",
+		PasswordHash:      "$2a$10$hash",
+		SecurityAnswer:    "Rex",
+		ApiKey:            "sk_live_key",
+		AccessToken:       "token-abc",
+		AccountNumber:     "ACC-0001",
+	}})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	return account
+}
+
+// errorCode is the gRPC code err reaches clients with, for AppErrors and
+// plain statuses alike
+func errorCode(err error) codes.Code {
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		return appErr.GRPCCode
+	}
+	return status.Code(err)
+}
+
+// assertSecretsMasked fails if any credential secret on account is readable
+func assertSecretsMasked(t *testing.T, account *pii.Account) {
+	t.Helper()
+	hidden := DefaultMaskPolicy.secret()
+	for name, value := range map[string]string{
+		"password_hash":   account.PasswordHash,
+		"credit_card_cvv": account.CreditCardCvv,
+		"security_answer": account.SecurityAnswer,
+		"api_key":         account.ApiKey,
+		"access_token":    account.AccessToken,
+	} {
+		if value != "" && value != hidden {
+			t.Errorf("%s = %q, want it masked", name, value)
+		}
+	}
+}
+
+func TestGetAccountPurposes(t *testing.T) {
+	s := newTestAccountService()
+	account := createTestAccount(t, s)
+
+	tests := []struct {
+		name     string
+		user     string
+		purpose  string
+		wantCode codes.Code
+	}{
+		{"billing for any caller", "bob", PurposeBilling, codes.OK},
+		{"support for any caller", "bob", PurposeSupport, codes.OK},
+		{"fraud for admin", "admin", PurposeFraud, codes.OK},
+		{"fraud for non-admin", "bob", PurposeFraud, codes.PermissionDenied},
+		{"unknown purpose", "admin", "MARKETING", codes.InvalidArgument},
+		{"missing purpose", "admin", "", codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.GetAccount(asUser(tt.user), &pii.GetAccountRequest{
+				Id:                   account.Id,
+				Purpose:              tt.purpose,
+				IncludeSensitiveData: true,
+			})
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("code = %v (%v), want %v", code, err, tt.wantCode)
+			}
+			if err == nil {
+				assertSecretsMasked(t, got)
+			}
+		})
+	}
+}
+
+func TestFraudPurposeRevealsIdentifiersButNotSecrets(t *testing.T) {
+	s := newTestAccountService()
+	account := createTestAccount(t, s)
+
+	got, err := s.GetAccount(asUser("admin"), &pii.GetAccountRequest{
+		Id:                   account.Id,
+		Purpose:              PurposeFraud,
+		IncludeSensitiveData: true,
+	})
+	if err != nil {
+		t.Fatalf("GetAccount: %v", err)
+	}
+	if got.Ssn != "123-45-6789" || got.PassportNumber != "X1234567" || got.Email != "alice@example.com" {
+		t.Errorf("identifiers masked for FRAUD: ssn=%q passport=%q email=%q", got.Ssn, got.PassportNumber, got.Email)
+	}
+	assertSecretsMasked(t, got)
+}
+
+func TestSearchAccountsFraudRequiresAdmin(t *testing.T) {
+	s := newTestAccountService()
+	createTestAccount(t, s)
+
+	if _, err := s.SearchAccounts(asUser("bob"), &pii.SearchAccountsRequest{Name: "Alice", Purpose: PurposeFraud}); err == nil {
+		t.Fatal("non-admin FRAUD search succeeded")
+	}
+
+	resp, err := s.SearchAccounts(asUser("admin"), &pii.SearchAccountsRequest{Name: "Alice", Purpose: PurposeFraud})
+	if err != nil {
+		t.Fatalf("SearchAccounts: %v", err)
+	}
+	if len(resp.Accounts) != 1 {
+		t.Fatalf("got %d accounts, want 1", len(resp.Accounts))
+	}
+	assertSecretsMasked(t, resp.Accounts[0])
+}
+
+// credentialFields are never handed out, masked or otherwise, in exports
+var credentialFields = map[string]bool{
+	"password_hash":   true,
+	"credit_card_cvv": true,
+	"security_answer": true,
+	"api_key":         true,
+	"access_token":    true,
+}
+
+func TestExportAccountDataIncludesPopulatedFields(t *testing.T) {
+	s := newTestAccountService()
+	account := createTestAccount(t, s)
+	stored, err := s.repo.GetAccount(context.Background(), account.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.ExportAccountData(asUser("alice"), &pii.ExportAccountDataRequest{Id: account.Id})
+	if err != nil {
+		t.Fatalf("ExportAccountData: %v", err)
+	}
+
+	exported := resp.Account.ProtoReflect()
+	stored.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+		if credentialFields[name] {
+			if exported.Has(fd) {
+				t.Errorf("%s exported", name)
+			}
+			return true
+		}
+		if !exported.Has(fd) || !exported.Get(fd).Equal(v) {
+			t.Errorf("%s = %v, want %v", name, exported.Get(fd), v)
+		}
+		return true
+	})
+
+	var actions []string
+	for _, event := range resp.AuditEvents {
+		actions = append(actions, event.Action)
+	}
+	if len(actions) < 2 || actions[0] != "CREATE" || actions[len(actions)-1] != "EXPORT" {
+		t.Errorf("audit actions = %v, want CREATE first and EXPORT last", actions)
+	}
+}
+
+func TestExportAccountDataRequiresOwnerOrAdmin(t *testing.T) {
+	s := newTestAccountService()
+	account := createTestAccount(t, s)
+
+	if _, err := s.ExportAccountData(asUser("bob"), &pii.ExportAccountDataRequest{Id: account.Id}); errorCode(err) != codes.PermissionDenied {
+		t.Fatalf("export by another user: %v, want PermissionDenied", err)
+	}
+	if _, err := s.ExportAccountData(asUser("admin"), &pii.ExportAccountDataRequest{Id: account.Id}); err != nil {
+		t.Fatalf("export by admin: %v", err)
+	}
+}
+
+func TestEraseAccountDataScrubsPIIButKeepsTombstone(t *testing.T) {
+	tokenizer := NewInMemoryTokenizer()
+	s := NewAccountServiceWithTokenizer(repository.NewInMemoryAccountRepository(), tokenizer)
+	s.SetAuditLogger(NewAuditLogger(io.Discard, false))
+	account := createTestAccount(t, s)
+	cardToken := account.CreditCardNumber
+	if !isToken(cardToken) {
+		t.Fatalf("card number %q was not tokenized", cardToken)
+	}
+
+	erased, err := s.EraseAccountData(asUser("alice"), &pii.EraseAccountDataRequest{Id: account.Id})
+	if err != nil {
+		t.Fatalf("EraseAccountData: %v", err)
+	}
+
+	stored, err := s.repo.GetAccount(context.Background(), account.Id)
+	if err != nil {
+		t.Fatalf("tombstone missing: %v", err)
+	}
+	for _, got := range []*pii.Account{erased, stored} {
+		if got.Id != account.Id || got.AccountNumber != account.AccountNumber {
+			t.Errorf("id/account number = %q/%q, want %q/%q", got.Id, got.AccountNumber, account.Id, account.AccountNumber)
+		}
+		if got.Metadata["erased_at"] == "" {
+			t.Error("erased_at marker missing")
+		}
+		for _, value := range []string{got.Username, got.FirstName, got.LastName, got.Email, got.Phone,
+			got.Ssn, got.PassportNumber, got.BankAccountNumber, got.CreditCardNumber, got.CreditCardCvv,
+			got.PasswordHash, got.SecurityAnswer, got.ApiKey, got.AccessToken} {
+			if value != "" {
+				t.Errorf("PII value %q survived erasure", value)
+			}
+		}
+	}
+
+	if _, err := tokenizer.Detokenize(context.Background(), cardToken); err != ErrUnknownToken {
+		t.Errorf("vaulted card number still readable after erasure: %v", err)
+	}
+
+	var actions []string
+	for _, event := range s.audit.History(account.Id) {
+		actions = append(actions, event.Action)
+	}
+	if len(actions) < 2 || actions[0] != "CREATE" || actions[len(actions)-1] != "ERASE" {
+		t.Errorf("audit trail = %v, want CREATE ... ERASE", actions)
+	}
+}
+
+func TestHardDeleteForgetsVaultedCard(t *testing.T) {
+	tokenizer := NewInMemoryTokenizer()
+	s := NewAccountServiceWithTokenizer(repository.NewInMemoryAccountRepository(), tokenizer)
+	s.SetAuditLogger(NewAuditLogger(io.Discard, false))
+	account := createTestAccount(t, s)
+
+	if _, err := s.DeleteAccount(asUser("admin"), &pii.DeleteAccountRequest{Id: account.Id, HardDelete: true}); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	if _, err := tokenizer.Detokenize(context.Background(), account.CreditCardNumber); err != ErrUnknownToken {
+		t.Errorf("vaulted card number still readable after hard delete: %v", err)
+	}
+}
+
+func TestSearchAccountsThrottlesSSNLookups(t *testing.T) {
+	s := newTestAccountService()
+	createTestAccount(t, s)
+	ctx := asUser("bob")
+
+	for i := 0; i < ssnSearchLimit; i++ {
+		if _, err := s.SearchAccounts(ctx, &pii.SearchAccountsRequest{Ssn: "123-45-6789", Purpose: PurposeSupport}); err != nil {
+			t.Fatalf("SSN search %d: %v", i+1, err)
+		}
+	}
+
+	_, err := s.SearchAccounts(ctx, &pii.SearchAccountsRequest{Ssn: "123-45-6789", Purpose: PurposeSupport})
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.ResourceExhausted {
+		t.Fatalf("SSN search over the limit: got %v, want RESOURCE_EXHAUSTED", err)
+	}
+	if appErr.RetryAfter <= 0 || appErr.RetryAfter > ssnSearchWindow {
+		t.Errorf("RetryAfter = %v, want within (0, %v]", appErr.RetryAfter, ssnSearchWindow)
+	}
+
+	// Other searches by the same caller, and SSN searches by others, still work
+	if _, err := s.SearchAccounts(ctx, &pii.SearchAccountsRequest{Name: "Alice", Purpose: PurposeSupport}); err != nil {
+		t.Errorf("name search after SSN throttling: %v", err)
+	}
+	if _, err := s.SearchAccounts(asUser("carol"), &pii.SearchAccountsRequest{Ssn: "123-45-6789", Purpose: PurposeSupport}); err != nil {
+		t.Errorf("SSN search by another caller: %v", err)
+	}
+}
+
+func TestUpdateAccountKeepsImmutableFields(t *testing.T) {
+	tests := []struct {
+		name string
+		mask []string
+	}{
+		{name: "full update"},
+		{name: "masked update", mask: []string{"email"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestAccountService()
+			account := createTestAccount(t, s)
+
+			change := proto.Clone(account).(*pii.Account)
+			change.Email = "alice@new.example.com"
+			change.AccountNumber = "ACC-9999"
+			change.CreatedAt = timestamppb.New(time.Unix(0, 0))
+			req := &pii.UpdateAccountRequest{Account: change}
+			if tt.mask != nil {
+				req.UpdateMask = &fieldmaskpb.FieldMask{Paths: tt.mask}
+			}
+
+			updated, err := s.UpdateAccount(asUser("alice"), req)
+			if err != nil {
+				t.Fatalf("UpdateAccount: %v", err)
+			}
+			if updated.Email != "alice@new.example.com" {
+				t.Errorf("email = %q, want the updated value", updated.Email)
+			}
+			if updated.Id != account.Id || updated.AccountNumber != account.AccountNumber {
+				t.Errorf("id/account number = %q/%q, want %q/%q", updated.Id, updated.AccountNumber, account.Id, account.AccountNumber)
+			}
+			if !proto.Equal(updated.CreatedAt, account.CreatedAt) {
+				t.Errorf("created_at = %v, want %v", updated.CreatedAt, account.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestCardNumbersRoundTripThroughTheVault(t *testing.T) {
+	tokenizer := NewInMemoryTokenizer()
+	s := NewAccountServiceWithTokenizer(repository.NewInMemoryAccountRepository(), tokenizer)
+	s.SetAuditLogger(NewAuditLogger(io.Discard, false))
+	account := createTestAccount(t, s)
+
+	token := account.CreditCardNumber
+	if !isToken(token) || strings.Contains(token, "4111111111111111") || !strings.HasSuffix(token, "1111") {
+		t.Fatalf("card number = %q, want a token keeping only the last 4 digits", token)
+	}
+	if raw, err := tokenizer.Detokenize(context.Background(), token); err != nil || raw != "4111111111111111" {
+		t.Errorf("Detokenize = %q, %v; want the original number", raw, err)
+	}
+
+	// Only admins see the raw number behind the token
+	got, err := s.GetAccount(asUser("admin"), &pii.GetAccountRequest{Id: account.Id, IncludeSensitiveData: true, Purpose: PurposeFraud})
+	if err != nil {
+		t.Fatalf("GetAccount as admin: %v", err)
+	}
+	if got.CreditCardNumber != "4111111111111111" {
+		t.Errorf("admin sees card %q, want the raw number", got.CreditCardNumber)
+	}
+	got, err = s.GetAccount(asUser("alice"), &pii.GetAccountRequest{Id: account.Id, IncludeSensitiveData: true, Purpose: PurposeBilling})
+	if err != nil {
+		t.Fatalf("GetAccount as owner: %v", err)
+	}
+	if strings.Contains(got.CreditCardNumber, "4111111111111111") {
+		t.Errorf("owner sees the raw card number %q", got.CreditCardNumber)
+	}
+}
+
+func TestSearchAccountsByCardLast4MatchesTokens(t *testing.T) {
+	s := NewAccountServiceWithTokenizer(repository.NewInMemoryAccountRepository(), NewInMemoryTokenizer())
+	s.SetAuditLogger(NewAuditLogger(io.Discard, false))
+	account := createTestAccount(t, s)
+
+	for last4, want := range map[string]int{"1111": 1, "4111": 0, "9999": 0} {
+		resp, err := s.SearchAccounts(asUser("bob"), &pii.SearchAccountsRequest{CreditCardLast4: last4, Purpose: PurposeSupport})
+		if err != nil {
+			t.Fatalf("SearchAccounts(%s): %v", last4, err)
+		}
+		if len(resp.Accounts) != want {
+			t.Errorf("SearchAccounts(%s) found %d accounts, want %d", last4, len(resp.Accounts), want)
+		}
+		if want == 1 && resp.Accounts[0].Id != account.Id {
+			t.Errorf("SearchAccounts(%s) found %s, want %s", last4, resp.Accounts[0].Id, account.Id)
+		}
+	}
+}
+
+func TestUpdateAccountForgetsReplacedAndClearedCards(t *testing.T) {
+	tokenizer := NewInMemoryTokenizer()
+	s := NewAccountServiceWithTokenizer(repository.NewInMemoryAccountRepository(), tokenizer)
+	s.SetAuditLogger(NewAuditLogger(io.Discard, false))
+	account := createTestAccount(t, s)
+	ctx := asUser("alice")
+	update := func(card string, paths ...string) *pii.Account {
+		t.Helper()
+		updated, err := s.UpdateAccount(ctx, &pii.UpdateAccountRequest{
+			Account:    &pii.Account{Id: account.Id, Email: "alice@example.org", CreditCardNumber: card},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: paths},
+		})
+		if err != nil {
+			t.Fatalf("UpdateAccount(%v): %v", paths, err)
+		}
+		return updated
+	}
+	vaulted := func(token string) bool {
+		_, err := tokenizer.Detokenize(context.Background(), token)
+		return err == nil
+	}
+
+	// Updates that leave the card alone keep it in the vault
+	first := account.CreditCardNumber
+	if got := update("", "email"); got.CreditCardNumber != first || !vaulted(first) {
+		t.Fatalf("card after an email update = %q (vaulted %v), want %q kept", got.CreditCardNumber, vaulted(first), first)
+	}
+
+	second := update("5500000000000004", "credit_card_number").CreditCardNumber
+	if !isToken(second) || second == first || !vaulted(second) {
+		t.Fatalf("replaced card = %q, want a new vaulted token", second)
+	}
+	if vaulted(first) {
+		t.Error("replaced card number is still in the vault")
+	}
+
+	if got := update("", "credit_card_number"); got.CreditCardNumber != "" {
+		t.Fatalf("cleared card = %q, want empty", got.CreditCardNumber)
+	}
+	if vaulted(second) {
+		t.Error("cleared card number is still in the vault")
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"fmt"
+
+	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+)
+
+// accountTransitions lists the statuses each status may move to. CLOSED is
+// terminal; only an admin may reopen a closed account.
+var accountTransitions = map[pii.AccountStatus][]pii.AccountStatus{
+	// Accounts stored before statuses were enforced may have none
+	pii.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED: {pii.AccountStatus_PENDING, pii.AccountStatus_ACTIVE, pii.AccountStatus_CLOSED},
+	pii.AccountStatus_PENDING:                    {pii.AccountStatus_ACTIVE, pii.AccountStatus_CLOSED},
+	pii.AccountStatus_ACTIVE:                     {pii.AccountStatus_SUSPENDED, pii.AccountStatus_CLOSED},
+	pii.AccountStatus_SUSPENDED:                  {pii.AccountStatus_ACTIVE, pii.AccountStatus_CLOSED},
+}
+
+// checkAccountTransition explains why an account may not move from one status
+// to another, or returns "" if it may
+func checkAccountTransition(from, to pii.AccountStatus, admin bool) string {
+	if from == to {
+		return ""
+	}
+	if from == pii.AccountStatus_CLOSED {
+		if to == pii.AccountStatus_ACTIVE && admin {
+			return ""
+		}
+		return "closed accounts can only be reopened (set to ACTIVE) by an admin"
+	}
+	for _, allowed := range accountTransitions[from] {
+		if to == allowed {
+			return ""
+		}
+	}
+	return fmt.Sprintf("status cannot change from %s to %s", from, to)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	stderrors "errors"
+	"testing"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestCheckAccountTransition(t *testing.T) {
+	for _, tc := range []struct {
+		from, to pii.AccountStatus
+		admin    bool
+		allowed  bool
+	}{
+		{pii.AccountStatus_PENDING, pii.AccountStatus_ACTIVE, false, true},
+		{pii.AccountStatus_ACTIVE, pii.AccountStatus_SUSPENDED, false, true},
+		{pii.AccountStatus_SUSPENDED, pii.AccountStatus_ACTIVE, false, true},
+		{pii.AccountStatus_SUSPENDED, pii.AccountStatus_CLOSED, false, true},
+		{pii.AccountStatus_ACTIVE, pii.AccountStatus_ACTIVE, false, true},
+		{pii.AccountStatus_PENDING, pii.AccountStatus_SUSPENDED, false, false},
+		{pii.AccountStatus_ACTIVE, pii.AccountStatus_PENDING, false, false},
+		{pii.AccountStatus_CLOSED, pii.AccountStatus_ACTIVE, false, false},
+		{pii.AccountStatus_CLOSED, pii.AccountStatus_ACTIVE, true, true},
+		{pii.AccountStatus_CLOSED, pii.AccountStatus_SUSPENDED, true, false},
+	} {
+		reason := checkAccountTransition(tc.from, tc.to, tc.admin)
+		if (reason == "") != tc.allowed {
+			t.Errorf("%v -> %v (admin %v): reason %q, want allowed %v", tc.from, tc.to, tc.admin, reason, tc.allowed)
+		}
+	}
+}
+
+func TestUpdateAccountEnforcesStatusTransitions(t *testing.T) {
+	s := newTestAccountService()
+	account, err := s.CreateAccount(asUser("alice"), &pii.CreateAccountRequest{Account: &pii.Account{Username: "alice", FirstName: "Alice"}})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	if account.Status != pii.AccountStatus_PENDING {
+		t.Errorf("new account status = %v, want PENDING", account.Status)
+	}
+
+	setStatus := func(user string, to pii.AccountStatus) error {
+		_, err := s.UpdateAccount(asUser(user), &pii.UpdateAccountRequest{
+			Account:    &pii.Account{Id: account.Id, Status: to},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+		})
+		return err
+	}
+	assertInconsistent := func(err error, what string) {
+		t.Helper()
+		var appErr *errors.AppError
+		if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.FailedPrecondition ||
+			appErr.AppCode != errorspb.AppErrorCode_INCONSISTENT_STATE {
+			t.Errorf("%s: err = %v, want INCONSISTENT_STATE", what, err)
+		}
+	}
+
+	assertInconsistent(setStatus("admin", pii.AccountStatus_SUSPENDED), "PENDING -> SUSPENDED")
+	for _, to := range []pii.AccountStatus{pii.AccountStatus_ACTIVE, pii.AccountStatus_SUSPENDED, pii.AccountStatus_CLOSED} {
+		if err := setStatus("admin", to); err != nil {
+			t.Fatalf("move to %v: %v", to, err)
+		}
+	}
+	assertInconsistent(setStatus("alice", pii.AccountStatus_ACTIVE), "non-admin reopen")
+	if err := setStatus("admin", pii.AccountStatus_ACTIVE); err != nil {
+		t.Errorf("admin reopen: %v", err)
+	}
+}
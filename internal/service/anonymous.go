@@ -0,0 +1,30 @@
+package service
+
+import "fmt"
+
+// anonymousUser is the identity of callers that sent no credentials
+const anonymousUser = "anonymous"
+
+// AnonymousPolicy decides what anonymous callers may do
+type AnonymousPolicy string
+
+const (
+	// AnonymousAllowAll lets anonymous callers read, create and own tasks
+	AnonymousAllowAll AnonymousPolicy = "allow"
+
+	// AnonymousReadOnly lets anonymous callers read but not change tasks
+	AnonymousReadOnly AnonymousPolicy = "read_only"
+
+	// AnonymousDeny rejects every anonymous call
+	AnonymousDeny AnonymousPolicy = "deny"
+)
+
+// ParseAnonymousPolicy checks a configured policy name
+func ParseAnonymousPolicy(name string) (AnonymousPolicy, error) {
+	switch policy := AnonymousPolicy(name); policy {
+	case AnonymousAllowAll, AnonymousReadOnly, AnonymousDeny:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown anonymous policy %q (expected one of allow, read_only, deny)", name)
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"google.golang.org/grpc/codes"
+)
+
+func TestAnonymousPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy    AnonymousPolicy
+		canRead   bool
+		canCreate bool
+	}{
+		{AnonymousAllowAll, true, true},
+		{AnonymousReadOnly, true, false},
+		{AnonymousDeny, false, false},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			s, err := NewTodoService(repository.NewInMemoryRepository(true))
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.SetAnonymousPolicy(tc.policy)
+			anonymous := context.Background()
+
+			_, err = s.CreateTask(anonymous, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Anonymous task"}})
+			checkAnonymousResult(t, "CreateTask", err, tc.canCreate)
+
+			_, err = s.ListTasks(anonymous, &todopb.ListTasksRequest{})
+			checkAnonymousResult(t, "ListTasks", err, tc.canRead)
+
+			// Signed-in callers are never affected by the policy
+			bob := context.WithValue(context.Background(), "user", "bob")
+			if _, err := s.CreateTask(bob, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Bob's task"}}); err != nil {
+				t.Errorf("signed-in CreateTask: %v", err)
+			}
+		})
+	}
+}
+
+func checkAnonymousResult(t *testing.T, method string, err error, allowed bool) {
+	t.Helper()
+	if allowed {
+		if err != nil {
+			t.Errorf("%s: %v, want it allowed", method, err)
+		}
+		return
+	}
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.Unauthenticated {
+		t.Errorf("%s: %v, want Unauthenticated", method, err)
+	}
+}
+
+func TestParseAnonymousPolicy(t *testing.T) {
+	for _, name := range []string{"allow", "read_only", "deny"} {
+		if policy, err := ParseAnonymousPolicy(name); err != nil || string(policy) != name {
+			t.Errorf("ParseAnonymousPolicy(%q) = %q, %v", name, policy, err)
+		}
+	}
+	if _, err := ParseAnonymousPolicy("readonly"); err == nil {
+		t.Error("ParseAnonymousPolicy(\"readonly\") = nil error, want one")
+	}
+}
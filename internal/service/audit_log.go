@@ -0,0 +1,128 @@
+package service
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrAuditChainBroken is returned when an audit log has been edited, reordered
+// or truncated in the middle
+var ErrAuditChainBroken = errors.New("audit chain broken")
+
+// AuditEvent is one PII access, written as a single JSON line
+type AuditEvent struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Action      string    `json:"action"`
+	Resource    string    `json:"resource"`
+	Sensitivity string    `json:"sensitivity"`
+	Purpose     string    `json:"purpose,omitempty"`
+	Caller      string    `json:"caller"`
+
+	// With chaining on, PrevHash is the Hash of the record before and Hash
+	// covers this record including PrevHash
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+// maxAuditHistory caps the events kept in memory per resource for data
+// subject exports; older events remain only in the written log
+const maxAuditHistory = 1000
+
+// AuditLogger writes audit events as JSON lines. With chaining on, each
+// record carries the hash of the one before it, so a removed or altered
+// record is detected by VerifyAuditChain. The latest events for each resource
+// are also kept in memory so they can be handed back to the data subject.
+type AuditLogger struct {
+	mu       sync.Mutex
+	w        io.Writer
+	chained  bool
+	lastHash string
+	history  map[string][]AuditEvent // resource -> events, oldest first
+}
+
+// NewAuditLogger creates an audit logger writing to w
+func NewAuditLogger(w io.Writer, chained bool) *AuditLogger {
+	return &AuditLogger{w: w, chained: chained, history: make(map[string][]AuditEvent)}
+}
+
+func (l *AuditLogger) Log(event AuditEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.chained {
+		event.PrevHash = l.lastHash
+		hash, err := hashAuditEvent(event)
+		if err != nil {
+			return err
+		}
+		event.Hash = hash
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := l.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	l.lastHash = event.Hash
+
+	events := append(l.history[event.Resource], event)
+	if len(events) > maxAuditHistory {
+		events = events[len(events)-maxAuditHistory:]
+	}
+	l.history[event.Resource] = events
+	return nil
+}
+
+// History returns the retained events for a resource, oldest first
+func (l *AuditLogger) History(resource string) []AuditEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]AuditEvent(nil), l.history[resource]...)
+}
+
+// VerifyAuditChain reads a chained audit log and checks every record's hash
+// and its link to the record before it
+func VerifyAuditChain(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	prevHash := ""
+	for n := 1; scanner.Scan(); n++ {
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return fmt.Errorf("%w at record %d: %v", ErrAuditChainBroken, n, err)
+		}
+		if event.PrevHash != prevHash {
+			return fmt.Errorf("%w at record %d: previous record is missing or altered", ErrAuditChainBroken, n)
+		}
+
+		want, err := hashAuditEvent(event)
+		if err != nil {
+			return err
+		}
+		if event.Hash != want {
+			return fmt.Errorf("%w at record %d: record was altered", ErrAuditChainBroken, n)
+		}
+		prevHash = event.Hash
+	}
+	return scanner.Err()
+}
+
+// hashAuditEvent hashes the event's JSON form without its own Hash
+func hashAuditEvent(event AuditEvent) (string, error) {
+	event.Hash = ""
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
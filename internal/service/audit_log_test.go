@@ -0,0 +1,52 @@
+package service
+
+import (
+	"bytes"
+	stderrors "errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chainedAuditLog writes n events with chaining on and returns the log lines
+func chainedAuditLog(t *testing.T, n int) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	logger := NewAuditLogger(&buf, true)
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		if err := logger.Log(AuditEvent{
+			Timestamp:   start.Add(time.Duration(i) * time.Minute),
+			Action:      "READ",
+			Resource:    "acc-1",
+			Sensitivity: "HIGH",
+			Caller:      "alice",
+		}); err != nil {
+			t.Fatalf("Log: %v", err)
+		}
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func TestVerifyAuditChainDetectsTampering(t *testing.T) {
+	lines := chainedAuditLog(t, 4)
+	if err := VerifyAuditChain(strings.NewReader(strings.Join(lines, "\n"))); err != nil {
+		t.Fatalf("untouched log failed verification: %v", err)
+	}
+
+	tampered := map[string][]string{
+		"edited":    {lines[0], strings.Replace(lines[1], `"caller":"alice"`, `"caller":"mallory"`, 1), lines[2], lines[3]},
+		"removed":   {lines[0], lines[2], lines[3]},
+		"reordered": {lines[0], lines[2], lines[1], lines[3]},
+		"inserted":  {lines[0], lines[1], lines[1], lines[2], lines[3]},
+		"not json":  {lines[0], "{", lines[2], lines[3]},
+	}
+	for name, log := range tampered {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyAuditChain(strings.NewReader(strings.Join(log, "\n")))
+			if !stderrors.Is(err, ErrAuditChainBroken) {
+				t.Errorf("VerifyAuditChain = %v, want ErrAuditChainBroken", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+)
+
+// CalendarContentType is the media type of ExportCalendar documents
+const CalendarContentType = "text/calendar; charset=utf-8"
+
+const icsTimeFormat = "20060102T150405Z"
+
+// ExportCalendar renders the caller's tasks that have a due date as an
+// iCalendar document with one VEVENT per task. The filter uses the ListTasks
+// syntax and access rules, so callers only see tasks they could list.
+func (s *TodoService) ExportCalendar(ctx context.Context, filter string) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "ExportCalendar")
+	defer span.End()
+
+	tasks, err := s.listAllTasks(ctx, filter, "due_date")
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCalendar(tasks, s.now().UTC().Format(icsTimeFormat)), nil
+}
+
+func buildCalendar(tasks []*todopb.Task, stamp string) []byte {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//todo.example.com//Tasks//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+
+		summary := task.Title
+		if task.Status == todopb.Status_STATUS_COMPLETED {
+			summary = "[Completed] " + summary
+		}
+
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, fmt.Sprintf("UID:%s@todo.example.com", strings.TrimPrefix(task.Name, "tasks/")))
+		writeICSLine(&b, "DTSTAMP:"+stamp)
+		writeICSLine(&b, "DTSTART:"+task.DueDate.AsTime().UTC().Format(icsTimeFormat))
+		writeICSLine(&b, "SUMMARY:"+escapeICSText(summary))
+		if task.Description != "" {
+			writeICSLine(&b, "DESCRIPTION:"+escapeICSText(task.Description))
+		}
+		if len(task.Tags) > 0 {
+			tags := make([]string, len(task.Tags))
+			for i, tag := range task.Tags {
+				tags[i] = escapeICSText(tag)
+			}
+			writeICSLine(&b, "CATEGORIES:"+strings.Join(tags, ","))
+		}
+
+		// Finished tasks shouldn't block time in the user's calendar
+		switch task.Status {
+		case todopb.Status_STATUS_COMPLETED:
+			writeICSLine(&b, "STATUS:CONFIRMED")
+			writeICSLine(&b, "TRANSP:TRANSPARENT")
+		case todopb.Status_STATUS_CANCELLED:
+			writeICSLine(&b, "STATUS:CANCELLED")
+			writeICSLine(&b, "TRANSP:TRANSPARENT")
+		default:
+			writeICSLine(&b, "STATUS:CONFIRMED")
+		}
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// writeICSLine ends each line with CRLF and folds lines longer than 75 octets
+// as RFC 5545 requires, without splitting a UTF-8 character
+func writeICSLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICSText(text string) string {
+	return icsTextEscaper.Replace(text)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestExportCalendar(t *testing.T) {
+	repo := repository.NewInMemoryRepository(true)
+	s, err := NewTodoService(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	due := timestamppb.New(time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC))
+
+	for i, task := range []*todopb.Task{
+		{CreatedBy: "bob", Title: "Pay rent", Status: todopb.Status_STATUS_PENDING, DueDate: due},
+		{CreatedBy: "bob", Title: "File taxes", Status: todopb.Status_STATUS_COMPLETED, DueDate: due},
+		{CreatedBy: "bob", Title: "Renew passport", Status: todopb.Status_STATUS_CANCELLED, DueDate: due},
+		{CreatedBy: "bob", Title: "Someday", Status: todopb.Status_STATUS_PENDING},
+		{CreatedBy: "alice", Title: "Not bob's", Status: todopb.Status_STATUS_PENDING, DueDate: due},
+	} {
+		task.Name = fmt.Sprintf("tasks/t%d", i)
+		if err := repo.CreateTask(context.Background(), task); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	for _, tc := range []struct {
+		filter string
+		events int
+	}{
+		{"", 3},
+		{"status=STATUS_PENDING", 1},
+	} {
+		ics, err := s.ExportCalendar(ctx, tc.filter)
+		if err != nil {
+			t.Fatalf("ExportCalendar(%q): %v", tc.filter, err)
+		}
+		doc := string(ics)
+		if got := strings.Count(doc, "BEGIN:VEVENT\r\n"); got != tc.events {
+			t.Errorf("ExportCalendar(%q) has %d VEVENTs, want %d:\n%s", tc.filter, got, tc.events, doc)
+		}
+		if !strings.HasPrefix(doc, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(doc, "END:VCALENDAR\r\n") {
+			t.Errorf("ExportCalendar(%q) is not wrapped in a VCALENDAR", tc.filter)
+		}
+		if strings.Contains(doc, "Not bob's") || strings.Contains(doc, "Someday") {
+			t.Errorf("ExportCalendar(%q) includes another user's task or one without a due date", tc.filter)
+		}
+	}
+
+	ics, err := s.ExportCalendar(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(map[string]string)
+	for _, event := range strings.Split(string(ics), "BEGIN:VEVENT\r\n")[1:] {
+		uid := strings.SplitN(strings.SplitN(event, "UID:", 2)[1], "@", 2)[0]
+		events[uid] = event
+	}
+	for _, want := range []struct {
+		uid   string
+		lines []string
+	}{
+		{"t0", []string{"SUMMARY:Pay rent\r\n", "DTSTART:20260310T093000Z\r\n", "STATUS:CONFIRMED\r\n"}},
+		{"t1", []string{"SUMMARY:[Completed] File taxes\r\n", "TRANSP:TRANSPARENT\r\n"}},
+		{"t2", []string{"STATUS:CANCELLED\r\n", "TRANSP:TRANSPARENT\r\n"}},
+	} {
+		event, ok := events[want.uid]
+		if !ok {
+			t.Errorf("no VEVENT for task %s", want.uid)
+			continue
+		}
+		for _, line := range want.lines {
+			if !strings.Contains(event, line) {
+				t.Errorf("VEVENT %s missing %q:\n%s", want.uid, line, event)
+			}
+		}
+	}
+	if strings.Contains(events["t0"], "TRANSP:TRANSPARENT") {
+		t.Errorf("pending task should block time:\n%s", events["t0"])
+	}
+}
+
+func TestWriteICSLineFoldsLongLines(t *testing.T) {
+	var b strings.Builder
+	writeICSLine(&b, "SUMMARY:"+strings.Repeat("é", 60))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected the line to be folded, got %q", b.String())
+	}
+	for i, line := range lines {
+		if len(line) > 75 {
+			t.Errorf("line %d is %d octets", i, len(line))
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("continuation line %d does not start with a space", i)
+		}
+	}
+	if unfolded := strings.ReplaceAll(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ", ""); unfolded != "SUMMARY:"+strings.Repeat("é", 60) {
+		t.Errorf("unfolded line = %q", unfolded)
+	}
+}
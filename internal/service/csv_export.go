@@ -0,0 +1,62 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"time"
+
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+)
+
+// CSVContentType is the media type of ExportCSV documents
+const CSVContentType = "text/csv; charset=utf-8"
+
+var csvHeader = []string{"name", "title", "description", "status", "priority", "due_date", "tags", "created_by"}
+
+// ExportCSV renders the caller's tasks as CSV, one row per task after a header
+// row. The filter uses the ListTasks syntax and access rules. Fields holding
+// commas, quotes or newlines are quoted per RFC 4180.
+func (s *TodoService) ExportCSV(ctx context.Context, filter string) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "ExportCSV")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	tasks, err := s.listAllTasks(ctx, filter, "create_time")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, errors.NewInternal("Failed to encode CSV export", traceID, err)
+	}
+	for _, task := range tasks {
+		var dueDate string
+		if task.DueDate != nil {
+			dueDate = task.DueDate.AsTime().UTC().Format(time.RFC3339)
+		}
+		if err := w.Write([]string{
+			task.Name,
+			task.Title,
+			task.Description,
+			task.Status.String(),
+			task.Priority.String(),
+			dueDate,
+			strings.Join(task.Tags, ";"),
+			task.CreatedBy,
+		}); err != nil {
+			return nil, errors.NewInternal("Failed to encode CSV export", traceID, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, errors.NewInternal("Failed to encode CSV export", traceID, err)
+	}
+
+	return buf.Bytes(), nil
+}
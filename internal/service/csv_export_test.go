@@ -0,0 +1,73 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestExportCSVEscapesFields(t *testing.T) {
+	repo := repository.NewInMemoryRepository(true)
+	s, err := NewTodoService(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	due := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+
+	for _, task := range []*todopb.Task{
+		{
+			Name:        "tasks/t1",
+			Title:       `Buy milk, eggs and "good" bread`,
+			Description: "first line\nsecond line",
+			Status:      todopb.Status_STATUS_PENDING,
+			Priority:    todopb.Priority_PRIORITY_HIGH,
+			DueDate:     timestamppb.New(due),
+			Tags:        []string{"home", "errands"},
+			CreatedBy:   "bob",
+		},
+		{Name: "tasks/t2", Title: "Not bob's", CreatedBy: "alice"},
+	} {
+		if err := repo.CreateTask(context.Background(), task); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+
+	data, err := s.ExportCSV(context.WithValue(context.Background(), "user", "bob"), "")
+	if err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"Buy milk, eggs and ""good"" bread"`)) {
+		t.Errorf("title was not quoted with doubled quotes:\n%s", data)
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v\n%s", err, data)
+	}
+	want := [][]string{
+		csvHeader,
+		{"tasks/t1", `Buy milk, eggs and "good" bread`, "first line\nsecond line", "STATUS_PENDING", "PRIORITY_HIGH", "2026-03-10T09:30:00Z", "home;errands", "bob"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+}
+
+func TestExportCSVRejectsInvalidFilter(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.ExportCSV(context.WithValue(context.Background(), "user", "bob"), "color=red")
+	if err == nil || !strings.Contains(err.Error(), "INVALID_FILTER") {
+		t.Errorf("ExportCSV with an unknown filter field = %v, want INVALID_FILTER", err)
+	}
+}
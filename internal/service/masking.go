@@ -0,0 +1,71 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// redactedLength is the width of a fully hidden value, fixed so the mask
+// doesn't leak the length of secrets
+const redactedLength = 8
+
+// MaskPolicy controls how PII is masked in responses. Identifiers such as
+// SSNs, card and phone numbers keep their separators and reveal the last
+// Reveal letters or digits; everything else becomes Char.
+type MaskPolicy struct {
+	Char   rune
+	Reveal int
+}
+
+// DefaultMaskPolicy reveals the last 4 characters, e.g. ***-**-6789
+var DefaultMaskPolicy = MaskPolicy{Char: '*', Reveal: 4}
+
+// identifier masks every letter and digit except the last Reveal. Values too
+// short to keep anything hidden are masked completely.
+func (p MaskPolicy) identifier(value string) string {
+	alnum := 0
+	for _, r := range value {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			alnum++
+		}
+	}
+
+	reveal := p.Reveal
+	if reveal < 0 || reveal >= alnum {
+		reveal = 0
+	}
+
+	var b strings.Builder
+	seen := 0
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			b.WriteRune(r)
+			continue
+		}
+		seen++
+		if seen > alnum-reveal {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(p.Char)
+		}
+	}
+	return b.String()
+}
+
+// secret hides a value completely
+func (p MaskPolicy) secret() string {
+	return strings.Repeat(string(p.Char), redactedLength)
+}
+
+// email keeps the first 2 characters of the local part and the domain
+func (p MaskPolicy) email(email string) string {
+	hidden := strings.Repeat(string(p.Char), 3)
+	if len(email) < 3 {
+		return hidden
+	}
+	atIndex := strings.IndexByte(email, '@')
+	if atIndex < 0 {
+		return email[:2] + hidden
+	}
+	return email[:2] + hidden + email[atIndex:]
+}
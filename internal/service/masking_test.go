@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+
+	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+)
+
+func TestMaskPolicyRevealCountAcrossFieldTypes(t *testing.T) {
+	s := &AccountService{}
+	s.SetMaskPolicy(MaskPolicy{Char: '#', Reveal: 2})
+
+	masked := s.maskSensitiveData(&pii.Account{
+		Ssn:               "123-45-6789",
+		TaxId:             "12-3456789",
+		PassportNumber:    "X1234567",
+		DriversLicense:    "D1234-5678",
+		BankAccountNumber: "000123456789",
+		CreditCardNumber:  "4111 1111 1111 1234",
+		Phone:             "+1 (555) 010-9876",
+		MobilePhone:       "+15550104321",
+		CreditCardCvv:     "123",
+		ApiKey:            "sk_live_abc",
+		Email:             "jane.doe@example.com",
+	})
+
+	for _, tc := range []struct {
+		field, got, want string
+	}{
+		{"ssn", masked.Ssn, "###-##-##89"},
+		{"tax_id", masked.TaxId, "##-#####89"},
+		{"passport_number", masked.PassportNumber, "######67"},
+		{"drivers_license", masked.DriversLicense, "#####-##78"},
+		{"bank_account_number", masked.BankAccountNumber, "##########89"},
+		{"credit_card_number", masked.CreditCardNumber, "#### #### #### ##34"},
+		{"phone", masked.Phone, "+# (###) ###-##76"},
+		{"mobile_phone", masked.MobilePhone, "+#########21"},
+		{"credit_card_cvv", masked.CreditCardCvv, "########"},
+		{"api_key", masked.ApiKey, "########"},
+		{"email", masked.Email, "ja###@example.com"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.field, tc.got, tc.want)
+		}
+	}
+}
+
+func TestMaskPolicyIdentifier(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy MaskPolicy
+		value  string
+		want   string
+	}{
+		{"default reveals last 4", DefaultMaskPolicy, "123-45-6789", "***-**-6789"},
+		{"too short to reveal", DefaultMaskPolicy, "1234", "****"},
+		{"reveal none", MaskPolicy{Char: 'X', Reveal: 0}, "12-34", "XX-XX"},
+		{"negative reveal masks all", MaskPolicy{Char: 'X', Reveal: -1}, "1234567", "XXXXXXX"},
+	} {
+		if got := tc.policy.identifier(tc.value); got != tc.want {
+			t.Errorf("%s: identifier(%q) = %q, want %q", tc.name, tc.value, got, tc.want)
+		}
+	}
+}
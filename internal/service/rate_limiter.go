@@ -0,0 +1,82 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// callerRateLimiter allows each caller a fixed number of operations within a
+// sliding window and counts how often each caller has been blocked. Callers
+// with no attempts left in the window are dropped, so the maps only hold
+// recently active callers.
+type callerRateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	hits      map[string][]time.Time
+	blocked   map[string]int
+	lastSweep time.Time
+	nowFunc   func() time.Time
+}
+
+func newCallerRateLimiter(limit int, window time.Duration) *callerRateLimiter {
+	return &callerRateLimiter{
+		limit:   limit,
+		window:  window,
+		hits:    make(map[string][]time.Time),
+		blocked: make(map[string]int),
+		nowFunc: time.Now,
+	}
+}
+
+// Allow records an attempt by caller and reports whether it is within the
+// limit. When it is not, it also returns how long until the oldest attempt
+// leaves the window and the caller may try again.
+func (l *callerRateLimiter) Allow(caller string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.nowFunc()
+	cutoff := now.Add(-l.window)
+	l.sweep(now, cutoff)
+
+	// Drop attempts that have fallen out of the window
+	recent := l.hits[caller][:0]
+	for _, t := range l.hits[caller] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= l.limit {
+		l.hits[caller] = recent
+		l.blocked[caller]++
+		return false, recent[0].Sub(cutoff)
+	}
+
+	l.hits[caller] = append(recent, now)
+	return true, 0
+}
+
+// sweep forgets callers whose attempts have all left the window. It runs at
+// most once per window so Allow stays cheap with many callers.
+func (l *callerRateLimiter) sweep(now, cutoff time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+	for caller, hits := range l.hits {
+		if len(hits) == 0 || !hits[len(hits)-1].After(cutoff) {
+			delete(l.hits, caller)
+			delete(l.blocked, caller)
+		}
+	}
+}
+
+// Blocked returns how many attempts by caller have been rejected since the
+// caller was last idle for a full window
+func (l *callerRateLimiter) Blocked(caller string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.blocked[caller]
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCallerRateLimiterRetryAfterAndEviction(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := newCallerRateLimiter(2, time.Minute)
+	l.nowFunc = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("bob"); !ok {
+			t.Fatalf("attempt %d blocked", i+1)
+		}
+		now = now.Add(10 * time.Second)
+	}
+
+	ok, retryAfter := l.Allow("bob")
+	if ok {
+		t.Fatal("third attempt allowed")
+	}
+	// The first attempt was 20s ago, so it leaves the window in 40s
+	if retryAfter != 40*time.Second {
+		t.Errorf("retryAfter = %v, want 40s", retryAfter)
+	}
+	if got := l.Blocked("bob"); got != 1 {
+		t.Errorf("Blocked = %d, want 1", got)
+	}
+
+	now = now.Add(40 * time.Second)
+	if ok, _ := l.Allow("bob"); !ok {
+		t.Error("attempt after the oldest expired was blocked")
+	}
+
+	// Once bob has been idle for a full window, the next sweep forgets it
+	now = now.Add(2 * time.Minute)
+	l.Allow("carol")
+	if _, ok := l.hits["bob"]; ok {
+		t.Error("idle caller still tracked after sweep")
+	}
+	if got := l.Blocked("bob"); got != 0 {
+		t.Errorf("Blocked after eviction = %d, want 0", got)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"github.com/bhatti/todo-api-errors/internal/validation"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// revalidatePageSize is how many stored tasks ValidateAllTasks holds at once
+const revalidatePageSize = 100
+
+// NonCompliantTask is a stored task that the current rules would reject
+type NonCompliantTask struct {
+	Name       string                     `json:"name"`
+	Violations []*errorspb.FieldViolation `json:"violations"`
+}
+
+// ValidateAllTasks runs the current validation rules against every task in
+// the caller's tenant and reports the tasks that would now be rejected, e.g.
+// after a limit was tightened. Tasks are read a page at a time and are never
+// modified. Admins only.
+func (s *TodoService) ValidateAllTasks(ctx context.Context) ([]*NonCompliantTask, error) {
+	ctx, span := tracer.Start(ctx, "ValidateAllTasks")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if s.getUserFromContext(ctx) != adminRole {
+		return nil, errors.NewPermissionDenied("tasks", "validate", adminRole, traceID)
+	}
+
+	allowlist := s.allowlistFor(ctx)
+	opts := repository.ListOptions{
+		PageSize: revalidatePageSize,
+		TenantID: s.getTenantFromContext(ctx),
+	}
+
+	checked := 0
+	nonCompliant := []*NonCompliantTask{}
+	for {
+		page, nextToken, err := s.repo.ListTasks(ctx, opts)
+		if err != nil {
+			span.RecordError(err)
+			return nil, s.handleRepositoryError(err, traceID)
+		}
+
+		for _, task := range page {
+			checked++
+			_, err := validation.ValidateTaskWithWarnings(task, allowlist, traceID)
+			if appErr, ok := err.(*errors.AppError); ok {
+				nonCompliant = append(nonCompliant, &NonCompliantTask{Name: task.Name, Violations: appErr.FieldViolations})
+			}
+		}
+
+		if nextToken == "" {
+			break
+		}
+		opts.PageToken = nextToken
+	}
+
+	span.SetAttributes(
+		attribute.Int("revalidate.checked", checked),
+		attribute.Int("revalidate.non_compliant", len(nonCompliant)),
+	)
+
+	return nonCompliant, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"github.com/bhatti/todo-api-errors/internal/validation"
+	"google.golang.org/grpc/codes"
+)
+
+func TestValidateAllTasksReportsTasksBrokenByNewRules(t *testing.T) {
+	repo := repository.NewInMemoryRepository(true)
+	if err := repo.Seed(context.Background(), []*todopb.Task{
+		{Name: "tasks/short", Title: "Short", TenantId: "acme"},
+		{Name: "tasks/long", Title: strings.Repeat("a", 40), TenantId: "acme"},
+		{Name: "tasks/elsewhere", Title: strings.Repeat("b", 40), TenantId: "other"},
+	}); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+	s, err := NewTodoService(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tighten the title limit after the tasks were stored
+	validation.SetLimits(validation.Limits{MaxTitleLength: 20})
+	t.Cleanup(func() { validation.SetLimits(validation.Limits{}) })
+
+	ctx := context.WithValue(context.WithValue(context.Background(), "tenant", "acme"), "user", adminRole)
+	report, err := s.ValidateAllTasks(ctx)
+	if err != nil {
+		t.Fatalf("ValidateAllTasks: %v", err)
+	}
+	if len(report) != 1 || report[0].Name != "tasks/long" {
+		t.Fatalf("report = %+v, want only tasks/long", report)
+	}
+	if v := report[0].Violations; len(v) != 1 || v[0].Field != "title" || v[0].Code != errorspb.AppErrorCode_TOO_LONG.String() {
+		t.Errorf("violations = %v, want TOO_LONG on title", v)
+	}
+
+	// The task itself is left as it was
+	if task, err := repo.GetTask(ctx, "acme", "long"); err != nil || len(task.Title) != 40 {
+		t.Errorf("stored task = %v, %v; want it unchanged", task, err)
+	}
+
+	_, err = s.ValidateAllTasks(context.WithValue(ctx, "user", "bob"))
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.PermissionDenied {
+		t.Errorf("non-admin err = %v, want PermissionDenied", err)
+	}
+}
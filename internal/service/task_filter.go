@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+)
+
+// FilterField describes a field accepted in ListTasks filter expressions
+type FilterField struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Operators []string `json:"operators"`
+	Values    []string `json:"values,omitempty"`
+}
+
+// taskFilterFields is the single source of truth for the filter parser and
+// the filter schema endpoint
+var taskFilterFields = []FilterField{
+	{Name: "status", Type: "enum", Operators: []string{"="}, Values: enumValues(todopb.Status_name)},
+	{Name: "priority", Type: "enum", Operators: []string{"="}, Values: enumValues(todopb.Priority_name)},
+	{Name: "created_by", Type: "string", Operators: []string{"="}},
+}
+
+// TaskFilterSchema returns the fields, types and operators supported in
+// ListTasks filters
+func TaskFilterSchema() []FilterField {
+	return taskFilterFields
+}
+
+func lookupFilterField(fields []FilterField, name string) (FilterField, bool) {
+	for _, field := range fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return FilterField{}, false
+}
+
+// enumValues lists the names of an enum in number order, skipping the zero value
+func enumValues(names map[int32]string) []string {
+	numbers := make([]int32, 0, len(names))
+	for number := range names {
+		if number != 0 {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	values := make([]string, len(numbers))
+	for i, number := range numbers {
+		values[i] = names[number]
+	}
+	return values
+}
+
+// parseEnumValue matches value against an enum field's values ignoring case,
+// with or without the enum prefix, so "pending", "Status_Pending" and
+// "STATUS_PENDING" all map to STATUS_PENDING
+func parseEnumValue(field FilterField, value string) (string, bool) {
+	prefix := strings.ToUpper(field.Name) + "_"
+	for _, v := range field.Values {
+		if strings.EqualFold(v, value) || strings.EqualFold(strings.TrimPrefix(v, prefix), value) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// parseFilterExpression parses a conjunction of field=value clauses against the
+// fields a resource accepts, e.g. "status=STATUS_COMPLETED AND priority=PRIORITY_HIGH"
+func parseFilterExpression(filter string, fields []FilterField) (map[string]interface{}, error) {
+	parsed := make(map[string]interface{})
+
+	if filter == "" {
+		return parsed, nil
+	}
+
+	const separator = " AND "
+	offset := 0
+	for _, part := range strings.Split(filter, separator) {
+		expr := strings.TrimSpace(part)
+		position := offset + strings.Index(part, expr)
+		offset += len(part) + len(separator)
+
+		if expr == "" {
+			return nil, &filterSyntaxError{token: part, position: position, reason: "empty expression"}
+		}
+
+		kv := strings.Split(expr, "=")
+		if len(kv) != 2 {
+			return nil, &filterSyntaxError{token: expr, position: position, reason: "expected field=value"}
+		}
+
+		key := strings.TrimSpace(kv[0])
+		value := strings.Trim(strings.TrimSpace(kv[1]), "'\"")
+
+		// Validate filter keys
+		field, ok := lookupFilterField(fields, key)
+		if !ok {
+			return nil, &filterSyntaxError{token: key, position: position, reason: "unknown filter field"}
+		}
+
+		// Map enum values onto their canonical names from the filter schema
+		valuePosition := position + strings.Index(expr, "=") + 1
+		if value == "" {
+			return nil, &filterSyntaxError{token: expr, position: valuePosition, reason: "missing value"}
+		}
+		if len(field.Values) > 0 {
+			enumValue, ok := parseEnumValue(field, value)
+			if !ok {
+				// Name the accepted values so a typo like status=DONE is easy to fix
+				reason := fmt.Sprintf("unknown %s value (expected one of %s)", key, strings.Join(field.Values, ", "))
+				return nil, &filterSyntaxError{token: value, position: valuePosition, reason: reason}
+			}
+			value = enumValue
+		}
+
+		parsed[key] = value
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestTaskFilterSchemaListsEqualityFields(t *testing.T) {
+	fields := make(map[string]FilterField)
+	for _, field := range TaskFilterSchema() {
+		fields[field.Name] = field
+	}
+
+	for _, name := range []string{"status", "priority"} {
+		field, ok := fields[name]
+		if !ok {
+			t.Fatalf("%s missing from the filter schema", name)
+		}
+		if field.Type != "enum" || len(field.Values) == 0 {
+			t.Errorf("%s = %+v, want an enum with values", name, field)
+		}
+		equality := false
+		for _, op := range field.Operators {
+			equality = equality || op == "="
+		}
+		if !equality {
+			t.Errorf("%s operators = %v, want =", name, field.Operators)
+		}
+	}
+}
+
+func TestParseFilterAcceptsExactlyTheSchemaFields(t *testing.T) {
+	s := &TodoService{}
+	for _, field := range TaskFilterSchema() {
+		value := "x"
+		if len(field.Values) > 0 {
+			value = field.Values[0]
+		}
+		if _, err := s.parseFilter(field.Name + "=" + value); err != nil {
+			t.Errorf("schema field %s rejected by the parser: %v", field.Name, err)
+		}
+	}
+	if _, err := s.parseFilter("color=red"); err == nil {
+		t.Error("field outside the schema accepted by the parser")
+	}
+}
+
+func TestListTasksPointsAtInvalidFilterToken(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		filter   string
+		token    string
+		position int
+	}{
+		{"status", "status", 0},
+		{"status=STATUS_PENDING AND color=red", "color", 26},
+		{"priority=PRIORITY_URGENT", "PRIORITY_URGENT", 9},
+		{"status=STATUS_PENDING AND  AND created_by=bob", "", 26},
+		{"created_by=", "created_by=", 11},
+	} {
+		_, err := s.ListTasks(context.Background(), &todopb.ListTasksRequest{Filter: tc.filter})
+		var appErr *errors.AppError
+		if !stderrors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_INVALID_FILTER {
+			t.Errorf("%q: err = %v, want INVALID_FILTER", tc.filter, err)
+			continue
+		}
+		if len(appErr.FieldViolations) != 0 {
+			t.Errorf("%q: field violations = %v, want none", tc.filter, appErr.FieldViolations)
+		}
+
+		var pointer structpb.Struct
+		if ext, ok := appErr.Extensions["filter"]; !ok || ext.UnmarshalTo(&pointer) != nil {
+			t.Errorf("%q: filter extension missing", tc.filter)
+			continue
+		}
+		if got := pointer.Fields["token"].GetStringValue(); got != tc.token {
+			t.Errorf("%q: token = %q, want %q", tc.filter, got, tc.token)
+		}
+		if got := int(pointer.Fields["position"].GetNumberValue()); got != tc.position {
+			t.Errorf("%q: position = %d, want %d", tc.filter, got, tc.position)
+		}
+		if pointer.Fields["syntax"].GetStringValue() != errors.FilterSyntax {
+			t.Errorf("%q: syntax hint missing", tc.filter)
+		}
+	}
+}
+
+func TestParseFilterNormalizesEnumValues(t *testing.T) {
+	s := &TodoService{}
+	for _, tc := range []struct {
+		filter, field, want string
+	}{
+		{"status=pending", "status", "STATUS_PENDING"},
+		{"status=Status_In_Progress", "status", "STATUS_IN_PROGRESS"},
+		{"status=STATUS_COMPLETED", "status", "STATUS_COMPLETED"},
+		{"priority=high", "priority", "PRIORITY_HIGH"},
+		{"priority=Priority_Low", "priority", "PRIORITY_LOW"},
+		{"created_by=Bob", "created_by", "Bob"},
+	} {
+		parsed, err := s.parseFilter(tc.filter)
+		if err != nil {
+			t.Errorf("%q: %v", tc.filter, err)
+			continue
+		}
+		if parsed[tc.field] != tc.want {
+			t.Errorf("%q: %s = %v, want %s", tc.filter, tc.field, parsed[tc.field], tc.want)
+		}
+	}
+
+	for _, filter := range []string{"status=done", "priority=PRIORITY_URGENT", "status=priority_high", "status=unspecified"} {
+		if _, err := s.parseFilter(filter); err == nil {
+			t.Errorf("%q accepted, want an unknown value error", filter)
+		}
+	}
+}
+
+func TestListTasksMatchesLowercaseStatusFilter(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+	if _, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Write tests"}}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	resp, err := s.ListTasks(ctx, &todopb.ListTasksRequest{Filter: "status=pending"})
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(resp.Tasks) != 1 {
+		t.Errorf("status=pending matched %d tasks, want 1", len(resp.Tasks))
+	}
+}
+
+func TestListTasksListsAcceptedValuesForInvalidEnum(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+	if _, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Ship release"}}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	for _, tc := range []struct {
+		filter string
+		want   []string
+	}{
+		{"status=DONE", []string{"STATUS_PENDING", "STATUS_COMPLETED", "STATUS_CANCELLED"}},
+		{"priority=URGENT", []string{"PRIORITY_LOW", "PRIORITY_HIGH"}},
+	} {
+		resp, err := s.ListTasks(ctx, &todopb.ListTasksRequest{Filter: tc.filter})
+		var appErr *errors.AppError
+		if !stderrors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_INVALID_FILTER {
+			t.Errorf("%q: got %v, %v; want INVALID_FILTER instead of an empty list", tc.filter, resp, err)
+			continue
+		}
+
+		var pointer structpb.Struct
+		if ext, ok := appErr.Extensions["filter"]; !ok || ext.UnmarshalTo(&pointer) != nil {
+			t.Errorf("%q: filter extension missing", tc.filter)
+			continue
+		}
+		reason := pointer.Fields["reason"].GetStringValue()
+		for _, value := range tc.want {
+			if !strings.Contains(reason, value) || !strings.Contains(appErr.Detail, value) {
+				t.Errorf("%q: reason %q and detail %q should list %s", tc.filter, reason, appErr.Detail, value)
+			}
+		}
+	}
+}
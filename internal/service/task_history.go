@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/fieldmask"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	// maxTaskChanges bounds the change records kept per task; older ones are dropped
+	maxTaskChanges = 500
+
+	defaultHistoryPageSize = 50
+	maxHistoryPageSize     = 100
+
+	historyPageTokenPrefix = "history_"
+)
+
+// TaskChange records one update to a task: who made it, when, and the old
+// and new value of each changed field
+type TaskChange struct {
+	User    string             `json:"user"`
+	Time    time.Time          `json:"time"`
+	Changes []fieldmask.Change `json:"changes"`
+}
+
+// GetTaskHistoryRequest asks for a page of a task's changes, oldest first
+type GetTaskHistoryRequest struct {
+	Name      string // e.g. tasks/{id}
+	PageSize  int
+	PageToken string
+}
+
+// GetTaskHistoryResponse is one page of a task's changes
+type GetTaskHistoryResponse struct {
+	Changes       []*TaskChange `json:"changes"`
+	NextPageToken string        `json:"next_page_token,omitempty"`
+}
+
+// editableTaskFields are compared when a change has no request mask, e.g.
+// when an update is undone
+var editableTaskFields = &fieldmaskpb.FieldMask{
+	Paths: []string{"title", "description", "status", "priority", "due_date", "tags", "time_zone"},
+}
+
+// taskChangeLog keeps each task's change records, keyed by tenant-scoped ID
+type taskChangeLog struct {
+	mu      sync.Mutex
+	changes map[string][]*TaskChange
+}
+
+func newTaskChangeLog() *taskChangeLog {
+	return &taskChangeLog{changes: make(map[string][]*TaskChange)}
+}
+
+func changeLogKey(tenantID, taskID string) string {
+	return tenantID + "/" + taskID
+}
+
+// recordChange stores the fields that mask names and that differ between
+// before and after. Updates that change nothing are not recorded.
+func (s *TodoService) recordChange(ctx context.Context, before, after *todopb.Task, mask *fieldmaskpb.FieldMask) {
+	changes := fieldmask.Diff(before, after, mask)
+	if len(changes) == 0 {
+		return
+	}
+
+	key := changeLogKey(after.TenantId, strings.TrimPrefix(after.Name, "tasks/"))
+	s.changeLog.mu.Lock()
+	defer s.changeLog.mu.Unlock()
+
+	entries := append(s.changeLog.changes[key], &TaskChange{
+		User:    s.getUserFromContext(ctx),
+		Time:    s.now().UTC(),
+		Changes: changes,
+	})
+	if len(entries) > maxTaskChanges {
+		entries = entries[len(entries)-maxTaskChanges:]
+	}
+	s.changeLog.changes[key] = entries
+}
+
+// forgetChanges drops a deleted task's history so a reused ID starts afresh
+func (s *TodoService) forgetChanges(tenantID, taskID string) {
+	s.changeLog.mu.Lock()
+	defer s.changeLog.mu.Unlock()
+	delete(s.changeLog.changes, changeLogKey(tenantID, taskID))
+}
+
+// GetTaskHistory returns a page of the changes made to a task the caller can
+// read, oldest first
+func (s *TodoService) GetTaskHistory(ctx context.Context, req *GetTaskHistoryRequest) (*GetTaskHistoryResponse, error) {
+	ctx, span := tracer.Start(ctx, "GetTaskHistory")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, false, traceID); err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(req.Name, "/")
+	if len(parts) != 2 || parts[0] != "tasks" || parts[1] == "" {
+		return nil, errors.NewRequiredField("name", "Invalid task name format", traceID)
+	}
+	taskID := parts[1]
+	span.SetAttributes(attribute.String("task.id", taskID))
+
+	task, err := s.repo.GetTask(ctx, s.getTenantFromContext(ctx), taskID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, errors.NewNotFound("Task", taskID, traceID)
+		}
+		span.RecordError(err)
+		return nil, s.handleRepositoryError(err, traceID)
+	}
+	if !s.canAccessTask(ctx, task) {
+		return nil, errors.NewPermissionDenied("task", "read", adminRole, traceID)
+	}
+
+	pageSize := req.PageSize
+	switch {
+	case pageSize < 0:
+		return nil, errors.NewInvalidArgument(fmt.Sprintf("page_size must not be negative, got %d", pageSize), traceID)
+	case pageSize == 0:
+		pageSize = defaultHistoryPageSize
+	case pageSize > maxHistoryPageSize:
+		pageSize = maxHistoryPageSize
+	}
+
+	start := 0
+	if req.PageToken != "" {
+		offset, err := strconv.Atoi(strings.TrimPrefix(req.PageToken, historyPageTokenPrefix))
+		if !strings.HasPrefix(req.PageToken, historyPageTokenPrefix) || err != nil || offset < 0 {
+			return nil, errors.NewInvalidArgument(fmt.Sprintf("Invalid page token '%s'", req.PageToken), traceID)
+		}
+		start = offset
+	}
+
+	s.changeLog.mu.Lock()
+	entries := s.changeLog.changes[changeLogKey(task.TenantId, taskID)]
+	s.changeLog.mu.Unlock()
+
+	if start > len(entries) {
+		start = len(entries)
+	}
+	end := start + pageSize
+	if end > len(entries) {
+		end = len(entries)
+	}
+
+	resp := &GetTaskHistoryResponse{Changes: append([]*TaskChange{}, entries[start:end]...)}
+	if end < len(entries) {
+		resp.NextPageToken = fmt.Sprintf("%s%d", historyPageTokenPrefix, end)
+	}
+	return resp, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestTaskHistoryRecordsEachUpdate(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	task, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Draft", Priority: todopb.Priority_PRIORITY_LOW}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	for _, update := range []struct {
+		task *todopb.Task
+		path string
+	}{
+		{&todopb.Task{Name: task.Name, Title: "Final"}, "title"},
+		{&todopb.Task{Name: task.Name, Priority: todopb.Priority_PRIORITY_HIGH}, "priority"},
+	} {
+		if _, err := s.UpdateTask(ctx, &todopb.UpdateTaskRequest{Task: update.task, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{update.path}}}); err != nil {
+			t.Fatalf("UpdateTask %s: %v", update.path, err)
+		}
+	}
+
+	resp, err := s.GetTaskHistory(ctx, &GetTaskHistoryRequest{Name: task.Name, PageSize: 1})
+	if err != nil {
+		t.Fatalf("GetTaskHistory: %v", err)
+	}
+	if len(resp.Changes) != 1 || resp.NextPageToken == "" {
+		t.Fatalf("first page = %+v, want one change and a next token", resp)
+	}
+	first := resp.Changes[0]
+
+	resp, err = s.GetTaskHistory(ctx, &GetTaskHistoryRequest{Name: task.Name, PageSize: 1, PageToken: resp.NextPageToken})
+	if err != nil {
+		t.Fatalf("GetTaskHistory page 2: %v", err)
+	}
+	if len(resp.Changes) != 1 || resp.NextPageToken != "" {
+		t.Fatalf("second page = %+v, want the last change", resp)
+	}
+	second := resp.Changes[0]
+
+	if first.User != "bob" || len(first.Changes) != 1 || first.Changes[0].Field != "title" ||
+		first.Changes[0].Old != "Draft" || first.Changes[0].New != "Final" {
+		t.Errorf("first change = %+v, want title Draft -> Final by bob", first)
+	}
+	if len(second.Changes) != 1 || second.Changes[0].Field != "priority" {
+		t.Errorf("second change = %+v, want only priority", second)
+	}
+	if second.Time.Before(first.Time) {
+		t.Errorf("changes out of order: %v then %v", first.Time, second.Time)
+	}
+
+	// Other users can't read the history of a task they can't read
+	alice := context.WithValue(context.Background(), "user", "alice")
+	if _, err := s.GetTaskHistory(alice, &GetTaskHistoryRequest{Name: task.Name}); err == nil {
+		t.Error("GetTaskHistory by another user = nil error, want permission denied")
+	}
+}
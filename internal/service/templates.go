@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+// templateDatePlaceholder in a title pattern is replaced by the creation date
+const templateDatePlaceholder = "{date}"
+
+// templateStore keeps task templates per tenant
+type templateStore struct {
+	mu        sync.Mutex
+	templates map[string]map[string]*todopb.TaskTemplate // tenant -> name -> template
+}
+
+func newTemplateStore() *templateStore {
+	return &templateStore{templates: make(map[string]map[string]*todopb.TaskTemplate)}
+}
+
+// CreateTaskTemplate stores a template in the caller's tenant
+func (s *TodoService) CreateTaskTemplate(ctx context.Context, req *todopb.CreateTaskTemplateRequest) (*todopb.TaskTemplate, error) {
+	ctx, span := tracer.Start(ctx, "CreateTaskTemplate")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, true, traceID); err != nil {
+		return nil, err
+	}
+
+	tmpl := req.GetTemplate()
+	if tmpl == nil {
+		return nil, errors.NewRequiredField("template", "Template object is required", traceID)
+	}
+	if err := validateTaskTemplate(tmpl, traceID); err != nil {
+		return nil, err
+	}
+
+	stored := &todopb.TaskTemplate{
+		Name:         "taskTemplates/" + uuid.New().String(),
+		TitlePattern: tmpl.TitlePattern,
+		Description:  tmpl.Description,
+		Priority:     tmpl.Priority,
+		Tags:         append([]string(nil), tmpl.Tags...),
+	}
+
+	tenant := s.getTenantFromContext(ctx)
+	s.templates.mu.Lock()
+	defer s.templates.mu.Unlock()
+	if s.templates.templates[tenant] == nil {
+		s.templates.templates[tenant] = make(map[string]*todopb.TaskTemplate)
+	}
+	s.templates.templates[tenant][stored.Name] = stored
+
+	return proto.Clone(stored).(*todopb.TaskTemplate), nil
+}
+
+// ListTaskTemplates returns the caller's tenant's templates ordered by name
+func (s *TodoService) ListTaskTemplates(ctx context.Context, req *todopb.ListTaskTemplatesRequest) (*todopb.ListTaskTemplatesResponse, error) {
+	ctx, span := tracer.Start(ctx, "ListTaskTemplates")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, false, traceID); err != nil {
+		return nil, err
+	}
+
+	tenant := s.getTenantFromContext(ctx)
+	s.templates.mu.Lock()
+	defer s.templates.mu.Unlock()
+
+	templates := make([]*todopb.TaskTemplate, 0, len(s.templates.templates[tenant]))
+	for _, tmpl := range s.templates.templates[tenant] {
+		templates = append(templates, proto.Clone(tmpl).(*todopb.TaskTemplate))
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+
+	return &todopb.ListTaskTemplatesResponse{Templates: templates}, nil
+}
+
+// CreateTaskFromTemplate creates a task from a stored template. The result is
+// validated exactly like a task passed to CreateTask.
+func (s *TodoService) CreateTaskFromTemplate(ctx context.Context, req *todopb.CreateTaskFromTemplateRequest) (*todopb.Task, error) {
+	ctx, span := tracer.Start(ctx, "CreateTaskFromTemplate")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if req == nil || req.Template == "" {
+		return nil, errors.NewRequiredField("template", "Template name is required", traceID)
+	}
+
+	s.templates.mu.Lock()
+	tmpl, ok := s.templates.templates[s.getTenantFromContext(ctx)][req.Template]
+	s.templates.mu.Unlock()
+	if !ok {
+		return nil, errors.NewNotFound("TaskTemplate", req.Template, traceID)
+	}
+
+	task := &todopb.Task{
+		Title:       strings.ReplaceAll(tmpl.TitlePattern, templateDatePlaceholder, s.now().Format("2006-01-02")),
+		Description: tmpl.Description,
+		Priority:    tmpl.Priority,
+		Tags:        append([]string(nil), tmpl.Tags...),
+	}
+	if o := req.Overrides; o != nil {
+		if o.Title != "" {
+			task.Title = o.Title
+		}
+		if o.Description != "" {
+			task.Description = o.Description
+		}
+		if o.Priority != todopb.Priority_PRIORITY_UNSPECIFIED {
+			task.Priority = o.Priority
+		}
+		if len(o.Tags) > 0 {
+			task.Tags = o.Tags
+		}
+		task.Status = o.Status
+		task.DueDate = o.DueDate
+		task.TimeZone = o.TimeZone
+	}
+
+	return s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: task})
+}
+
+// validateTaskTemplate checks the fields a template supplies; the tasks made
+// from it are validated again on creation
+func validateTaskTemplate(tmpl *todopb.TaskTemplate, traceID string) error {
+	v := errors.NewViolations()
+	if strings.TrimSpace(tmpl.TitlePattern) == "" {
+		v.Add("title_pattern", errorspb.AppErrorCode_REQUIRED_FIELD, "Title pattern is required")
+	} else if len(tmpl.TitlePattern) > 200 {
+		v.Addf("title_pattern", errorspb.AppErrorCode_TOO_LONG, "Title pattern must not exceed 200 characters, got %d", len(tmpl.TitlePattern))
+	}
+	if _, ok := todopb.Priority_name[int32(tmpl.Priority)]; !ok {
+		v.Addf("priority", errorspb.AppErrorCode_INVALID_VALUE, "Unknown priority %d", tmpl.Priority)
+	}
+	return v.Err(traceID)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+)
+
+func asTenantUser(tenant, user string) context.Context {
+	return context.WithValue(asUser(user), "tenant", tenant)
+}
+
+func TestTaskFromTemplateAppliesPatternAndOverrides(t *testing.T) {
+	s := newTestTodoService(t)
+	s.now = func() time.Time { return time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC) }
+	ctx := asTenantUser("acme", "bob")
+
+	tmpl, err := s.CreateTaskTemplate(ctx, &todopb.CreateTaskTemplateRequest{Template: &todopb.TaskTemplate{
+		TitlePattern: "Standup {date}",
+		Description:  "Daily sync",
+		Priority:     todopb.Priority_PRIORITY_LOW,
+		Tags:         []string{"meeting"},
+	}})
+	if err != nil {
+		t.Fatalf("CreateTaskTemplate: %v", err)
+	}
+
+	listed, err := s.ListTaskTemplates(ctx, &todopb.ListTaskTemplatesRequest{})
+	if err != nil {
+		t.Fatalf("ListTaskTemplates: %v", err)
+	}
+	if len(listed.Templates) != 1 || listed.Templates[0].Name != tmpl.Name {
+		t.Fatalf("ListTaskTemplates = %v, want [%s]", listed.Templates, tmpl.Name)
+	}
+
+	task, err := s.CreateTaskFromTemplate(ctx, &todopb.CreateTaskFromTemplateRequest{
+		Template:  tmpl.Name,
+		Overrides: &todopb.Task{Priority: todopb.Priority_PRIORITY_HIGH},
+	})
+	if err != nil {
+		t.Fatalf("CreateTaskFromTemplate: %v", err)
+	}
+	if task.Title != "Standup 2026-03-09" {
+		t.Errorf("title = %q, want the pattern with the date filled in", task.Title)
+	}
+	if task.Description != "Daily sync" || len(task.Tags) != 1 || task.Tags[0] != "meeting" {
+		t.Errorf("task = %v, want the template's description and tags", task)
+	}
+	if task.Priority != todopb.Priority_PRIORITY_HIGH {
+		t.Errorf("priority = %v, want the override", task.Priority)
+	}
+}
+
+func TestTaskTemplatesAreScopedToTenant(t *testing.T) {
+	s := newTestTodoService(t)
+	tmpl, err := s.CreateTaskTemplate(asTenantUser("acme", "bob"), &todopb.CreateTaskTemplateRequest{
+		Template: &todopb.TaskTemplate{TitlePattern: "Retro"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTaskTemplate: %v", err)
+	}
+
+	carol := asTenantUser("globex", "carol")
+	listed, err := s.ListTaskTemplates(carol, &todopb.ListTaskTemplatesRequest{})
+	if err != nil {
+		t.Fatalf("ListTaskTemplates: %v", err)
+	}
+	if len(listed.Templates) != 0 {
+		t.Errorf("other tenant listed %v", listed.Templates)
+	}
+
+	_, err = s.CreateTaskFromTemplate(carol, &todopb.CreateTaskFromTemplateRequest{Template: tmpl.Name})
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_RESOURCE_NOT_FOUND {
+		t.Errorf("other tenant's template = %v, want RESOURCE_NOT_FOUND", err)
+	}
+}
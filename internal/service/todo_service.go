@@ -2,32 +2,89 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
 	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/duedate"
 	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/fieldmask"
+	"github.com/bhatti/todo-api-errors/internal/monitoring"
 	"github.com/bhatti/todo-api-errors/internal/repository"
 	"github.com/bhatti/todo-api-errors/internal/validation"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var tracer = otel.Tracer("todo-service")
 
+const (
+	// taskChangesHeader carries the JSON list of fields changed by UpdateTask
+	taskChangesHeader = "x-task-changes"
+
+	// validationWarningsHeader carries soft validation warnings for a task that
+	// was accepted, as a JSON list of field violations
+	validationWarningsHeader = "x-validation-warnings"
+)
+
+// immutableTaskFields are managed by the server and cannot be set through an update mask
+var immutableTaskFields = []string{"name", "create_time", "update_time", "created_by", "tenant_id"}
+
 // TodoService implements the TODO API
 type TodoService struct {
 	todopb.UnimplementedTodoServiceServer
-	repo repository.TodoRepository
+	repo       repository.TodoRepository
+	history    *undoHistory
+	now        func() time.Time // clock used for due-date calculations
+	allowlists map[string]*validation.Allowlist
+	defaults   map[string]TaskDefaults // per-tenant defaults for new tasks
+
+	// uniqueTitles rejects a task whose title is already used in its tenant
+	uniqueTitles bool
+
+	// maxResponseBytes caps the estimated size of a ListTasks page; zero is unlimited
+	maxResponseBytes int
+
+	// defaultOrderBy sorts ListTasks when the request names no order
+	defaultOrderBy string
+
+	// anonymousPolicy decides what callers without credentials may do
+	anonymousPolicy AnonymousPolicy
+
+	templates *templateStore
+
+	// changeLog holds each task's field-level update history
+	changeLog *taskChangeLog
+
+	// mutationLog holds recent repository writes for debugging; nil when off
+	mutationLog *repository.MutationLog
 }
 
 // NewTodoService creates a new TODO service
 func NewTodoService(repo repository.TodoRepository) (*TodoService, error) {
 	return &TodoService{
-		repo: repo,
+		repo:            repo,
+		history:         newUndoHistory(),
+		now:             time.Now,
+		uniqueTitles:    true,
+		anonymousPolicy: AnonymousAllowAll,
+		templates:       newTemplateStore(),
+		changeLog:       newTaskChangeLog(),
 	}, nil
 }
 
@@ -37,32 +94,61 @@ func (s *TodoService) CreateTask(ctx context.Context, req *todopb.CreateTaskRequ
 	defer span.End()
 
 	// Get trace ID for error responses
-	traceID := span.SpanContext().TraceID().String()
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, true, traceID); err != nil {
+		return nil, err
+	}
 
 	// Validate request
 	if req.Task == nil {
 		return nil, errors.NewRequiredField("task", "Task object is required", traceID)
 	}
 
+	// Resolve a relative due date against the service clock
+	if req.RelativeDueDate != "" {
+		if req.Task.DueDate != nil {
+			return nil, errors.NewViolations().
+				Add("relative_due_date", errorspb.AppErrorCode_INVALID_ARGUMENT, "Set either relative_due_date or task.due_date, not both").
+				Err(traceID)
+		}
+		due, err := validation.ParseRelativeDueDate(req.RelativeDueDate, s.now().In(duedate.Location(req.Task)), traceID)
+		if err != nil {
+			return nil, err
+		}
+		req.Task.DueDate = timestamppb.New(due)
+	}
+
 	// Validate task fields using the new validation package
-	if err := validation.ValidateTask(req.Task, traceID); err != nil {
+	warnings, err := validation.ValidateTaskWithWarnings(req.Task, s.allowlistFor(ctx), traceID)
+	if err != nil {
 		span.SetAttributes(attribute.String("validation.error", err.Error()))
 		return nil, err
 	}
 
-	// Check for duplicate title
-	existing, err := s.repo.GetTaskByTitle(ctx, req.Task.Title)
-	if err != nil && !repository.IsNotFound(err) {
-		span.RecordError(err)
-		return nil, s.handleRepositoryError(err, traceID)
+	// Use the client-provided ID if there is one, otherwise generate it
+	taskID := req.TaskId
+	if taskID != "" {
+		if err := validation.ValidateTaskID(taskID, traceID); err != nil {
+			return nil, err
+		}
+	} else {
+		taskID = uuid.New().String()
 	}
 
-	if existing != nil {
-		return nil, errors.NewConflict("task", "A task with this title already exists", traceID)
+	// Check for duplicate title
+	if s.uniqueTitles {
+		existing, err := s.repo.GetTaskByTitle(ctx, s.getTenantFromContext(ctx), req.Task.Title)
+		if err != nil && !repository.IsNotFound(err) {
+			span.RecordError(err)
+			return nil, s.handleRepositoryError(err, traceID)
+		}
+
+		if existing != nil {
+			return nil, titleConflict(req.Task.Title, existing.Name, s.suggestTitle(ctx, req.Task.Title), traceID)
+		}
 	}
 
-	// Generate task ID
-	taskID := uuid.New().String()
 	task := &todopb.Task{
 		Name:        fmt.Sprintf("tasks/%s", taskID),
 		Title:       req.Task.Title,
@@ -71,21 +157,37 @@ func (s *TodoService) CreateTask(ctx context.Context, req *todopb.CreateTaskRequ
 		Priority:    req.Task.Priority,
 		DueDate:     req.Task.DueDate,
 		Tags:        req.Task.Tags,
+		TimeZone:    req.Task.TimeZone,
 		CreateTime:  timestamppb.Now(),
 		UpdateTime:  timestamppb.Now(),
 		CreatedBy:   s.getUserFromContext(ctx),
+		TenantId:    s.getTenantFromContext(ctx),
 	}
 
 	// Set defaults
+	defaults := s.defaultsFor(ctx)
 	if task.Status == todopb.Status_STATUS_UNSPECIFIED {
-		task.Status = todopb.Status_STATUS_PENDING
+		task.Status = defaults.Status
 	}
 	if task.Priority == todopb.Priority_PRIORITY_UNSPECIFIED {
-		task.Priority = todopb.Priority_PRIORITY_MEDIUM
+		task.Priority = defaults.Priority
 	}
 
 	// Save to repository
 	if err := s.repo.CreateTask(ctx, task); err != nil {
+		if repository.IsTitleExists(err) {
+			// Another request took the title after the check above
+			return nil, titleConflict(task.Title, "", s.suggestTitle(ctx, task.Title), traceID)
+		}
+		if repository.IsIDExists(err) {
+			return nil, errors.NewFieldConflict("task", fmt.Sprintf("Task ID '%s' is already in use", taskID), []*errorspb.FieldViolation{
+				{
+					Field:       "task_id",
+					Code:        errorspb.AppErrorCode_RESOURCE_CONFLICT.String(),
+					Description: fmt.Sprintf("Task ID '%s' is already used by another task", taskID),
+				},
+			}, traceID)
+		}
 		span.RecordError(err)
 		return nil, s.handleRepositoryError(err, traceID)
 	}
@@ -95,6 +197,9 @@ func (s *TodoService) CreateTask(ctx context.Context, req *todopb.CreateTaskRequ
 		attribute.String("task.title", task.Title),
 	)
 
+	s.history.Record(s.historyKey(ctx), operationCreate, nil, task)
+	setValidationWarnings(ctx, warnings)
+
 	return task, nil
 }
 
@@ -103,7 +208,11 @@ func (s *TodoService) GetTask(ctx context.Context, req *todopb.GetTaskRequest) (
 	ctx, span := tracer.Start(ctx, "GetTask")
 	defer span.End()
 
-	traceID := span.SpanContext().TraceID().String()
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, false, traceID); err != nil {
+		return nil, err
+	}
 
 	// Validate request using the new validation package
 	if err := validation.ValidateRequest(req, traceID); err != nil {
@@ -120,7 +229,7 @@ func (s *TodoService) GetTask(ctx context.Context, req *todopb.GetTaskRequest) (
 	span.SetAttributes(attribute.String("task.id", taskID))
 
 	// Get from repository
-	task, err := s.repo.GetTask(ctx, taskID)
+	task, err := s.repo.GetTask(ctx, s.getTenantFromContext(ctx), taskID)
 	if err != nil {
 		if repository.IsNotFound(err) {
 			return nil, errors.NewNotFound("Task", taskID, traceID)
@@ -131,7 +240,7 @@ func (s *TodoService) GetTask(ctx context.Context, req *todopb.GetTaskRequest) (
 
 	// Check permissions
 	if !s.canAccessTask(ctx, task) {
-		return nil, errors.NewPermissionDenied("task", "read", traceID)
+		return nil, errors.NewPermissionDenied("task", "read", adminRole, traceID)
 	}
 
 	return task, nil
@@ -142,7 +251,11 @@ func (s *TodoService) ListTasks(ctx context.Context, req *todopb.ListTasksReques
 	ctx, span := tracer.Start(ctx, "ListTasks")
 	defer span.End()
 
-	traceID := span.SpanContext().TraceID().String()
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, false, traceID); err != nil {
+		return nil, err
+	}
 
 	// Validate request using the new validation package
 	if err := validation.ValidateRequest(req, traceID); err != nil {
@@ -166,7 +279,15 @@ func (s *TodoService) ListTasks(ctx context.Context, req *todopb.ListTasksReques
 	// Parse filter
 	filter, err := s.parseFilter(req.Filter)
 	if err != nil {
-		return nil, errors.NewRequiredField("filter", fmt.Sprintf("Failed to parse filter: %v", err), traceID)
+		if syntaxErr, ok := err.(*filterSyntaxError); ok {
+			return nil, errors.NewInvalidFilter(syntaxErr.token, syntaxErr.position, syntaxErr.reason, traceID)
+		}
+		return nil, errors.NewInvalidArgument(fmt.Sprintf("Failed to parse filter: %v", err), traceID)
+	}
+
+	orderBy := req.OrderBy
+	if orderBy == "" {
+		orderBy = s.defaultOrderBy
 	}
 
 	// Get tasks from repository
@@ -174,7 +295,8 @@ func (s *TodoService) ListTasks(ctx context.Context, req *todopb.ListTasksReques
 		PageSize:  int(pageSize),
 		PageToken: req.PageToken,
 		Filter:    filter,
-		OrderBy:   req.OrderBy,
+		OrderBy:   orderBy,
+		TenantID:  s.getTenantFromContext(ctx),
 		UserID:    s.getUserFromContext(ctx),
 	})
 
@@ -183,12 +305,20 @@ func (s *TodoService) ListTasks(ctx context.Context, req *todopb.ListTasksReques
 		return nil, s.handleRepositoryError(err, traceID)
 	}
 
-	// Get total count
-	totalSize, err := s.repo.CountTasks(ctx, filter, s.getUserFromContext(ctx))
-	if err != nil {
-		// Log but don't fail the request
-		span.RecordError(err)
-		totalSize = -1
+	// Fail fast instead of serializing a page that is too large to send
+	if err := s.checkResponseSize(tasks, traceID); err != nil {
+		return nil, err
+	}
+
+	// Get total count unless the client opted out; counting is costly on large stores
+	totalSize := -1
+	if !req.SkipTotal {
+		totalSize, err = s.repo.CountTasks(ctx, filter, s.getTenantFromContext(ctx), s.getUserFromContext(ctx))
+		if err != nil {
+			// Log but don't fail the request
+			span.RecordError(err)
+			totalSize = -1
+		}
 	}
 
 	return &todopb.ListTasksResponse{
@@ -203,7 +333,11 @@ func (s *TodoService) UpdateTask(ctx context.Context, req *todopb.UpdateTaskRequ
 	ctx, span := tracer.Start(ctx, "UpdateTask")
 	defer span.End()
 
-	traceID := span.SpanContext().TraceID().String()
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, true, traceID); err != nil {
+		return nil, err
+	}
 
 	// Validate request
 	if req.Task == nil {
@@ -224,7 +358,7 @@ func (s *TodoService) UpdateTask(ctx context.Context, req *todopb.UpdateTaskRequ
 	span.SetAttributes(attribute.String("task.id", taskID))
 
 	// Get existing task
-	existing, err := s.repo.GetTask(ctx, taskID)
+	existing, err := s.repo.GetTask(ctx, s.getTenantFromContext(ctx), taskID)
 	if err != nil {
 		if repository.IsNotFound(err) {
 			return nil, errors.NewNotFound("Task", taskID, traceID)
@@ -234,24 +368,42 @@ func (s *TodoService) UpdateTask(ctx context.Context, req *todopb.UpdateTaskRequ
 
 	// Check permissions
 	if !s.canModifyTask(ctx, existing) {
-		return nil, errors.NewPermissionDenied("task", "update", traceID)
+		return nil, errors.NewPermissionDenied("task", "update", adminRole, traceID)
 	}
 
 	// Apply updates based on field mask
-	updated := s.applyFieldMask(existing, req.Task, req.UpdateMask)
+	updated, err := s.applyFieldMask(existing, req.Task, req.UpdateMask, traceID)
+	if err != nil {
+		return nil, err
+	}
 	updated.UpdateTime = timestamppb.Now()
 
 	// Validate updated task using the new validation package
-	if err := validation.ValidateTask(updated, traceID); err != nil {
+	warnings, err := validation.ValidateTaskWithWarnings(updated, s.allowlistFor(ctx), traceID)
+	if err != nil {
 		return nil, err
 	}
 
 	// Save to repository
 	if err := s.repo.UpdateTask(ctx, updated); err != nil {
+		if repository.IsTitleExists(err) {
+			return nil, s.updateTitleConflict(ctx, updated.Title, traceID)
+		}
 		span.RecordError(err)
 		return nil, s.handleRepositoryError(err, traceID)
 	}
 
+	s.history.Record(s.historyKey(ctx), operationUpdate, existing, updated)
+	s.recordChange(ctx, existing, updated, req.UpdateMask)
+	setValidationWarnings(ctx, warnings)
+
+	// Report exactly what changed; the gateway forwards this as Grpc-Metadata-X-Task-Changes
+	if changes := fieldmask.Diff(existing, updated, req.UpdateMask); len(changes) > 0 {
+		if data, err := json.Marshal(changes); err == nil {
+			_ = grpc.SetHeader(ctx, metadata.Pairs(taskChangesHeader, string(data)))
+		}
+	}
+
 	return updated, nil
 }
 
@@ -260,7 +412,11 @@ func (s *TodoService) DeleteTask(ctx context.Context, req *todopb.DeleteTaskRequ
 	ctx, span := tracer.Start(ctx, "DeleteTask")
 	defer span.End()
 
-	traceID := span.SpanContext().TraceID().String()
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, true, traceID); err != nil {
+		return nil, err
+	}
 
 	// Validate request using the new validation package
 	if err := validation.ValidateRequest(req, traceID); err != nil {
@@ -277,7 +433,7 @@ func (s *TodoService) DeleteTask(ctx context.Context, req *todopb.DeleteTaskRequ
 	span.SetAttributes(attribute.String("task.id", taskID))
 
 	// Get existing task to check permissions
-	existing, err := s.repo.GetTask(ctx, taskID)
+	existing, err := s.repo.GetTask(ctx, s.getTenantFromContext(ctx), taskID)
 	if err != nil {
 		if repository.IsNotFound(err) {
 			return nil, errors.NewNotFound("Task", taskID, traceID)
@@ -287,15 +443,18 @@ func (s *TodoService) DeleteTask(ctx context.Context, req *todopb.DeleteTaskRequ
 
 	// Check permissions
 	if !s.canModifyTask(ctx, existing) {
-		return nil, errors.NewPermissionDenied("task", "delete", traceID)
+		return nil, errors.NewPermissionDenied("task", "delete", adminRole, traceID)
 	}
 
 	// Delete from repository
-	if err := s.repo.DeleteTask(ctx, taskID); err != nil {
+	if err := s.repo.DeleteTask(ctx, existing.TenantId, taskID); err != nil {
 		span.RecordError(err)
 		return nil, s.handleRepositoryError(err, traceID)
 	}
 
+	s.history.Record(s.historyKey(ctx), operationDelete, existing, nil)
+	s.forgetChanges(existing.TenantId, taskID)
+
 	return &todopb.DeleteTaskResponse{
 		Message: fmt.Sprintf("Task %s deleted successfully", req.Name),
 	}, nil
@@ -306,10 +465,14 @@ func (s *TodoService) BatchCreateTasks(ctx context.Context, req *todopb.BatchCre
 	ctx, span := tracer.Start(ctx, "BatchCreateTasks")
 	defer span.End()
 
-	traceID := span.SpanContext().TraceID().String()
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, true, traceID); err != nil {
+		return nil, err
+	}
 
 	// Validate batch request using the new validation package
-	if err := validation.ValidateBatchCreateTasks(req, traceID); err != nil {
+	if err := validation.ValidateBatchCreateTasks(req, s.uniqueTitles, traceID); err != nil {
 		span.SetAttributes(attribute.String("validation.error", err.Error()))
 		return nil, err
 	}
@@ -350,11 +513,567 @@ func (s *TodoService) BatchCreateTasks(ctx context.Context, req *todopb.BatchCre
 	return response, nil
 }
 
+// RenameTag renames a tag across every task the caller can modify. Admins
+// rename it on all tasks. A task that already carries the new tag keeps a
+// single copy of it.
+func (s *TodoService) RenameTag(ctx context.Context, req *todopb.RenameTagRequest) (*todopb.RenameTagResponse, error) {
+	ctx, span := tracer.Start(ctx, "RenameTag")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, true, traceID); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateRenameTag(req, traceID); err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("tag.from", req.From),
+		attribute.String("tag.to", req.To),
+	)
+
+	// Collect every page of the caller's tasks before changing any of them
+	opts := repository.ListOptions{
+		PageSize: 100,
+		TenantID: s.getTenantFromContext(ctx),
+		UserID:   s.getUserFromContext(ctx),
+	}
+	var tasks []*todopb.Task
+	for {
+		page, nextToken, err := s.repo.ListTasks(ctx, opts)
+		if err != nil {
+			span.RecordError(err)
+			return nil, s.handleRepositoryError(err, traceID)
+		}
+		tasks = append(tasks, page...)
+		if nextToken == "" {
+			break
+		}
+		opts.PageToken = nextToken
+	}
+
+	var affected int32
+	for _, task := range tasks {
+		tags, renamed := renameTag(task.Tags, req.From, req.To)
+		if !renamed || !s.canModifyTask(ctx, task) {
+			continue
+		}
+
+		updated := proto.Clone(task).(*todopb.Task)
+		updated.Tags = tags
+		updated.UpdateTime = timestamppb.Now()
+		if err := s.repo.UpdateTask(ctx, updated); err != nil {
+			span.RecordError(err)
+			return nil, s.handleRepositoryError(err, traceID)
+		}
+		s.recordChange(ctx, task, updated, &fieldmaskpb.FieldMask{Paths: []string{"tags"}})
+		affected++
+	}
+
+	span.SetAttributes(attribute.Int("tag.affected_count", int(affected)))
+
+	return &todopb.RenameTagResponse{AffectedCount: affected}, nil
+}
+
+// ReassignUserTasks transfers ownership of every task from_user created in the
+// caller's tenant to to_user, e.g. when from_user is offboarded. Only admins
+// may call it. Running it again moves nothing, so retries are safe.
+func (s *TodoService) ReassignUserTasks(ctx context.Context, req *todopb.ReassignUserTasksRequest) (*todopb.ReassignUserTasksResponse, error) {
+	ctx, span := tracer.Start(ctx, "ReassignUserTasks")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, true, traceID); err != nil {
+		return nil, err
+	}
+
+	if s.getUserFromContext(ctx) != adminRole {
+		return nil, errors.NewPermissionDenied("tasks", "reassign", adminRole, traceID)
+	}
+
+	if err := validation.ValidateReassignUserTasks(req, traceID); err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("reassign.from_user", req.FromUser),
+		attribute.String("reassign.to_user", req.ToUser),
+	)
+
+	// Collect every page of from_user's tasks before changing any of them
+	opts := repository.ListOptions{
+		PageSize: 100,
+		TenantID: s.getTenantFromContext(ctx),
+		UserID:   req.FromUser,
+	}
+	var tasks []*todopb.Task
+	for {
+		page, nextToken, err := s.repo.ListTasks(ctx, opts)
+		if err != nil {
+			span.RecordError(err)
+			return nil, s.handleRepositoryError(err, traceID)
+		}
+		tasks = append(tasks, page...)
+		if nextToken == "" {
+			break
+		}
+		opts.PageToken = nextToken
+	}
+
+	var moved int32
+	for _, task := range tasks {
+		// The repository lists every task when from_user is the admin role
+		if task.CreatedBy != req.FromUser {
+			continue
+		}
+
+		updated := proto.Clone(task).(*todopb.Task)
+		updated.CreatedBy = req.ToUser
+		updated.UpdateTime = timestamppb.Now()
+		if err := s.repo.UpdateTask(ctx, updated); err != nil {
+			span.RecordError(err)
+			return nil, s.handleRepositoryError(err, traceID)
+		}
+		s.recordChange(ctx, task, updated, &fieldmaskpb.FieldMask{Paths: []string{"created_by"}})
+		moved++
+	}
+
+	span.SetAttributes(attribute.Int("reassign.moved_count", int(moved)))
+
+	return &todopb.ReassignUserTasksResponse{MovedCount: moved}, nil
+}
+
+// renameTag replaces from with to, keeping a single copy of to if the task already had it
+func renameTag(tags []string, from, to string) ([]string, bool) {
+	renamed := false
+	hasTarget := false
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		switch tag {
+		case from:
+			renamed = true
+			if !hasTarget {
+				result = append(result, to)
+				hasTarget = true
+			}
+		case to:
+			if !hasTarget {
+				result = append(result, to)
+				hasTarget = true
+			}
+		default:
+			result = append(result, tag)
+		}
+	}
+	return result, renamed
+}
+
+// SuggestTags returns the caller's existing tags that start with a prefix,
+// most used first, to encourage reusing tags over near-duplicates
+func (s *TodoService) SuggestTags(ctx context.Context, req *todopb.SuggestTagsRequest) (*todopb.SuggestTagsResponse, error) {
+	ctx, span := tracer.Start(ctx, "SuggestTags")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, false, traceID); err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	counts, err := s.repo.TagCounts(ctx, s.getTenantFromContext(ctx), s.getUserFromContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		return nil, s.handleRepositoryError(err, traceID)
+	}
+
+	prefix := strings.ToLower(req.Prefix)
+	var suggestions []*todopb.TagSuggestion
+	for tag, count := range counts {
+		if strings.HasPrefix(tag, prefix) {
+			suggestions = append(suggestions, &todopb.TagSuggestion{Tag: tag, Count: int32(count)})
+		}
+	}
+
+	// Most used first; ties broken alphabetically so results are stable
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Count != suggestions[j].Count {
+			return suggestions[i].Count > suggestions[j].Count
+		}
+		return suggestions[i].Tag < suggestions[j].Tag
+	})
+	if len(suggestions) > pageSize {
+		suggestions = suggestions[:pageSize]
+	}
+
+	return &todopb.SuggestTagsResponse{Suggestions: suggestions}, nil
+}
+
+// GetTaskStats returns aggregate counts over the caller's tasks, or over all
+// tasks for an admin. Overdue is judged against the service clock.
+func (s *TodoService) GetTaskStats(ctx context.Context, req *todopb.GetTaskStatsRequest) (*todopb.TaskStats, error) {
+	ctx, span := tracer.Start(ctx, "GetTaskStats")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, false, traceID); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.TaskStats(ctx, s.getTenantFromContext(ctx), s.getUserFromContext(ctx), s.now())
+	if err != nil {
+		span.RecordError(err)
+		return nil, s.handleRepositoryError(err, traceID)
+	}
+
+	resp := &todopb.TaskStats{
+		TotalCount:   int32(stats.Total),
+		OverdueCount: int32(stats.Overdue),
+	}
+	if stats.NextDue != nil {
+		resp.NextDueDate = timestamppb.New(*stats.NextDue)
+	}
+
+	// Report counts in enum order so the response is stable
+	for _, value := range sortedEnumNumbers(todopb.Status_name) {
+		if count := stats.ByStatus[todopb.Status(value)]; count > 0 {
+			resp.ByStatus = append(resp.ByStatus, &todopb.StatusCount{Status: todopb.Status(value), Count: int32(count)})
+		}
+	}
+	for _, value := range sortedEnumNumbers(todopb.Priority_name) {
+		if count := stats.ByPriority[todopb.Priority(value)]; count > 0 {
+			resp.ByPriority = append(resp.ByPriority, &todopb.PriorityCount{Priority: todopb.Priority(value), Count: int32(count)})
+		}
+	}
+
+	return resp, nil
+}
+
+// BatchGetTasks fetches several tasks in one call. Unlike GetTask, a missing or
+// unreadable task doesn't fail the request; each name gets its own status.
+func (s *TodoService) BatchGetTasks(ctx context.Context, req *todopb.BatchGetTasksRequest) (*todopb.BatchGetTasksResponse, error) {
+	ctx, span := tracer.Start(ctx, "BatchGetTasks")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, false, traceID); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateBatchGetTasks(req, traceID); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("batch.size", len(req.Names)))
+
+	tenantID := s.getTenantFromContext(ctx)
+	resp := &todopb.BatchGetTasksResponse{}
+	for _, name := range req.Names {
+		result := &todopb.BatchGetResult{Name: name}
+
+		task, err := s.repo.GetTask(ctx, tenantID, strings.TrimPrefix(name, "tasks/"))
+		switch {
+		case repository.IsNotFound(err):
+			result.Status = todopb.BatchGetStatus_BATCH_GET_STATUS_NOT_FOUND
+		case err != nil:
+			span.RecordError(err)
+			return nil, s.handleRepositoryError(err, traceID)
+		case !s.canAccessTask(ctx, task):
+			result.Status = todopb.BatchGetStatus_BATCH_GET_STATUS_FORBIDDEN
+		default:
+			result.Status = todopb.BatchGetStatus_BATCH_GET_STATUS_FOUND
+			result.Task = task
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
+
+func sortedEnumNumbers(names map[int32]string) []int32 {
+	numbers := make([]int32, 0, len(names))
+	for number := range names {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	return numbers
+}
+
+// UndoLastOperation reverses the caller's most recent create, update or delete.
+// Only the last operation is kept, so a second undo has nothing to reverse.
+func (s *TodoService) UndoLastOperation(ctx context.Context, req *todopb.UndoLastOperationRequest) (*todopb.UndoLastOperationResponse, error) {
+	ctx, span := tracer.Start(ctx, "UndoLastOperation")
+	defer span.End()
+
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if err := s.checkAnonymous(ctx, true, traceID); err != nil {
+		return nil, err
+	}
+
+	key := s.historyKey(ctx)
+	op, ok := s.history.Last(key)
+	if !ok {
+		return nil, errors.NewNotFound("Operation", "last", traceID)
+	}
+	span.SetAttributes(attribute.String("undo.operation", op.kind))
+
+	var (
+		result *todopb.Task
+		err    error
+	)
+	switch op.kind {
+	case operationCreate:
+		result, err = s.undoCreate(ctx, op, traceID)
+	case operationUpdate:
+		result, err = s.undoUpdate(ctx, op, traceID)
+	case operationDelete:
+		result, err = s.undoDelete(ctx, op, traceID)
+	default:
+		err = errors.NewInternal("Unknown operation in undo history", traceID, fmt.Errorf("operation %q", op.kind))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.history.Clear(key, op)
+
+	return &todopb.UndoLastOperationResponse{
+		Operation: op.kind,
+		Task:      result,
+	}, nil
+}
+
+// undoCreate deletes a task the caller created, as long as it hasn't changed since
+func (s *TodoService) undoCreate(ctx context.Context, op *taskOperation, traceID string) (*todopb.Task, error) {
+	taskID := strings.TrimPrefix(op.after.Name, "tasks/")
+	if err := s.checkUnchangedSince(ctx, op.after.TenantId, taskID, op.after, traceID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteTask(ctx, op.after.TenantId, taskID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, errors.NewNotFound("Task", taskID, traceID)
+		}
+		return nil, s.handleRepositoryError(err, traceID)
+	}
+	return op.after, nil
+}
+
+// undoUpdate restores a task to its state before the caller's update
+func (s *TodoService) undoUpdate(ctx context.Context, op *taskOperation, traceID string) (*todopb.Task, error) {
+	taskID := strings.TrimPrefix(op.after.Name, "tasks/")
+	if err := s.checkUnchangedSince(ctx, op.after.TenantId, taskID, op.after, traceID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateTask(ctx, op.before); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, errors.NewNotFound("Task", taskID, traceID)
+		}
+		if repository.IsTitleExists(err) {
+			return nil, s.updateTitleConflict(ctx, op.before.Title, traceID)
+		}
+		return nil, s.handleRepositoryError(err, traceID)
+	}
+	s.recordChange(ctx, op.after, op.before, editableTaskFields)
+	return op.before, nil
+}
+
+// undoDelete recreates a deleted task under its original name
+func (s *TodoService) undoDelete(ctx context.Context, op *taskOperation, traceID string) (*todopb.Task, error) {
+	taskID := strings.TrimPrefix(op.before.Name, "tasks/")
+	if err := s.repo.CreateTask(ctx, op.before); err != nil {
+		if repository.IsTitleExists(err) {
+			return nil, titleConflict(op.before.Title, "", "", traceID)
+		}
+		if repository.IsIDExists(err) {
+			return nil, errors.NewConflict("task", fmt.Sprintf("Task ID '%s' has been reused since it was deleted", taskID), traceID)
+		}
+		return nil, s.handleRepositoryError(err, traceID)
+	}
+	return op.before, nil
+}
+
+// checkUnchangedSince refuses to undo over a task that was modified after the
+// recorded operation, so an undo never discards someone else's change
+func (s *TodoService) checkUnchangedSince(ctx context.Context, tenantID, taskID string, want *todopb.Task, traceID string) error {
+	current, err := s.repo.GetTask(ctx, tenantID, taskID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return errors.NewNotFound("Task", taskID, traceID)
+		}
+		return s.handleRepositoryError(err, traceID)
+	}
+	if !proto.Equal(current, want) {
+		return errors.NewConflict("task", "The task was modified after the operation being undone", traceID)
+	}
+	return nil
+}
+
 // Helper methods
 
+// listAllTasks pages through ListTasks so exports share its filtering and
+// permission rules
+func (s *TodoService) listAllTasks(ctx context.Context, filter, orderBy string) ([]*todopb.Task, error) {
+	req := &todopb.ListTasksRequest{PageSize: 100, Filter: filter, OrderBy: orderBy}
+	var tasks []*todopb.Task
+	for {
+		resp, err := s.ListTasks(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, resp.Tasks...)
+		if resp.NextPageToken == "" {
+			return tasks, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// setValidationWarnings attaches soft warnings to a successful response; the
+// gateway forwards them as Grpc-Metadata-X-Validation-Warnings
+func setValidationWarnings(ctx context.Context, warnings []*errorspb.FieldViolation) {
+	if len(warnings) == 0 {
+		return
+	}
+	if data, err := json.Marshal(warnings); err == nil {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(validationWarningsHeader, string(data)))
+	}
+}
+
+// titleConflict reports a title already used in the tenant. usedBy names the
+// task holding it and suggested is a free variant of the title, when known.
+func titleConflict(title, usedBy, suggested string, traceID string) error {
+	description := fmt.Sprintf("Title '%s' is already used by another task", title)
+	if usedBy != "" {
+		description = fmt.Sprintf("Title '%s' is already used by %s", title, usedBy)
+	}
+	appErr := errors.NewFieldConflict("task", "A task with this title already exists", []*errorspb.FieldViolation{
+		{
+			Field:       "title",
+			Code:        errorspb.AppErrorCode_DUPLICATE_TITLE.String(),
+			Description: description,
+		},
+	}, traceID)
+
+	// Let import tools retry with a title that is free right now
+	if suggested != "" {
+		if hint, err := structpb.NewStruct(map[string]interface{}{"title": suggested}); err == nil {
+			if ext, err := anypb.New(hint); err == nil {
+				appErr.Extensions = map[string]*anypb.Any{"suggestedTitle": ext}
+			}
+		}
+	}
+
+	return appErr
+}
+
+// maxTitleSuggestions bounds how many " (n)" variants suggestTitle tries
+const maxTitleSuggestions = 100
+
+// databaseRetryAfter is how long clients are asked to wait when storage is
+// unreachable
+const databaseRetryAfter = 5 * time.Second
+
+// titleCounterSuffix matches a " (n)" counter already on a title
+var titleCounterSuffix = regexp.MustCompile(` \((\d+)\)$`)
+
+// suggestTitle returns the first variant "<title> (n)" not used in the
+// tenant, counting on from any counter the title already has. It returns ""
+// if none of the variants tried is free.
+func (s *TodoService) suggestTitle(ctx context.Context, title string) string {
+	base, next := title, 2
+	if m := titleCounterSuffix.FindStringSubmatch(title); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			base, next = strings.TrimSuffix(title, m[0]), n+1
+		}
+	}
+
+	tenant := s.getTenantFromContext(ctx)
+	for n := next; n < next+maxTitleSuggestions; n++ {
+		candidate := fmt.Sprintf("%s (%d)", base, n)
+		if _, err := s.repo.GetTaskByTitle(ctx, tenant, candidate); repository.IsNotFound(err) {
+			return candidate
+		} else if err != nil {
+			return ""
+		}
+	}
+	return ""
+}
+
+// updateTitleConflict reports an update that would give a task the title of
+// another task in the tenant, naming that task when it can be found
+func (s *TodoService) updateTitleConflict(ctx context.Context, title, traceID string) error {
+	var usedBy string
+	if holder, err := s.repo.GetTaskByTitle(ctx, s.getTenantFromContext(ctx), title); err == nil {
+		usedBy = holder.Name
+	}
+	return titleConflict(title, usedBy, s.suggestTitle(ctx, title), traceID)
+}
+
+// SetMutationLog exposes the repository's recent writes through
+// RecentMutations; nil disables it
+func (s *TodoService) SetMutationLog(log *repository.MutationLog) {
+	s.mutationLog = log
+}
+
+// RecentMutations returns the latest task writes in the caller's tenant,
+// oldest first, to help reproduce reports of vanished or reverted tasks.
+// Admins only.
+func (s *TodoService) RecentMutations(ctx context.Context) ([]repository.Mutation, error) {
+	traceID := monitoring.TraceIDFromContext(ctx)
+
+	if s.getUserFromContext(ctx) != adminRole {
+		return nil, errors.NewPermissionDenied("mutations", "read", adminRole, traceID)
+	}
+	if s.mutationLog == nil {
+		return nil, errors.NewFeatureDisabled("mutation log", traceID)
+	}
+
+	return s.mutationLog.Recent(s.getTenantFromContext(ctx)), nil
+}
+
+// Ready reports whether the service can reach its storage, for readiness
+// probes. An unreachable store is a SERVICE_UNAVAILABLE error.
+func (s *TodoService) Ready(ctx context.Context) error {
+	if err := s.repo.Ping(ctx); err != nil {
+		return s.handleRepositoryError(err, monitoring.TraceIDFromContext(ctx))
+	}
+	return nil
+}
+
 func (s *TodoService) handleRepositoryError(err error, traceID string) error {
+	// Context errors usually arrive wrapped, so match them with errors.Is
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return errors.NewTimeout(traceID)
+	}
+	if stderrors.Is(err, context.Canceled) {
+		return errors.NewCanceled(traceID)
+	}
+
+	if repository.IsInvalidPageToken(err) {
+		return errors.NewValidationFailed([]*errorspb.FieldViolation{{
+			Field:       "page_token",
+			Code:        errorspb.AppErrorCode_INVALID_FORMAT.String(),
+			Description: "The page token is invalid or was issued for a different order_by or filter; start again without one",
+		}}, traceID)
+	}
+
 	if repository.IsConnectionError(err) {
-		return errors.NewServiceUnavailable("Unable to connect to the database. Please try again later.", traceID)
+		return errors.NewServiceUnavailable("database", "Unable to connect to the database. Please try again later.", databaseRetryAfter, traceID)
 	}
 
 	// Log internal error details
@@ -366,75 +1085,169 @@ func (s *TodoService) handleRepositoryError(err error, traceID string) error {
 	return errors.NewInternal("An unexpected error occurred while processing your request", traceID, err)
 }
 
+// SetTenantAllowlists restricts the statuses and priorities each tenant may
+// use, keyed by tenant ID. Tenants without an entry are unrestricted.
+func (s *TodoService) SetTenantAllowlists(allowlists map[string]*validation.Allowlist) {
+	s.allowlists = allowlists
+}
+
+// SetAnonymousPolicy decides whether anonymous callers may read and change
+// tasks, only read them, or do nothing at all
+func (s *TodoService) SetAnonymousPolicy(policy AnonymousPolicy) {
+	s.anonymousPolicy = policy
+}
+
+// SetUniqueTitles turns the per-tenant duplicate title check on or off. It
+// should match the repository's own setting.
+func (s *TodoService) SetUniqueTitles(enabled bool) {
+	s.uniqueTitles = enabled
+}
+
+// SetMaxResponseBytes limits the estimated size of a ListTasks page. Pages
+// over the limit fail with RESPONSE_TOO_LARGE; zero disables the check.
+func (s *TodoService) SetMaxResponseBytes(limit int) {
+	s.maxResponseBytes = limit
+}
+
+// SetDefaultOrderBy sets the order ListTasks uses when the request has no
+// order_by. It must be one of repository.SortOrders, or "" for title order.
+func (s *TodoService) SetDefaultOrderBy(orderBy string) error {
+	if orderBy != "" && !repository.IsSortOrder(orderBy) {
+		return fmt.Errorf("unknown order %q; expected one of %s", orderBy, strings.Join(repository.SortOrders, ", "))
+	}
+	s.defaultOrderBy = orderBy
+	return nil
+}
+
+// checkResponseSize estimates the page size from the tasks' wire size, which
+// is cheaper than serializing and close to the JSON size for text-heavy tasks
+func (s *TodoService) checkResponseSize(tasks []*todopb.Task, traceID string) error {
+	if s.maxResponseBytes <= 0 || len(tasks) == 0 {
+		return nil
+	}
+
+	size := 0
+	for _, task := range tasks {
+		size += proto.Size(task)
+	}
+	if size <= s.maxResponseBytes {
+		return nil
+	}
+
+	perTask := (size + len(tasks) - 1) / len(tasks)
+	suggested := s.maxResponseBytes / perTask
+	if suggested < 1 {
+		suggested = 1
+	}
+	return errors.NewResponseTooLarge(size, s.maxResponseBytes, suggested, traceID)
+}
+
+// TaskDefaults are the status and priority given to new tasks that omit them.
+// Unspecified values fall back to STATUS_PENDING and PRIORITY_MEDIUM.
+type TaskDefaults struct {
+	Status   todopb.Status
+	Priority todopb.Priority
+}
+
+// SetTenantDefaults overrides the defaults for new tasks per tenant, keyed by
+// tenant ID
+func (s *TodoService) SetTenantDefaults(defaults map[string]TaskDefaults) {
+	s.defaults = defaults
+}
+
+func (s *TodoService) defaultsFor(ctx context.Context) TaskDefaults {
+	defaults := s.defaults[s.getTenantFromContext(ctx)]
+	if defaults.Status == todopb.Status_STATUS_UNSPECIFIED {
+		defaults.Status = todopb.Status_STATUS_PENDING
+	}
+	if defaults.Priority == todopb.Priority_PRIORITY_UNSPECIFIED {
+		defaults.Priority = todopb.Priority_PRIORITY_MEDIUM
+	}
+	return defaults
+}
+
+func (s *TodoService) allowlistFor(ctx context.Context) *validation.Allowlist {
+	return s.allowlists[s.getTenantFromContext(ctx)]
+}
+
+// historyKey identifies the caller's undo history; users with the same name
+// in different tenants are different callers
+func (s *TodoService) historyKey(ctx context.Context) string {
+	return s.getTenantFromContext(ctx) + "/" + s.getUserFromContext(ctx)
+}
+
+func (s *TodoService) getTenantFromContext(ctx context.Context) string {
+	// Set by the auth layer; empty when the caller has no tenant
+	if tenant, ok := ctx.Value("tenant").(string); ok {
+		return tenant
+	}
+	return ""
+}
+
 func (s *TodoService) getUserFromContext(ctx context.Context) string {
 	// In a real implementation, this would extract user info from auth context
 	if user, ok := ctx.Value("user").(string); ok {
 		return user
 	}
-	return "anonymous"
+	return anonymousUser
+}
+
+// checkAnonymous rejects anonymous callers the policy doesn't admit. mutating
+// is set for operations that create, change or delete tasks.
+func (s *TodoService) checkAnonymous(ctx context.Context, mutating bool, traceID string) error {
+	if s.getUserFromContext(ctx) != anonymousUser {
+		return nil
+	}
+	switch {
+	case s.anonymousPolicy == AnonymousDeny:
+		return errors.NewAuthenticationRequired("access tasks", traceID)
+	case s.anonymousPolicy == AnonymousReadOnly && mutating:
+		return errors.NewAuthenticationRequired("change tasks", traceID)
+	default:
+		return nil
+	}
 }
 
+// adminRole may read and change every task in its tenant, not just its own
+const adminRole = "admin"
+
 func (s *TodoService) canAccessTask(ctx context.Context, task *todopb.Task) bool {
 	// In a real implementation, check if user can access this task
 	user := s.getUserFromContext(ctx)
-	return user == task.CreatedBy || user == "admin"
+	return user == task.CreatedBy || user == adminRole
 }
 
 func (s *TodoService) canModifyTask(ctx context.Context, task *todopb.Task) bool {
 	// In a real implementation, check if user can modify this task
 	user := s.getUserFromContext(ctx)
-	return user == task.CreatedBy || user == "admin"
+	return user == task.CreatedBy || user == adminRole
 }
 
-func (s *TodoService) parseFilter(filter string) (map[string]interface{}, error) {
-	// Simple filter parser - in production, use a proper parser
-	parsed := make(map[string]interface{})
-
-	if filter == "" {
-		return parsed, nil
-	}
-
-	// Example: "status=COMPLETED AND priority=HIGH"
-	parts := strings.Split(filter, " AND ")
-	for _, part := range parts {
-		kv := strings.Split(strings.TrimSpace(part), "=")
-		if len(kv) != 2 {
-			return nil, fmt.Errorf("invalid filter expression: %s", part)
-		}
-
-		key := strings.TrimSpace(kv[0])
-		value := strings.Trim(strings.TrimSpace(kv[1]), "'\"")
-
-		// Validate filter keys
-		switch key {
-		case "status", "priority", "created_by":
-			parsed[key] = value
-		default:
-			return nil, fmt.Errorf("unknown filter field: %s", key)
-		}
-	}
+// filterSyntaxError points at the token in a filter expression that could not be parsed
+type filterSyntaxError struct {
+	token    string
+	position int
+	reason   string
+}
 
-	return parsed, nil
+func (e *filterSyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d: %q", e.reason, e.position, e.token)
 }
 
-func (s *TodoService) applyFieldMask(existing, update *todopb.Task, mask *fieldmaskpb.FieldMask) *todopb.Task {
-	result := *existing
+func (s *TodoService) parseFilter(filter string) (map[string]interface{}, error) {
+	return parseFilterExpression(filter, taskFilterFields)
+}
 
-	for _, path := range mask.Paths {
-		switch path {
-		case "title":
-			result.Title = update.Title
-		case "description":
-			result.Description = update.Description
-		case "status":
-			result.Status = update.Status
-		case "priority":
-			result.Priority = update.Priority
-		case "due_date":
-			result.DueDate = update.DueDate
-		case "tags":
-			result.Tags = update.Tags
-		}
+func (s *TodoService) applyFieldMask(existing, update *todopb.Task, mask *fieldmaskpb.FieldMask, traceID string) (*todopb.Task, error) {
+	result := proto.Clone(existing).(*todopb.Task)
+	if err := fieldmask.Apply(result, update, mask, immutableTaskFields...); err != nil {
+		return nil, errors.NewValidationFailed([]*errorspb.FieldViolation{
+			{
+				Field:       "update_mask",
+				Code:        errorspb.AppErrorCode_INVALID_VALUE.String(),
+				Description: err.Error(),
+			},
+		}, traceID)
 	}
-	return &result
+	return result, nil
 }
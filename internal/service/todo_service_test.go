@@ -0,0 +1,1012 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/fieldmask"
+	"github.com/bhatti/todo-api-errors/internal/middleware"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"github.com/bhatti/todo-api-errors/internal/validation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// headerStream captures the headers a handler sets with grpc.SetHeader
+type headerStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestUpdateTaskReportsChangedFields(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+	task, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{
+		Title:       "Write tests",
+		Description: "unit and integration",
+		Priority:    todopb.Priority_PRIORITY_LOW,
+	}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	stream := &headerStream{}
+	_, err = s.UpdateTask(grpc.NewContextWithServerTransportStream(ctx, stream), &todopb.UpdateTaskRequest{
+		Task:       &todopb.Task{Name: task.Name, Title: "Write more tests", Description: "unit and integration", Priority: todopb.Priority_PRIORITY_LOW},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title", "description", "priority"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+
+	values := stream.header.Get(taskChangesHeader)
+	if len(values) != 1 {
+		t.Fatalf("%s header = %v, want one value", taskChangesHeader, values)
+	}
+	var changes []fieldmask.Change
+	if err := json.Unmarshal([]byte(values[0]), &changes); err != nil {
+		t.Fatalf("decode %q: %v", values[0], err)
+	}
+	if len(changes) != 1 || changes[0].Field != "title" || changes[0].Old != "Write tests" || changes[0].New != "Write more tests" {
+		t.Errorf("changes = %+v, want only the title", changes)
+	}
+}
+
+func TestCreateTaskWithClientTaskID(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	task, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{TaskId: "weekly-review", Task: &todopb.Task{Title: "Weekly review"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if task.Name != "tasks/weekly-review" {
+		t.Errorf("name = %q, want tasks/weekly-review", task.Name)
+	}
+
+	_, err = s.CreateTask(ctx, &todopb.CreateTaskRequest{TaskId: "weekly-review", Task: &todopb.Task{Title: "Another review"}})
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.AlreadyExists {
+		t.Errorf("duplicate ID = %v, want AlreadyExists", err)
+	}
+
+	_, err = s.CreateTask(ctx, &todopb.CreateTaskRequest{TaskId: "Not_Valid", Task: &todopb.Task{Title: "Bad ID"}})
+	if !stderrors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_VALIDATION_FAILED ||
+		len(appErr.FieldViolations) != 1 || appErr.FieldViolations[0].Field != "task_id" {
+		t.Errorf("invalid ID = %v, want a task_id violation", err)
+	}
+
+	generated, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Generated"}})
+	if err != nil {
+		t.Fatalf("CreateTask without ID: %v", err)
+	}
+	if !strings.HasPrefix(generated.Name, "tasks/") || len(generated.Name) <= len("tasks/") {
+		t.Errorf("generated name = %q", generated.Name)
+	}
+}
+
+func TestCreateTaskAttachesValidationWarnings(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	stream := &headerStream{}
+	task, err := s.CreateTask(grpc.NewContextWithServerTransportStream(ctx, stream), &todopb.CreateTaskRequest{Task: &todopb.Task{
+		Title:   "Due in an hour",
+		DueDate: timestamppb.New(time.Now().Add(time.Hour)),
+	}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if task.Name == "" {
+		t.Error("task not created")
+	}
+
+	values := stream.header.Get(validationWarningsHeader)
+	if len(values) != 1 {
+		t.Fatalf("%s header = %v, want one value", validationWarningsHeader, values)
+	}
+	var warnings []*errorspb.FieldViolation
+	if err := json.Unmarshal([]byte(values[0]), &warnings); err != nil {
+		t.Fatalf("decode %q: %v", values[0], err)
+	}
+	if len(warnings) != 1 || warnings[0].Field != "due_date" || warnings[0].Code != errorspb.AppErrorCode_DUE_SOON.String() {
+		t.Errorf("warnings = %v, want DUE_SOON on due_date", warnings)
+	}
+}
+
+func TestRenameTag(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob := context.WithValue(context.Background(), "user", "bob")
+	alice := context.WithValue(context.Background(), "user", "alice")
+
+	create := func(ctx context.Context, title string, tags ...string) *todopb.Task {
+		t.Helper()
+		task, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: title, Tags: tags}})
+		if err != nil {
+			t.Fatalf("CreateTask(%s): %v", title, err)
+		}
+		return task
+	}
+	typo := create(bob, "Typo", "urgnet", "home")
+	both := create(bob, "Both", "urgent", "urgnet")
+	untouched := create(bob, "Untouched", "home")
+	other := create(alice, "Alice's", "urgnet")
+
+	resp, err := s.RenameTag(bob, &todopb.RenameTagRequest{From: "urgnet", To: "urgent"})
+	if err != nil {
+		t.Fatalf("RenameTag: %v", err)
+	}
+	if resp.AffectedCount != 2 {
+		t.Errorf("affected = %d, want 2", resp.AffectedCount)
+	}
+
+	for _, tc := range []struct {
+		task *todopb.Task
+		want []string
+	}{
+		{typo, []string{"urgent", "home"}},
+		{both, []string{"urgent"}},
+		{untouched, []string{"home"}},
+		{other, []string{"urgnet"}},
+	} {
+		stored, err := s.repo.GetTask(context.Background(), "", strings.TrimPrefix(tc.task.Name, "tasks/"))
+		if err != nil {
+			t.Fatalf("GetTask: %v", err)
+		}
+		if strings.Join(stored.Tags, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s tags = %v, want %v", stored.Title, stored.Tags, tc.want)
+		}
+	}
+}
+
+func TestRenameTagRejectsInvalidTarget(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	for _, req := range []*todopb.RenameTagRequest{
+		{From: "urgnet", To: "Urgent!"},
+		{From: "urgnet", To: ""},
+		{From: "urgent", To: "urgent"},
+		{From: "", To: "urgent"},
+	} {
+		_, err := s.RenameTag(ctx, req)
+		var appErr *errors.AppError
+		if !stderrors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_VALIDATION_FAILED {
+			t.Errorf("RenameTag(%q -> %q) = %v, want VALIDATION_FAILED", req.From, req.To, err)
+		}
+	}
+}
+
+func TestSuggestTagsByPrefixAndFrequency(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob := context.WithValue(context.Background(), "user", "bob")
+	alice := context.WithValue(context.Background(), "user", "alice")
+
+	for i, tags := range [][]string{
+		{"work", "weekly"},
+		{"work", "weekend"},
+		{"work", "weekly"},
+		{"home"},
+	} {
+		if _, err := s.CreateTask(bob, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: fmt.Sprintf("Task %d", i), Tags: tags}}); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+	if _, err := s.CreateTask(alice, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Alice's", Tags: []string{"webinar", "webinar-prep"}}}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	for _, tc := range []struct {
+		prefix   string
+		pageSize int32
+		want     string
+	}{
+		{"we", 0, "weekly:2,weekend:1"},
+		{"W", 0, "work:3,weekly:2,weekend:1"},
+		{"", 2, "work:3,weekly:2"},
+		{"x", 0, ""},
+	} {
+		resp, err := s.SuggestTags(bob, &todopb.SuggestTagsRequest{Prefix: tc.prefix, PageSize: tc.pageSize})
+		if err != nil {
+			t.Fatalf("SuggestTags(%q): %v", tc.prefix, err)
+		}
+		var got []string
+		for _, suggestion := range resp.Suggestions {
+			got = append(got, fmt.Sprintf("%s:%d", suggestion.Tag, suggestion.Count))
+		}
+		if strings.Join(got, ",") != tc.want {
+			t.Errorf("SuggestTags(%q, %d) = %v, want %s", tc.prefix, tc.pageSize, got, tc.want)
+		}
+	}
+}
+
+func TestGetTaskStats(t *testing.T) {
+	repo := repository.NewInMemoryRepository(true)
+	s, err := NewTodoService(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	for i, task := range []*todopb.Task{
+		{CreatedBy: "bob", Status: todopb.Status_STATUS_PENDING, Priority: todopb.Priority_PRIORITY_HIGH, DueDate: timestamppb.New(now.Add(-time.Hour))},
+		{CreatedBy: "bob", Status: todopb.Status_STATUS_IN_PROGRESS, Priority: todopb.Priority_PRIORITY_HIGH, DueDate: timestamppb.New(now.Add(48 * time.Hour))},
+		{CreatedBy: "bob", Status: todopb.Status_STATUS_PENDING, Priority: todopb.Priority_PRIORITY_LOW, DueDate: timestamppb.New(now.Add(2 * time.Hour))},
+		{CreatedBy: "bob", Status: todopb.Status_STATUS_COMPLETED, Priority: todopb.Priority_PRIORITY_LOW, DueDate: timestamppb.New(now.Add(-48 * time.Hour))},
+		{CreatedBy: "bob", Status: todopb.Status_STATUS_PENDING, Priority: todopb.Priority_PRIORITY_MEDIUM},
+		{CreatedBy: "alice", Status: todopb.Status_STATUS_PENDING, Priority: todopb.Priority_PRIORITY_LOW, DueDate: timestamppb.New(now.Add(-time.Minute))},
+	} {
+		task.Name = fmt.Sprintf("tasks/t%d", i)
+		task.Title = fmt.Sprintf("Task %d", i)
+		if err := repo.CreateTask(context.Background(), task); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		user       string
+		total      int32
+		overdue    int32
+		nextDue    time.Time
+		byStatus   string
+		byPriority string
+	}{
+		{"bob", 5, 1, now.Add(2 * time.Hour), "STATUS_PENDING:3,STATUS_IN_PROGRESS:1,STATUS_COMPLETED:1", "PRIORITY_LOW:2,PRIORITY_MEDIUM:1,PRIORITY_HIGH:2"},
+		{"admin", 6, 2, now.Add(2 * time.Hour), "STATUS_PENDING:4,STATUS_IN_PROGRESS:1,STATUS_COMPLETED:1", "PRIORITY_LOW:3,PRIORITY_MEDIUM:1,PRIORITY_HIGH:2"},
+	} {
+		stats, err := s.GetTaskStats(context.WithValue(context.Background(), "user", tc.user), &todopb.GetTaskStatsRequest{})
+		if err != nil {
+			t.Fatalf("GetTaskStats(%s): %v", tc.user, err)
+		}
+		if stats.TotalCount != tc.total || stats.OverdueCount != tc.overdue {
+			t.Errorf("%s: total/overdue = %d/%d, want %d/%d", tc.user, stats.TotalCount, stats.OverdueCount, tc.total, tc.overdue)
+		}
+		if !stats.NextDueDate.AsTime().Equal(tc.nextDue) {
+			t.Errorf("%s: next due = %v, want %v", tc.user, stats.NextDueDate.AsTime(), tc.nextDue)
+		}
+
+		var byStatus, byPriority []string
+		for _, c := range stats.ByStatus {
+			byStatus = append(byStatus, fmt.Sprintf("%s:%d", c.Status, c.Count))
+		}
+		for _, c := range stats.ByPriority {
+			byPriority = append(byPriority, fmt.Sprintf("%s:%d", c.Priority, c.Count))
+		}
+		if got := strings.Join(byStatus, ","); got != tc.byStatus {
+			t.Errorf("%s: by status = %s, want %s", tc.user, got, tc.byStatus)
+		}
+		if got := strings.Join(byPriority, ","); got != tc.byPriority {
+			t.Errorf("%s: by priority = %s, want %s", tc.user, got, tc.byPriority)
+		}
+	}
+}
+
+func TestCreateTaskEnforcesTenantAllowlist(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	noLow, err := validation.NewAllowlist(nil, []string{"PRIORITY_MEDIUM", "PRIORITY_HIGH"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetTenantAllowlists(map[string]*validation.Allowlist{"acme": noLow})
+
+	bob := context.WithValue(context.Background(), "user", "bob")
+	acme := context.WithValue(bob, "tenant", "acme")
+	lowTask := func(title string) *todopb.CreateTaskRequest {
+		return &todopb.CreateTaskRequest{Task: &todopb.Task{Title: title, Priority: todopb.Priority_PRIORITY_LOW}}
+	}
+
+	_, err = s.CreateTask(acme, lowTask("Water plants"))
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || len(appErr.FieldViolations) != 1 ||
+		appErr.FieldViolations[0].Code != errorspb.AppErrorCode_DISALLOWED_VALUE.String() {
+		t.Fatalf("CreateTask for acme = %v, want a DISALLOWED_VALUE violation", err)
+	}
+
+	// Tenants without settings, and callers without a tenant, are unrestricted
+	if _, err := s.CreateTask(context.WithValue(bob, "tenant", "globex"), lowTask("Water plants")); err != nil {
+		t.Errorf("CreateTask for globex: %v", err)
+	}
+	if _, err := s.CreateTask(bob, lowTask("Feed cat")); err != nil {
+		t.Errorf("CreateTask without a tenant: %v", err)
+	}
+}
+
+func TestUniqueTitles(t *testing.T) {
+	for _, unique := range []bool{true, false} {
+		t.Run(fmt.Sprintf("unique=%v", unique), func(t *testing.T) {
+			repo := repository.NewInMemoryRepository(unique)
+			s, err := NewTodoService(repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.SetUniqueTitles(unique)
+			ctx := context.WithValue(context.Background(), "user", "bob")
+
+			first, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Standup"}})
+			if err != nil {
+				t.Fatalf("CreateTask: %v", err)
+			}
+			second, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Standup"}})
+			var appErr *errors.AppError
+			if unique {
+				if !stderrors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_RESOURCE_CONFLICT {
+					t.Fatalf("duplicate title = %v, want RESOURCE_CONFLICT", err)
+				}
+			} else if err != nil {
+				t.Fatalf("duplicate title rejected with uniqueness off: %v", err)
+			}
+
+			_, err = s.BatchCreateTasks(ctx, &todopb.BatchCreateTasksRequest{Requests: []*todopb.CreateTaskRequest{
+				{Task: &todopb.Task{Title: "Retro"}},
+				{Task: &todopb.Task{Title: "Retro"}},
+			}})
+			if unique != (err != nil) {
+				t.Errorf("batch with a repeated title = %v, want rejected = %v", err, unique)
+			}
+
+			// The repository works with or without its title index
+			found, err := repo.GetTaskByTitle(ctx, "", "Standup")
+			if err != nil || found.Title != "Standup" {
+				t.Errorf("GetTaskByTitle = %v, %v; want a task titled Standup", found, err)
+			}
+			if unique {
+				return
+			}
+			if _, err := s.DeleteTask(ctx, &todopb.DeleteTaskRequest{Name: first.Name}); err != nil {
+				t.Fatalf("DeleteTask: %v", err)
+			}
+			if found, err := repo.GetTaskByTitle(ctx, "", "Standup"); err != nil || found.Name != second.Name {
+				t.Errorf("after delete GetTaskByTitle = %v, %v; want %s", found, err, second.Name)
+			}
+		})
+	}
+}
+
+func TestCreateTaskConflictPointsAtTitle(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	existing, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Quarterly report"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	_, err = s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Quarterly report"}})
+
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_RESOURCE_CONFLICT {
+		t.Fatalf("err = %v, want RESOURCE_CONFLICT", err)
+	}
+
+	// Rendered over HTTP, the conflict carries the same errors[] array as a validation failure
+	rec := httptest.NewRecorder()
+	middleware.CustomHTTPError(ctx, nil, nil, rec, httptest.NewRequest(http.MethodPost, "/v1/tasks", nil), appErr.ToGRPCStatus().Err())
+	if rec.Code != http.StatusConflict {
+		t.Errorf("HTTP status = %d, want 409", rec.Code)
+	}
+	var body struct {
+		Errors []struct {
+			Field   string `json:"field"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if len(body.Errors) != 1 {
+		t.Fatalf("errors = %+v, want one title violation", body.Errors)
+	}
+	v := body.Errors[0]
+	if v.Field != "title" || v.Code != errorspb.AppErrorCode_DUPLICATE_TITLE.String() || !strings.Contains(v.Message, existing.Name) {
+		t.Errorf("violation = %+v, want DUPLICATE_TITLE on title naming %s", v, existing.Name)
+	}
+}
+
+func TestCreateTaskTellsIDAndTitleConflictsApart(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	if _, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{TaskId: "weekly-review", Task: &todopb.Task{Title: "Weekly review"}}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		req       *todopb.CreateTaskRequest
+		wantField string
+		wantMsg   string
+	}{
+		{"ID collision", &todopb.CreateTaskRequest{TaskId: "weekly-review", Task: &todopb.Task{Title: "Another review"}}, "task_id", "Task ID 'weekly-review' is already in use"},
+		{"title collision", &todopb.CreateTaskRequest{TaskId: "other-review", Task: &todopb.Task{Title: "Weekly review"}}, "title", "A task with this title already exists"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.CreateTask(ctx, tc.req)
+			var appErr *errors.AppError
+			if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.AlreadyExists {
+				t.Fatalf("err = %v, want AlreadyExists", err)
+			}
+			if !strings.Contains(appErr.Detail, tc.wantMsg) {
+				t.Errorf("detail = %q, want it to contain %q", appErr.Detail, tc.wantMsg)
+			}
+			if len(appErr.FieldViolations) != 1 || appErr.FieldViolations[0].Field != tc.wantField {
+				t.Errorf("violations = %v, want one on %s", appErr.FieldViolations, tc.wantField)
+			}
+		})
+	}
+}
+
+func TestUpdateTaskTitleConflict(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	a, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Task A"}})
+	if err != nil {
+		t.Fatalf("CreateTask A: %v", err)
+	}
+	b, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Task B"}})
+	if err != nil {
+		t.Fatalf("CreateTask B: %v", err)
+	}
+
+	_, err = s.UpdateTask(ctx, &todopb.UpdateTaskRequest{
+		Task:       &todopb.Task{Name: b.Name, Title: "Task A"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.AlreadyExists {
+		t.Fatalf("err = %v, want AlreadyExists (409)", err)
+	}
+	if len(appErr.FieldViolations) != 1 || appErr.FieldViolations[0].Field != "title" ||
+		!strings.Contains(appErr.FieldViolations[0].Description, a.Name) {
+		t.Errorf("violations = %v, want a title violation naming %s", appErr.FieldViolations, a.Name)
+	}
+}
+
+func TestBatchGetTasksReportsStatusPerName(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob := context.WithValue(context.Background(), "user", "bob")
+	alice := context.WithValue(context.Background(), "user", "alice")
+
+	own, err := s.CreateTask(bob, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Bob's task"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	other, err := s.CreateTask(alice, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Alice's task"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	resp, err := s.BatchGetTasks(bob, &todopb.BatchGetTasksRequest{Names: []string{own.Name, "tasks/missing", other.Name}})
+	if err != nil {
+		t.Fatalf("BatchGetTasks: %v", err)
+	}
+
+	want := []todopb.BatchGetStatus{
+		todopb.BatchGetStatus_BATCH_GET_STATUS_FOUND,
+		todopb.BatchGetStatus_BATCH_GET_STATUS_NOT_FOUND,
+		todopb.BatchGetStatus_BATCH_GET_STATUS_FORBIDDEN,
+	}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("results = %v, want %d", resp.Results, len(want))
+	}
+	for i, result := range resp.Results {
+		if result.Status != want[i] {
+			t.Errorf("%s: status = %v, want %v", result.Name, result.Status, want[i])
+		}
+		if (result.Task != nil) != (want[i] == todopb.BatchGetStatus_BATCH_GET_STATUS_FOUND) {
+			t.Errorf("%s: task = %v, want it only when found", result.Name, result.Task)
+		}
+	}
+}
+
+func TestReassignUserTasks(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := context.WithValue(context.Background(), "user", "alice")
+	bob := context.WithValue(context.Background(), "user", "bob")
+	carol := context.WithValue(context.Background(), "user", "carol")
+	admin := context.WithValue(context.Background(), "user", adminRole)
+
+	var aliceTasks []string
+	for i := 0; i < 3; i++ {
+		task, err := s.CreateTask(alice, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: fmt.Sprintf("Alice %d", i)}})
+		if err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+		aliceTasks = append(aliceTasks, task.Name)
+	}
+	carolTask, err := s.CreateTask(carol, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Carol's task"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	req := &todopb.ReassignUserTasksRequest{FromUser: "alice", ToUser: "bob"}
+
+	_, err = s.ReassignUserTasks(bob, req)
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.PermissionDenied {
+		t.Fatalf("non-admin err = %v, want PermissionDenied", err)
+	}
+
+	resp, err := s.ReassignUserTasks(admin, req)
+	if err != nil {
+		t.Fatalf("ReassignUserTasks: %v", err)
+	}
+	if resp.MovedCount != 3 {
+		t.Errorf("moved = %d, want 3", resp.MovedCount)
+	}
+	for _, name := range aliceTasks {
+		task, err := s.GetTask(bob, &todopb.GetTaskRequest{Name: name})
+		if err != nil || task.CreatedBy != "bob" {
+			t.Errorf("%s after reassignment = %v, %v; want it owned by bob", name, task, err)
+		}
+	}
+	if task, err := s.GetTask(carol, &todopb.GetTaskRequest{Name: carolTask.Name}); err != nil || task.CreatedBy != "carol" {
+		t.Errorf("carol's task = %v, %v; want it untouched", task, err)
+	}
+
+	// Running it again moves nothing
+	resp, err = s.ReassignUserTasks(admin, req)
+	if err != nil || resp.MovedCount != 0 {
+		t.Errorf("second run = %v, %v; want 0 moved", resp, err)
+	}
+}
+
+func TestTitleConflictSuggestsFreeTitle(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	for _, title := range []string{"Report", "Report (2)", "Report (3)"} {
+		if _, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: title}}); err != nil {
+			t.Fatalf("CreateTask %q: %v", title, err)
+		}
+	}
+
+	// Each suggestion is taken before the next conflict, so counting goes on
+	for _, tc := range []struct{ title, want string }{
+		{"Report", "Report (4)"},
+		{"Report (3)", "Report (5)"},
+	} {
+		title, want := tc.title, tc.want
+		_, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: title}})
+		var appErr *errors.AppError
+		if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.AlreadyExists {
+			t.Fatalf("%q: err = %v, want AlreadyExists", title, err)
+		}
+		ext, ok := appErr.Extensions["suggestedTitle"]
+		if !ok {
+			t.Fatalf("%q: no suggestedTitle extension in %v", title, appErr.Extensions)
+		}
+		var hint structpb.Struct
+		if err := ext.UnmarshalTo(&hint); err != nil {
+			t.Fatal(err)
+		}
+		suggested := hint.Fields["title"].GetStringValue()
+		if suggested != want {
+			t.Errorf("%q: suggested %q, want %q", title, suggested, want)
+		}
+		if _, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: suggested}}); err != nil {
+			t.Errorf("creating the suggested title %q: %v", suggested, err)
+		}
+	}
+}
+
+func TestListTasksAppliesDefaultOrder(t *testing.T) {
+	repo := repository.NewInMemoryRepository(true)
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	var seed []*todopb.Task
+	for i, title := range []string{"Alpha", "Charlie", "Bravo"} {
+		seed = append(seed, &todopb.Task{
+			Name:       fmt.Sprintf("tasks/t%d", i),
+			Title:      title,
+			CreatedBy:  "bob",
+			CreateTime: timestamppb.New(base.Add(time.Duration(i) * time.Hour)),
+		})
+	}
+	if err := repo.Seed(context.Background(), seed); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+	s, err := NewTodoService(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	titles := func(orderBy string) []string {
+		resp, err := s.ListTasks(ctx, &todopb.ListTasksRequest{OrderBy: orderBy})
+		if err != nil {
+			t.Fatalf("ListTasks(%q): %v", orderBy, err)
+		}
+		var got []string
+		for _, task := range resp.Tasks {
+			got = append(got, task.Title)
+		}
+		return got
+	}
+
+	if err := s.SetDefaultOrderBy("-create_time"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(titles(""), ","); got != "Bravo,Charlie,Alpha" {
+		t.Errorf("default order = %s, want newest first", got)
+	}
+	if got := strings.Join(titles("title"), ","); got != "Alpha,Bravo,Charlie" {
+		t.Errorf("explicit title order = %s, want it to override the default", got)
+	}
+
+	if err := s.SetDefaultOrderBy("newest"); err == nil {
+		t.Error("SetDefaultOrderBy(\"newest\") = nil, want an error")
+	}
+}
+
+// countSpyRepository records how often CountTasks is called
+type countSpyRepository struct {
+	repository.TodoRepository
+	counts int
+}
+
+func (r *countSpyRepository) CountTasks(ctx context.Context, filter map[string]interface{}, tenantID, userID string) (int, error) {
+	r.counts++
+	return r.TodoRepository.CountTasks(ctx, filter, tenantID, userID)
+}
+
+func TestListTasksSkipTotal(t *testing.T) {
+	repo := &countSpyRepository{TodoRepository: repository.NewInMemoryRepository(true)}
+	s, err := NewTodoService(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+	for _, title := range []string{"One", "Two"} {
+		if _, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: title}}); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+
+	resp, err := s.ListTasks(ctx, &todopb.ListTasksRequest{SkipTotal: true})
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if repo.counts != 0 || resp.TotalSize != -1 || len(resp.Tasks) != 2 {
+		t.Errorf("skip_total: CountTasks called %d times, total_size %d, %d tasks; want 0, -1, 2", repo.counts, resp.TotalSize, len(resp.Tasks))
+	}
+
+	// The count is still computed by default
+	resp, err = s.ListTasks(ctx, &todopb.ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if repo.counts != 1 || resp.TotalSize != 2 {
+		t.Errorf("default: CountTasks called %d times, total_size %d; want 1, 2", repo.counts, resp.TotalSize)
+	}
+}
+
+func TestListTasksRejectsPagesOverResponseBudget(t *testing.T) {
+	repo := repository.NewInMemoryRepository(true)
+	s, err := NewTodoService(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetMaxResponseBytes(2500)
+	for i := 0; i < 5; i++ {
+		task := &todopb.Task{
+			Name:        fmt.Sprintf("tasks/t%d", i),
+			Title:       fmt.Sprintf("Imported %d", i),
+			Description: strings.Repeat("x", 1000),
+			CreatedBy:   "bob",
+		}
+		if err := repo.CreateTask(context.Background(), task); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	_, err = s.ListTasks(ctx, &todopb.ListTasksRequest{PageSize: 5})
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_RESPONSE_TOO_LARGE || appErr.GRPCCode != codes.ResourceExhausted {
+		t.Fatalf("err = %v, want RESPONSE_TOO_LARGE", err)
+	}
+	if !strings.Contains(appErr.Detail, "page_size=2") {
+		t.Errorf("detail %q should suggest page_size=2", appErr.Detail)
+	}
+	var hint structpb.Struct
+	if ext, ok := appErr.Extensions["responseSize"]; !ok || ext.UnmarshalTo(&hint) != nil {
+		t.Fatal("responseSize extension missing")
+	}
+	if got := hint.Fields["suggestedPageSize"].GetNumberValue(); got != 2 {
+		t.Errorf("suggestedPageSize = %v, want 2", got)
+	}
+
+	rec := httptest.NewRecorder()
+	middleware.CustomHTTPError(ctx, nil, nil, rec, httptest.NewRequest(http.MethodGet, "/v1/tasks", nil), appErr.ToGRPCStatus().Err())
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("HTTP status = %d, want 413", rec.Code)
+	}
+
+	// The suggested page size fits the budget
+	resp, err := s.ListTasks(ctx, &todopb.ListTasksRequest{PageSize: 2})
+	if err != nil || len(resp.Tasks) != 2 {
+		t.Errorf("ListTasks with the suggested page size = %v, %v", resp, err)
+	}
+}
+
+// unreachableRepository fails every lookup with a connection error
+type unreachableRepository struct {
+	repository.TodoRepository
+}
+
+func (unreachableRepository) GetTask(context.Context, string, string) (*todopb.Task, error) {
+	return nil, repository.ErrConnection
+}
+
+func TestServiceUnavailableNamesDependency(t *testing.T) {
+	s, err := NewTodoService(unreachableRepository{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	_, err = s.GetTask(ctx, &todopb.GetTaskRequest{Name: "tasks/1"})
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_SERVICE_UNAVAILABLE {
+		t.Fatalf("err = %v, want SERVICE_UNAVAILABLE", err)
+	}
+
+	rec := httptest.NewRecorder()
+	middleware.CustomHTTPError(ctx, nil, nil, rec, httptest.NewRequest(http.MethodGet, "/v1/tasks/1", nil), appErr.ToGRPCStatus().Err())
+	var body struct {
+		Extensions struct {
+			Dependency struct {
+				Value struct {
+					Name string `json:"name"`
+				} `json:"value"`
+			} `json:"dependency"`
+		} `json:"extensions"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Extensions.Dependency.Value.Name != "database" {
+		t.Errorf("dependency = %q, want database; body %s", body.Extensions.Dependency.Value.Name, rec.Body.String())
+	}
+
+	// Without a known dependency no extension is attached
+	if ext := errors.NewServiceUnavailable("", "down", 0, "").Extensions; ext != nil {
+		t.Errorf("extensions = %v, want none", ext)
+	}
+}
+
+func TestUnreachableDatabaseAsksClientsToRetry(t *testing.T) {
+	s, err := NewTodoService(unreachableRepository{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+
+	_, err = s.GetTask(ctx, &todopb.GetTaskRequest{Name: "tasks/1"})
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.RetryAfter != databaseRetryAfter {
+		t.Fatalf("err = %v, want a %v retry hint", err, databaseRetryAfter)
+	}
+
+	rec := httptest.NewRecorder()
+	middleware.CustomHTTPError(ctx, nil, nil, rec, httptest.NewRequest(http.MethodGet, "/v1/tasks/1", nil), appErr.ToGRPCStatus().Err())
+	if got := rec.Header().Get("Retry-After"); got != "5" {
+		t.Errorf("Retry-After = %q, want 5", got)
+	}
+}
+
+func TestPermissionDeniedNamesRequiredRole(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := context.WithValue(context.Background(), "user", "alice")
+	bob := context.WithValue(context.Background(), "user", "bob")
+
+	task, err := s.CreateTask(alice, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Alice's task"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	_, err = s.GetTask(bob, &todopb.GetTaskRequest{Name: task.Name})
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.PermissionDenied {
+		t.Fatalf("err = %v, want PermissionDenied", err)
+	}
+
+	rec := httptest.NewRecorder()
+	middleware.CustomHTTPError(bob, nil, nil, rec, httptest.NewRequest(http.MethodGet, "/v1/"+task.Name, nil), appErr.ToGRPCStatus().Err())
+	var body struct {
+		Extensions struct {
+			RequiredRole struct {
+				Value struct {
+					Name string `json:"name"`
+				} `json:"value"`
+			} `json:"requiredRole"`
+		} `json:"extensions"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Extensions.RequiredRole.Value.Name != adminRole {
+		t.Errorf("required role = %q, want %q; body %s", body.Extensions.RequiredRole.Value.Name, adminRole, rec.Body.String())
+	}
+
+	// Without a required role no extension is attached
+	if ext := errors.NewPermissionDenied("task", "read", "", "").Extensions; ext != nil {
+		t.Errorf("extensions = %v, want none", ext)
+	}
+}
+
+func TestCreateTaskAppliesTenantDefaults(t *testing.T) {
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetTenantDefaults(map[string]TaskDefaults{"acme": {Priority: todopb.Priority_PRIORITY_HIGH}})
+	bob := context.WithValue(context.Background(), "user", "bob")
+
+	for _, tc := range []struct {
+		name         string
+		tenant       string
+		priority     todopb.Priority
+		wantPriority todopb.Priority
+	}{
+		{"tenant default", "acme", todopb.Priority_PRIORITY_UNSPECIFIED, todopb.Priority_PRIORITY_HIGH},
+		{"client value wins", "acme", todopb.Priority_PRIORITY_LOW, todopb.Priority_PRIORITY_LOW},
+		{"fallback for other tenants", "globex", todopb.Priority_PRIORITY_UNSPECIFIED, todopb.Priority_PRIORITY_MEDIUM},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.WithValue(bob, "tenant", tc.tenant)
+			task, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: tc.name, Priority: tc.priority}})
+			if err != nil {
+				t.Fatalf("CreateTask: %v", err)
+			}
+			if task.Priority != tc.wantPriority || task.Status != todopb.Status_STATUS_PENDING {
+				t.Errorf("priority/status = %v/%v, want %v/STATUS_PENDING", task.Priority, task.Status, tc.wantPriority)
+			}
+		})
+	}
+}
+
+// newTestTodoService returns a service over an empty in-memory store
+func newTestTodoService(t *testing.T) *TodoService {
+	t.Helper()
+	s, err := NewTodoService(repository.NewInMemoryRepository(false))
+	if err != nil {
+		t.Fatalf("NewTodoService: %v", err)
+	}
+	return s
+}
+
+func TestListTasksRejectsInvalidPageToken(t *testing.T) {
+	s := newTestTodoService(t)
+	ctx := asUser("bob")
+	for _, title := range []string{"a", "b", "c"} {
+		if _, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: title}}); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+	page, err := s.ListTasks(ctx, &todopb.ListTasksRequest{PageSize: 1, OrderBy: "title"})
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+
+	for name, req := range map[string]*todopb.ListTasksRequest{
+		"tampered":    {PageSize: 1, OrderBy: "title", PageToken: "x" + page.NextPageToken},
+		"other order": {PageSize: 1, OrderBy: "-create_time", PageToken: page.NextPageToken},
+	} {
+		_, err := s.ListTasks(ctx, req)
+		var appErr *errors.AppError
+		if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.InvalidArgument {
+			t.Errorf("%s: ListTasks = %v, want INVALID_ARGUMENT", name, err)
+			continue
+		}
+		if len(appErr.FieldViolations) != 1 || appErr.FieldViolations[0].Field != "page_token" {
+			t.Errorf("%s: violations = %v, want one on page_token", name, appErr.FieldViolations)
+		}
+	}
+}
+
+func TestListTasksReturnsPromptlyWhenCancelled(t *testing.T) {
+	store := repository.NewInMemoryRepository(false)
+	tasks := make([]*todopb.Task, 5000)
+	for i := range tasks {
+		tasks[i] = &todopb.Task{Name: fmt.Sprintf("tasks/task-%05d", i), Title: fmt.Sprintf("task %d", i), CreatedBy: "bob"}
+	}
+	if err := store.Seed(context.Background(), tasks); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+	s, err := NewTodoService(store)
+	if err != nil {
+		t.Fatalf("NewTodoService: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(asUser("bob"))
+	cancel()
+	start := time.Now()
+	_, err = s.ListTasks(ctx, &todopb.ListTasksRequest{})
+	if code := errorCode(err); code != codes.Canceled {
+		t.Errorf("ListTasks on a cancelled context = %v, want CANCELED", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ListTasks took %v after cancellation", elapsed)
+	}
+}
+
+func TestHandleRepositoryErrorMatchesWrappedContextErrors(t *testing.T) {
+	s := newTestTodoService(t)
+
+	err := s.handleRepositoryError(fmt.Errorf("list tasks: %w", context.DeadlineExceeded), "trace-1")
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.AppCode != errorspb.AppErrorCode_TIMEOUT {
+		t.Errorf("wrapped deadline = %v, want TIMEOUT", err)
+	}
+
+	err = s.handleRepositoryError(fmt.Errorf("list tasks: %w", context.Canceled), "trace-1")
+	if !stderrors.As(err, &appErr) || appErr.GRPCCode != codes.Canceled || appErr.AppCode != errorspb.AppErrorCode_CANCELED {
+		t.Errorf("wrapped cancellation = %v, want a CANCELED AppError", err)
+	}
+	if appErr.TraceID != "trace-1" {
+		t.Errorf("trace ID = %q, want trace-1", appErr.TraceID)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownToken is returned when a token was never issued by the tokenizer
+var ErrUnknownToken = errors.New("unknown token")
+
+const tokenPrefix = "tok_"
+
+// Tokenizer swaps sensitive values for opaque tokens so the raw values never
+// live in primary storage. Tokens keep the last 4 characters of the original
+// value in the clear so they can still be displayed and searched.
+type Tokenizer interface {
+	Tokenize(ctx context.Context, value string) (string, error)
+	Detokenize(ctx context.Context, token string) (string, error)
+
+	// Delete removes the raw value behind a token, e.g. when its owner's data
+	// is erased. Deleting an unknown token is not an error.
+	Delete(ctx context.Context, token string) error
+}
+
+// InMemoryTokenizer is a simple vault that keeps token mappings in memory
+type InMemoryTokenizer struct {
+	mu     sync.RWMutex
+	values map[string]string // token -> raw value
+}
+
+// NewInMemoryTokenizer creates a new in-memory tokenizer
+func NewInMemoryTokenizer() *InMemoryTokenizer {
+	return &InMemoryTokenizer{
+		values: make(map[string]string),
+	}
+}
+
+func (t *InMemoryTokenizer) Tokenize(_ context.Context, value string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	token := fmt.Sprintf("%s%s_%s", tokenPrefix, strings.ReplaceAll(uuid.New().String(), "-", ""), lastN(value, 4))
+	t.values[token] = value
+
+	return token, nil
+}
+
+func (t *InMemoryTokenizer) Detokenize(_ context.Context, token string) (string, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	value, exists := t.values[token]
+	if !exists {
+		return "", ErrUnknownToken
+	}
+
+	return value, nil
+}
+
+func (t *InMemoryTokenizer) Delete(_ context.Context, token string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.values, token)
+	return nil
+}
+
+func isToken(value string) bool {
+	return strings.HasPrefix(value, tokenPrefix)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"sync"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Operations that can be undone
+const (
+	operationCreate = "create"
+	operationUpdate = "update"
+	operationDelete = "delete"
+)
+
+// taskOperation captures enough state to reverse a single task change. Before
+// is nil for a create and after is nil for a delete.
+type taskOperation struct {
+	kind   string
+	before *todopb.Task
+	after  *todopb.Task
+}
+
+// undoHistory keeps only the most recent operation for each caller
+type undoHistory struct {
+	mu   sync.Mutex
+	last map[string]*taskOperation
+}
+
+func newUndoHistory() *undoHistory {
+	return &undoHistory{
+		last: make(map[string]*taskOperation),
+	}
+}
+
+// Record replaces the user's last operation. Tasks are cloned so later changes
+// to the caller's copies don't alter the history.
+func (h *undoHistory) Record(user, kind string, before, after *todopb.Task) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.last[user] = &taskOperation{
+		kind:   kind,
+		before: cloneTask(before),
+		after:  cloneTask(after),
+	}
+}
+
+// Last returns the user's most recent operation, if any
+func (h *undoHistory) Last(user string) (*taskOperation, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	op, ok := h.last[user]
+	return op, ok
+}
+
+// Clear forgets the user's last operation once it has been undone
+func (h *undoHistory) Clear(user string, op *taskOperation) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Another request may have recorded a newer operation in the meantime
+	if h.last[user] == op {
+		delete(h.last, user)
+	}
+}
+
+func cloneTask(task *todopb.Task) *todopb.Task {
+	if task == nil {
+		return nil
+	}
+	return proto.Clone(task).(*todopb.Task)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/errors"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// newUndoTestService returns a service and a context for user bob
+func newUndoTestService(t *testing.T) (*TodoService, context.Context) {
+	t.Helper()
+	s, err := NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, context.WithValue(context.Background(), "user", "bob")
+}
+
+func storedTask(t *testing.T, s *TodoService, name string) (*todopb.Task, bool) {
+	t.Helper()
+	task, err := s.repo.GetTask(context.Background(), "", strings.TrimPrefix(name, "tasks/"))
+	if repository.IsNotFound(err) {
+		return nil, false
+	}
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	return task, true
+}
+
+func wantAppCode(t *testing.T, err error, code errorspb.AppErrorCode) {
+	t.Helper()
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.AppCode != code {
+		t.Errorf("err = %v, want %v", err, code)
+	}
+}
+
+func TestUndoCreateDeletesTheTask(t *testing.T) {
+	s, ctx := newUndoTestService(t)
+	task, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Undo me"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	resp, err := s.UndoLastOperation(ctx, &todopb.UndoLastOperationRequest{})
+	if err != nil {
+		t.Fatalf("UndoLastOperation: %v", err)
+	}
+	if resp.Operation != operationCreate {
+		t.Errorf("operation = %q, want create", resp.Operation)
+	}
+	if _, ok := storedTask(t, s, task.Name); ok {
+		t.Error("created task still stored after undo")
+	}
+
+	// Only the last operation is kept
+	_, err = s.UndoLastOperation(ctx, &todopb.UndoLastOperationRequest{})
+	wantAppCode(t, err, errorspb.AppErrorCode_RESOURCE_NOT_FOUND)
+}
+
+func TestUndoUpdateRestoresPreviousVersion(t *testing.T) {
+	s, ctx := newUndoTestService(t)
+	task, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Original"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	before, _ := storedTask(t, s, task.Name)
+
+	if _, err := s.UpdateTask(ctx, &todopb.UpdateTaskRequest{
+		Task:       &todopb.Task{Name: task.Name, Title: "Changed"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	}); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+
+	resp, err := s.UndoLastOperation(ctx, &todopb.UndoLastOperationRequest{})
+	if err != nil {
+		t.Fatalf("UndoLastOperation: %v", err)
+	}
+	if resp.Operation != operationUpdate {
+		t.Errorf("operation = %q, want update", resp.Operation)
+	}
+	if after, _ := storedTask(t, s, task.Name); !proto.Equal(after, before) {
+		t.Errorf("stored task = %v, want %v", after, before)
+	}
+}
+
+func TestUndoDeleteRecreatesTheTask(t *testing.T) {
+	s, ctx := newUndoTestService(t)
+	task, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Keep me"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	before, _ := storedTask(t, s, task.Name)
+	if _, err := s.DeleteTask(ctx, &todopb.DeleteTaskRequest{Name: task.Name}); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+
+	resp, err := s.UndoLastOperation(ctx, &todopb.UndoLastOperationRequest{})
+	if err != nil {
+		t.Fatalf("UndoLastOperation: %v", err)
+	}
+	if resp.Operation != operationDelete {
+		t.Errorf("operation = %q, want delete", resp.Operation)
+	}
+	if after, ok := storedTask(t, s, task.Name); !ok || !proto.Equal(after, before) {
+		t.Errorf("stored task = %v, want %v", after, before)
+	}
+}
+
+func TestUndoRefusesToOverwriteLaterChanges(t *testing.T) {
+	s, ctx := newUndoTestService(t)
+	task, err := s.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "Shared"}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	// An admin changes the task after bob created it
+	admin := context.WithValue(context.Background(), "user", "admin")
+	if _, err := s.UpdateTask(admin, &todopb.UpdateTaskRequest{
+		Task:       &todopb.Task{Name: task.Name, Title: "Edited by admin"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	}); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+
+	_, err = s.UndoLastOperation(ctx, &todopb.UndoLastOperationRequest{})
+	wantAppCode(t, err, errorspb.AppErrorCode_RESOURCE_CONFLICT)
+	if _, ok := storedTask(t, s, task.Name); !ok {
+		t.Error("task deleted despite the later change")
+	}
+}
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"net/mail"
+	"regexp"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+)
+
+// e164Pattern matches E.164 numbers: a leading +, no trunk 0 and at most 15 digits
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// ValidateAccount checks the format of an account's contact details. Empty
+// fields are allowed; only values that are present are checked.
+func ValidateAccount(account *pii.Account, traceID string) error {
+	var violations []*errorspb.FieldViolation
+	violations = appendEmailViolation(violations, "account.email", account.Email)
+	violations = appendEmailViolation(violations, "account.personal_email", account.PersonalEmail)
+	violations = appendPhoneViolation(violations, "account.phone", account.Phone)
+	violations = appendPhoneViolation(violations, "account.mobile_phone", account.MobilePhone)
+
+	if len(violations) > 0 {
+		return apperrors.NewValidationFailed(violations, traceID)
+	}
+	return nil
+}
+
+// ValidateAccountSearch rejects search terms that could never match a stored
+// account because they aren't a valid email or phone number
+func ValidateAccountSearch(req *pii.SearchAccountsRequest, traceID string) error {
+	var violations []*errorspb.FieldViolation
+	violations = appendEmailViolation(violations, "email", req.Email)
+	violations = appendPhoneViolation(violations, "phone", req.Phone)
+
+	if len(violations) > 0 {
+		return apperrors.NewValidationFailed(violations, traceID)
+	}
+	return nil
+}
+
+// appendEmailViolation and appendPhoneViolation never echo the value in the
+// description: emails and phone numbers are PII, and violations reach clients
+// and logs.
+func appendEmailViolation(violations []*errorspb.FieldViolation, field, email string) []*errorspb.FieldViolation {
+	if email == "" || isValidEmail(email) {
+		return violations
+	}
+	return append(violations, &errorspb.FieldViolation{
+		Field:       field,
+		Code:        errorspb.AppErrorCode_INVALID_FORMAT.String(),
+		Description: "Must be a valid email address",
+	})
+}
+
+func appendPhoneViolation(violations []*errorspb.FieldViolation, field, phone string) []*errorspb.FieldViolation {
+	if phone == "" || e164Pattern.MatchString(phone) {
+		return violations
+	}
+	return append(violations, &errorspb.FieldViolation{
+		Field:       field,
+		Code:        errorspb.AppErrorCode_INVALID_FORMAT.String(),
+		Description: "Must be an E.164 phone number, e.g. +14155552671",
+	})
+}
+
+// isValidEmail accepts a bare RFC 5322 address. Display names ("Jo <jo@x.io>")
+// are rejected since the field holds the address alone.
+func isValidEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
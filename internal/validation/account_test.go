@@ -0,0 +1,91 @@
+package validation
+
+import (
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+)
+
+func TestValidateAccountAcceptsWellFormedContacts(t *testing.T) {
+	account := &pii.Account{
+		Email:         "jo@example.com",
+		PersonalEmail: "jo.personal@example.org",
+		Phone:         "+14155552671",
+		MobilePhone:   "+447911123456",
+	}
+	if err := ValidateAccount(account, ""); err != nil {
+		t.Errorf("ValidateAccount = %v, want nil", err)
+	}
+	if err := ValidateAccount(&pii.Account{}, ""); err != nil {
+		t.Errorf("ValidateAccount(empty) = %v, want nil", err)
+	}
+}
+
+func TestValidateAccountRejectsMalformedEmails(t *testing.T) {
+	for _, email := range []string{
+		"jo.secret",
+		"jo.secret@",
+		"@example.com",
+		"jo secret@example.com",
+		"Jo Secret <jo.secret@example.com>",
+	} {
+		t.Run(email, func(t *testing.T) {
+			fv := singleViolation(t, ValidateAccount(&pii.Account{Email: email}, ""))
+			if fv.Field != "account.email" || fv.Code != errorspb.AppErrorCode_INVALID_FORMAT.String() {
+				t.Errorf("violation = %s/%s, want account.email/INVALID_FORMAT", fv.Field, fv.Code)
+			}
+			assertNoValue(t, fv, email)
+		})
+	}
+}
+
+func TestValidateAccountRejectsNonE164Phones(t *testing.T) {
+	for _, phone := range []string{
+		"2025550143",
+		"(415) 555-2671",
+		"+1 415 555 2671",
+		"+04155552671",
+		"+1234567890123456",
+	} {
+		t.Run(phone, func(t *testing.T) {
+			fv := singleViolation(t, ValidateAccount(&pii.Account{MobilePhone: phone}, ""))
+			if fv.Field != "account.mobile_phone" || fv.Code != errorspb.AppErrorCode_INVALID_FORMAT.String() {
+				t.Errorf("violation = %s/%s, want account.mobile_phone/INVALID_FORMAT", fv.Field, fv.Code)
+			}
+			assertNoValue(t, fv, phone)
+		})
+	}
+}
+
+func TestValidateAccountSearchKeepsTermsOutOfErrors(t *testing.T) {
+	err := ValidateAccountSearch(&pii.SearchAccountsRequest{Email: "jo.secret@", Phone: "555-2671"}, "")
+	var appErr *apperrors.AppError
+	if !stderrors.As(err, &appErr) || len(appErr.FieldViolations) != 2 {
+		t.Fatalf("err = %v, want two violations", err)
+	}
+	for _, fv := range appErr.FieldViolations {
+		assertNoValue(t, fv, "jo.secret")
+		assertNoValue(t, fv, "555-2671")
+	}
+}
+
+func singleViolation(t *testing.T, err error) *errorspb.FieldViolation {
+	t.Helper()
+	var appErr *apperrors.AppError
+	if !stderrors.As(err, &appErr) || len(appErr.FieldViolations) != 1 {
+		t.Fatalf("err = %v, want one field violation", err)
+	}
+	return appErr.FieldViolations[0]
+}
+
+// assertNoValue checks the PII value appears nowhere in the violation
+func assertNoValue(t *testing.T, fv *errorspb.FieldViolation, value string) {
+	t.Helper()
+	if strings.Contains(fv.Description, value) || strings.Contains(fv.RejectedValue, value) {
+		t.Errorf("violation %+v leaks %q", fv, value)
+	}
+}
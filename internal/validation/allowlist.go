@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"fmt"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+)
+
+// Allowlist restricts which statuses and priorities a tenant accepts. An empty
+// list leaves that field unrestricted.
+type Allowlist struct {
+	Statuses   map[todopb.Status]bool
+	Priorities map[todopb.Priority]bool
+}
+
+// NewAllowlist builds an allowlist from enum names such as "STATUS_PENDING"
+// or "PRIORITY_HIGH"
+func NewAllowlist(statuses, priorities []string) (*Allowlist, error) {
+	allowlist := &Allowlist{
+		Statuses:   make(map[todopb.Status]bool),
+		Priorities: make(map[todopb.Priority]bool),
+	}
+	for _, name := range statuses {
+		value, ok := todopb.Status_value[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown status %q", name)
+		}
+		allowlist.Statuses[todopb.Status(value)] = true
+	}
+	for _, name := range priorities {
+		value, ok := todopb.Priority_value[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown priority %q", name)
+		}
+		allowlist.Priorities[todopb.Priority(value)] = true
+	}
+	return allowlist, nil
+}
+
+// violations reports the task's status and priority if the allowlist excludes
+// them. Unspecified values are left to the server defaults.
+func (a *Allowlist) violations(task *todopb.Task) []*errorspb.FieldViolation {
+	if a == nil {
+		return nil
+	}
+
+	var violations []*errorspb.FieldViolation
+	if len(a.Statuses) > 0 && task.Status != todopb.Status_STATUS_UNSPECIFIED && !a.Statuses[task.Status] {
+		violations = append(violations, &errorspb.FieldViolation{
+			Field:       "status",
+			Code:        errorspb.AppErrorCode_DISALLOWED_VALUE.String(),
+			Description: fmt.Sprintf("Status %s is not allowed for this tenant", task.Status),
+		})
+	}
+	if len(a.Priorities) > 0 && task.Priority != todopb.Priority_PRIORITY_UNSPECIFIED && !a.Priorities[task.Priority] {
+		violations = append(violations, &errorspb.FieldViolation{
+			Field:       "priority",
+			Code:        errorspb.AppErrorCode_DISALLOWED_VALUE.String(),
+			Description: fmt.Sprintf("Priority %s is not allowed for this tenant", task.Priority),
+		})
+	}
+	return violations
+}
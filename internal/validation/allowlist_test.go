@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+)
+
+func TestAllowlistRejectsDisallowedPriority(t *testing.T) {
+	allowlist, err := NewAllowlist(nil, []string{"PRIORITY_MEDIUM", "PRIORITY_HIGH"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		priority todopb.Priority
+		rejected bool
+	}{
+		{"allowed", todopb.Priority_PRIORITY_HIGH, false},
+		{"unspecified uses the default", todopb.Priority_PRIORITY_UNSPECIFIED, false},
+		{"disallowed", todopb.Priority_PRIORITY_LOW, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			task := &todopb.Task{Title: "Plan sprint", Priority: tc.priority, Status: todopb.Status_STATUS_CANCELLED}
+			_, err := ValidateTaskWithWarnings(task, allowlist, "")
+			if !tc.rejected {
+				if err != nil {
+					t.Fatalf("task rejected: %v", err)
+				}
+				return
+			}
+
+			var appErr *apperrors.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("err = %v, want an AppError", err)
+			}
+			if len(appErr.FieldViolations) != 1 {
+				t.Fatalf("violations = %v, want one", appErr.FieldViolations)
+			}
+			v := appErr.FieldViolations[0]
+			if v.Field != "priority" || v.Code != errorspb.AppErrorCode_DISALLOWED_VALUE.String() {
+				t.Errorf("violation = %s/%s, want priority/DISALLOWED_VALUE", v.Field, v.Code)
+			}
+		})
+	}
+
+	// Without an allowlist every priority is accepted
+	if _, err := ValidateTaskWithWarnings(&todopb.Task{Title: "Plan sprint", Priority: todopb.Priority_PRIORITY_LOW}, nil, ""); err != nil {
+		t.Errorf("unrestricted tenant rejected LOW priority: %v", err)
+	}
+}
+
+func TestAllowlistRejectsDisallowedStatus(t *testing.T) {
+	allowlist, err := NewAllowlist([]string{"STATUS_PENDING", "STATUS_IN_PROGRESS", "STATUS_COMPLETED"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ValidateTaskWithWarnings(&todopb.Task{Title: "Plan sprint", Status: todopb.Status_STATUS_CANCELLED}, allowlist, "")
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || len(appErr.FieldViolations) != 1 || appErr.FieldViolations[0].Field != "status" {
+		t.Errorf("err = %v, want a single status violation", err)
+	}
+}
+
+func TestNewAllowlistRejectsUnknownNames(t *testing.T) {
+	if _, err := NewAllowlist([]string{"STATUS_ARCHIVED"}, nil); err == nil {
+		t.Error("unknown status accepted")
+	}
+	if _, err := NewAllowlist(nil, []string{"LOW"}); err == nil {
+		t.Error("priority without the enum prefix accepted")
+	}
+}
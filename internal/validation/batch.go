@@ -0,0 +1,119 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	pii "github.com/bhatti/todo-api-errors/api/proto/pii/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// MaxBatchSize caps how many items one batch call may carry, for tasks and
+// accounts alike
+const MaxBatchSize = 100
+
+// maxSummaryIndices bounds how many invalid positions a batch summary lists
+const maxSummaryIndices = 10
+
+// withBatchSummary adds a batchSummary extension to a batch validation error
+// saying how many of the total items under field are invalid and listing the
+// first few of their indices, so import tools needn't count errors[] entries
+func withBatchSummary(err error, field string, total int) error {
+	appErr, ok := err.(*apperrors.AppError)
+	if !ok {
+		return err
+	}
+
+	invalid := make(map[int]bool)
+	for _, fv := range appErr.FieldViolations {
+		var index int
+		if _, scanErr := fmt.Sscanf(fv.Field, field+"[%d]", &index); scanErr == nil {
+			invalid[index] = true
+		}
+	}
+	if len(invalid) == 0 {
+		return err
+	}
+
+	indices := make([]int, 0, len(invalid))
+	for index := range invalid {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	if len(indices) > maxSummaryIndices {
+		indices = indices[:maxSummaryIndices]
+	}
+	listed := make([]interface{}, len(indices))
+	for i, index := range indices {
+		listed[i] = index
+	}
+
+	if summary, structErr := structpb.NewStruct(map[string]interface{}{
+		"total":          total,
+		"invalidCount":   len(invalid),
+		"invalidIndices": listed,
+	}); structErr == nil {
+		if ext, anyErr := anypb.New(summary); anyErr == nil {
+			if appErr.Extensions == nil {
+				appErr.Extensions = make(map[string]*anypb.Any)
+			}
+			appErr.Extensions["batchSummary"] = ext
+		}
+	}
+	return appErr
+}
+
+// addBatchSizeViolations checks that a batch field holds between 1 and
+// MaxBatchSize items
+func addBatchSizeViolations(violations *apperrors.Violations, field string, n int, item string) {
+	if n == 0 {
+		violations.Addf(field, errorspb.AppErrorCode_EMPTY_BATCH, "At least one %s is required", item)
+	}
+	if n > MaxBatchSize {
+		violations.Addf(field, errorspb.AppErrorCode_BATCH_TOO_LARGE,
+			"Batch size %d exceeds maximum of %d", n, MaxBatchSize)
+	}
+}
+
+// ValidateBatchCreateAccounts checks the batch size and every account in it,
+// reporting violations with their position, e.g. requests[2].account.email
+func ValidateBatchCreateAccounts(req *pii.BatchCreateAccountsRequest, traceID string) error {
+	violations := apperrors.NewViolations()
+	addBatchSizeViolations(violations, "requests", len(req.Requests), "account")
+
+	for i, createReq := range req.Requests {
+		if createReq.GetAccount() == nil {
+			violations.Add(fmt.Sprintf("requests[%d].account", i), errorspb.AppErrorCode_REQUIRED_FIELD, "Account is required")
+			continue
+		}
+
+		if err := ValidateAccount(createReq.Account, traceID); err != nil {
+			if appErr, ok := err.(*apperrors.AppError); ok {
+				for _, violation := range appErr.FieldViolations {
+					violation.Field = fmt.Sprintf("requests[%d].%s", i, violation.Field)
+					violations.Append(violation)
+				}
+			}
+		}
+	}
+
+	return withBatchSummary(violations.Err(traceID), "requests", len(req.Requests))
+}
+
+// ValidateBatchGetAccounts checks that a batch get names between 1 and 100
+// accounts, none of them blank
+func ValidateBatchGetAccounts(req *pii.BatchGetAccountsRequest, traceID string) error {
+	violations := apperrors.NewViolations()
+	addBatchSizeViolations(violations, "ids", len(req.Ids), "account ID")
+
+	for i, id := range req.Ids {
+		if id == "" {
+			violations.Add(apperrors.IndexedField("ids", i), errorspb.AppErrorCode_REQUIRED_FIELD, "Account ID is required")
+		}
+	}
+
+	return violations.Err(traceID)
+}
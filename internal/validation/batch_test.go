@@ -0,0 +1,76 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestBatchValidationSummarizesInvalidItems(t *testing.T) {
+	req := &todopb.BatchCreateTasksRequest{}
+	for i := 0; i < 10; i++ {
+		task := &todopb.Task{Title: fmt.Sprintf("Task %d", i)}
+		switch i {
+		case 2:
+			task.Title = ""
+		case 5:
+			task = nil
+		case 7:
+			task.Tags = []string{"dup", "dup"}
+		}
+		req.Requests = append(req.Requests, &todopb.CreateTaskRequest{Task: task})
+	}
+
+	err := ValidateBatchCreateTasks(req, true, "")
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("err = %v, want an AppError", err)
+	}
+	ext, ok := appErr.Extensions["batchSummary"]
+	if !ok {
+		t.Fatalf("no batchSummary extension in %v", appErr.Extensions)
+	}
+	var summary structpb.Struct
+	if err := ext.UnmarshalTo(&summary); err != nil {
+		t.Fatal(err)
+	}
+
+	got := summary.AsMap()
+	if got["total"] != 10.0 || got["invalidCount"] != 3.0 {
+		t.Errorf("summary = %v, want 3 of 10 invalid", got)
+	}
+	if indices := got["invalidIndices"]; !reflect.DeepEqual(indices, []interface{}{2.0, 5.0, 7.0}) {
+		t.Errorf("invalid indices = %v, want [2 5 7]", indices)
+	}
+}
+
+func TestBatchSummaryListsFirstIndicesOnly(t *testing.T) {
+	err := withBatchSummary(apperrors.NewViolations().
+		Add("requests[12].title", 0, "bad").
+		Add("requests[3].title", 0, "bad").
+		Add("requests[3].description", 0, "bad").
+		Err(""), "requests", 20)
+
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("err = %v, want an AppError", err)
+	}
+	var summary structpb.Struct
+	if err := appErr.Extensions["batchSummary"].UnmarshalTo(&summary); err != nil {
+		t.Fatal(err)
+	}
+	if got := summary.AsMap(); got["invalidCount"] != 2.0 || !reflect.DeepEqual(got["invalidIndices"], []interface{}{3.0, 12.0}) {
+		t.Errorf("summary = %v, want indices 3 and 12 counted once each", got)
+	}
+
+	// Errors without per-item violations get no summary
+	sizeOnly := apperrors.NewViolations().Add("requests", 0, "empty").Err("")
+	if withBatchSummary(sizeOnly, "requests", 0).(*apperrors.AppError).Extensions != nil {
+		t.Error("size-only error got a summary")
+	}
+}
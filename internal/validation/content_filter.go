@@ -0,0 +1,88 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+)
+
+// ContentFilter screens free text such as task titles. Match returns the
+// blocked word found in text, or "" if the text is acceptable.
+type ContentFilter interface {
+	Match(text string) string
+}
+
+// noContentFilter accepts everything; it is the default
+type noContentFilter struct{}
+
+func (noContentFilter) Match(string) string { return "" }
+
+// wordListFilter blocks whole words from a list, ignoring case
+type wordListFilter struct {
+	pattern *regexp.Regexp
+}
+
+// NewWordListFilter blocks the given words wherever they appear as whole
+// words, ignoring case. Matching respects word boundaries, so blocking "ass"
+// does not reject "class" or "assignment". An empty list blocks nothing.
+func NewWordListFilter(words []string) ContentFilter {
+	var quoted []string
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) == 0 {
+		return noContentFilter{}
+	}
+	return &wordListFilter{pattern: regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)}
+}
+
+func (f *wordListFilter) Match(text string) string {
+	return f.pattern.FindString(text)
+}
+
+var (
+	contentFilterMu sync.RWMutex
+	contentFilter   ContentFilter = noContentFilter{}
+)
+
+// SetContentFilter installs the filter ValidateTask applies to titles and
+// descriptions; nil restores the default, which accepts everything
+func SetContentFilter(f ContentFilter) {
+	contentFilterMu.Lock()
+	defer contentFilterMu.Unlock()
+	if f == nil {
+		f = noContentFilter{}
+	}
+	contentFilter = f
+}
+
+func currentContentFilter() ContentFilter {
+	contentFilterMu.RLock()
+	defer contentFilterMu.RUnlock()
+	return contentFilter
+}
+
+// contentViolations reports a title or description containing blocked words.
+// The word itself is left out of the description.
+func contentViolations(task *todopb.Task) []*errorspb.FieldViolation {
+	filter := currentContentFilter()
+
+	var violations []*errorspb.FieldViolation
+	check := func(field, label, value string) {
+		if value != "" && filter.Match(value) != "" {
+			violations = append(violations, &errorspb.FieldViolation{
+				Field:       field,
+				Code:        errorspb.AppErrorCode_DISALLOWED_CONTENT.String(),
+				Description: label + " contains a word that is not allowed",
+			})
+		}
+	}
+	check("title", "Title", task.Title)
+	check("description", "Description", task.Description)
+	return violations
+}
@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+)
+
+func TestContentFilterBlocksWholeWords(t *testing.T) {
+	SetContentFilter(NewWordListFilter([]string{"darn", " heck "}))
+	t.Cleanup(func() { SetContentFilter(nil) })
+
+	for _, tc := range []struct {
+		name  string
+		task  *todopb.Task
+		field string // field with the violation, "" when accepted
+	}{
+		{"blocked word in title", &todopb.Task{Title: "Fix the darn printer"}, "title"},
+		{"blocked word ignores case", &todopb.Task{Title: "Ok", Description: "What the HECK"}, "description"},
+		{"substring of a longer word", &todopb.Task{Title: "Darning socks", Description: "check the heckler"}, ""},
+		{"clean task", &todopb.Task{Title: "Water plants"}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTask(tc.task, "")
+			if tc.field == "" {
+				if err != nil {
+					t.Errorf("task rejected: %v", err)
+				}
+				return
+			}
+
+			var appErr *apperrors.AppError
+			if !errors.As(err, &appErr) || len(appErr.FieldViolations) != 1 {
+				t.Fatalf("err = %v, want one violation", err)
+			}
+			v := appErr.FieldViolations[0]
+			if v.Field != tc.field || v.Code != errorspb.AppErrorCode_DISALLOWED_CONTENT.String() {
+				t.Errorf("violation = %s %s, want DISALLOWED_CONTENT on %s", v.Field, v.Code, tc.field)
+			}
+			if strings.Contains(strings.ToLower(v.Description), "darn") || strings.Contains(strings.ToLower(v.Description), "heck") {
+				t.Errorf("description %q repeats the blocked word", v.Description)
+			}
+		})
+	}
+}
+
+func TestDefaultContentFilterAcceptsEverything(t *testing.T) {
+	if word := NewWordListFilter(nil).Match("anything at all"); word != "" {
+		t.Errorf("empty word list matched %q", word)
+	}
+	if err := ValidateTask(&todopb.Task{Title: "darn"}, ""); err != nil {
+		t.Errorf("default filter rejected a task: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+)
+
+// ParseRelativeDueDate resolves a relative due date against now. It accepts
+// "today" and "tomorrow" (the end of that day), and a positive offset such as
+// "+72h", "+90m" or "+3d".
+func ParseRelativeDueDate(expr string, now time.Time, traceID string) (time.Time, error) {
+	switch strings.ToLower(strings.TrimSpace(expr)) {
+	case "today":
+		return endOfDay(now, 0), nil
+	case "tomorrow":
+		return endOfDay(now, 1), nil
+	}
+
+	if offset, ok := parseOffset(strings.TrimSpace(expr)); ok {
+		return now.Add(offset), nil
+	}
+
+	return time.Time{}, apperrors.NewViolations().
+		Addf("relative_due_date", errorspb.AppErrorCode_INVALID_FORMAT,
+			"Relative due date '%s' must be 'today', 'tomorrow' or a positive offset of at most %d days, such as '+24h' or '+3d'", expr, maxRelativeOffsetDays).
+		Err(traceID)
+}
+
+// maxRelativeOffsetDays bounds relative offsets, well short of the ~292 years
+// a time.Duration can hold, so "+<n>d" can never overflow when multiplied out
+const maxRelativeOffsetDays = 10 * 366
+
+// maxRelativeOffset is maxRelativeOffsetDays as a duration
+const maxRelativeOffset = maxRelativeOffsetDays * 24 * time.Hour
+
+// parseOffset parses "+<duration>", where the duration may also be whole days.
+// Offsets beyond maxRelativeOffset are rejected.
+func parseOffset(expr string) (time.Duration, bool) {
+	if !strings.HasPrefix(expr, "+") {
+		return 0, false
+	}
+	expr = expr[1:]
+
+	if days, ok := strings.CutSuffix(expr, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 || n > maxRelativeOffsetDays {
+			return 0, false
+		}
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+
+	offset, err := time.ParseDuration(expr)
+	if err != nil || offset <= 0 || offset > maxRelativeOffset {
+		return 0, false
+	}
+	return offset, true
+}
+
+// endOfDay returns the last second of the day days after now, in now's zone
+func endOfDay(now time.Time, days int) time.Time {
+	y, m, d := now.Date()
+	return time.Date(y, m, d+days+1, 0, 0, 0, 0, now.Location()).Add(-time.Second)
+}
@@ -0,0 +1,64 @@
+package validation
+
+import (
+	stderrors "errors"
+	"testing"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+)
+
+func TestParseRelativeDueDate(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		expr string
+		want time.Time
+	}{
+		{"+24h", now.Add(24 * time.Hour)},
+		{"+90m", now.Add(90 * time.Minute)},
+		{"+3d", now.AddDate(0, 0, 3)},
+		{" +3d ", now.AddDate(0, 0, 3)},
+		{"today", time.Date(2026, 3, 10, 23, 59, 59, 0, time.UTC)},
+		{"Tomorrow", time.Date(2026, 3, 11, 23, 59, 59, 0, time.UTC)},
+		{"+3660d", now.Add(maxRelativeOffset)},
+	} {
+		got, err := ParseRelativeDueDate(tc.expr, now, "")
+		if err != nil {
+			t.Errorf("ParseRelativeDueDate(%q) = %v", tc.expr, err)
+			continue
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("ParseRelativeDueDate(%q) = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestParseRelativeDueDateRejectsInvalidAndOutOfRange(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	for _, expr := range []string{
+		"next week",
+		"24h",
+		"+0d",
+		"+-3d",
+		"-24h",
+		"+3.5d",
+		"+3661d",
+		"+200000h",
+		// Would overflow time.Duration once multiplied out to nanoseconds
+		"+9223372036854775807d",
+		"+106752d",
+	} {
+		_, err := ParseRelativeDueDate(expr, now, "")
+		var appErr *apperrors.AppError
+		if !stderrors.As(err, &appErr) || len(appErr.FieldViolations) != 1 {
+			t.Errorf("ParseRelativeDueDate(%q) = %v, want one violation", expr, err)
+			continue
+		}
+		if fv := appErr.FieldViolations[0]; fv.Field != "relative_due_date" || fv.Code != errorspb.AppErrorCode_INVALID_FORMAT.String() {
+			t.Errorf("ParseRelativeDueDate(%q) violation = %s/%s, want relative_due_date/INVALID_FORMAT", expr, fv.Field, fv.Code)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+)
+
+// Limits tightens the length rules declared in the proto for a deployment.
+// A zero value leaves the proto rule as the only limit.
+type Limits struct {
+	MaxTitleLength       int
+	MaxDescriptionLength int
+
+	// OverdueGrace is how long after its due date a task may be completed
+	// before OVERDUE_COMPLETION is reported; zero flags any late completion
+	OverdueGrace time.Duration
+}
+
+var (
+	limitsMu sync.RWMutex
+	limits   Limits
+)
+
+// SetLimits installs deployment-specific limits that ValidateTask enforces in
+// addition to the proto rules. Limits looser than the proto have no effect.
+func SetLimits(l Limits) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	limits = l
+}
+
+func currentLimits() Limits {
+	limitsMu.RLock()
+	defer limitsMu.RUnlock()
+	return limits
+}
+
+// limitViolations reports fields longer than the configured limits, skipping
+// fields the proto rule already rejected so each field is reported once
+func limitViolations(task *todopb.Task, existing []*errorspb.FieldViolation) []*errorspb.FieldViolation {
+	l := currentLimits()
+
+	var violations []*errorspb.FieldViolation
+	check := func(field, value string, max int) {
+		if max <= 0 || hasViolation(existing, field) {
+			return
+		}
+		if n := utf8.RuneCountInString(value); n > max {
+			violations = append(violations, &errorspb.FieldViolation{
+				Field:       field,
+				Code:        errorspb.AppErrorCode_TOO_LONG.String(),
+				Description: fmt.Sprintf("%s is %d characters; this deployment allows at most %d", field, n, max),
+			})
+		}
+	}
+	check("title", task.Title, l.MaxTitleLength)
+	check("description", task.Description, l.MaxDescriptionLength)
+	return violations
+}
+
+func hasViolation(violations []*errorspb.FieldViolation, field string) bool {
+	for _, v := range violations {
+		if v.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestLimitsAreStricterThanProtoRules(t *testing.T) {
+	SetLimits(Limits{MaxTitleLength: 20, MaxDescriptionLength: 10})
+	t.Cleanup(func() { SetLimits(Limits{}) })
+
+	for _, tc := range []struct {
+		name string
+		task *todopb.Task
+		want map[string]string // field -> limit named in the description
+	}{
+		{"within limits", &todopb.Task{Title: strings.Repeat("a", 20), Description: "short"}, nil},
+		{"title over deployment limit", &todopb.Task{Title: strings.Repeat("a", 21)}, map[string]string{"title": "at most 20"}},
+		{"description over deployment limit", &todopb.Task{Title: "ok", Description: strings.Repeat("d", 11)}, map[string]string{"description": "at most 10"}},
+		{"runes not bytes", &todopb.Task{Title: strings.Repeat("é", 20)}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTask(tc.task, "")
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("task rejected: %v", err)
+				}
+				return
+			}
+
+			var appErr *apperrors.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("err = %v, want an AppError", err)
+			}
+			if len(appErr.FieldViolations) != len(tc.want) {
+				t.Fatalf("violations = %v, want %d", appErr.FieldViolations, len(tc.want))
+			}
+			for _, v := range appErr.FieldViolations {
+				if v.Code != errorspb.AppErrorCode_TOO_LONG.String() || !strings.Contains(v.Description, tc.want[v.Field]) {
+					t.Errorf("violation %s = %s %q, want TOO_LONG mentioning %q", v.Field, v.Code, v.Description, tc.want[v.Field])
+				}
+			}
+		})
+	}
+}
+
+func TestLimitsReportProtoViolationOnce(t *testing.T) {
+	SetLimits(Limits{MaxTitleLength: 20})
+	t.Cleanup(func() { SetLimits(Limits{}) })
+
+	// 201 characters breaks the proto rule too; only one title violation is reported
+	err := ValidateTask(&todopb.Task{Title: strings.Repeat("a", 201)}, "")
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("err = %v, want an AppError", err)
+	}
+	var titles int
+	for _, v := range appErr.FieldViolations {
+		if v.Field == "title" {
+			titles++
+		}
+	}
+	if titles != 1 {
+		t.Errorf("%d title violations, want 1: %v", titles, appErr.FieldViolations)
+	}
+}
+
+func TestLooserLimitsHaveNoEffect(t *testing.T) {
+	SetLimits(Limits{MaxTitleLength: 500})
+	t.Cleanup(func() { SetLimits(Limits{}) })
+
+	if err := ValidateTask(&todopb.Task{Title: strings.Repeat("a", 201)}, ""); err == nil {
+		t.Error("proto title limit bypassed by a looser deployment limit")
+	}
+}
+
+func TestOverdueGrace(t *testing.T) {
+	SetLimits(Limits{OverdueGrace: time.Hour})
+	t.Cleanup(func() { SetLimits(Limits{}) })
+
+	due := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	for _, tc := range []struct {
+		name        string
+		completedAt time.Time
+		flagged     bool
+	}{
+		{"just inside the grace window", due.Add(time.Hour - time.Second), false},
+		{"just outside the grace window", due.Add(time.Hour + time.Second), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTask(&todopb.Task{
+				Title:      "Late task",
+				Status:     todopb.Status_STATUS_COMPLETED,
+				DueDate:    timestamppb.New(due),
+				UpdateTime: timestamppb.New(tc.completedAt),
+			}, "")
+
+			var flagged bool
+			var appErr *apperrors.AppError
+			if errors.As(err, &appErr) {
+				for _, v := range appErr.FieldViolations {
+					flagged = flagged || v.Code == errorspb.AppErrorCode_OVERDUE_COMPLETION.String()
+				}
+			} else if err != nil {
+				t.Fatalf("err = %v, want an AppError", err)
+			}
+			if flagged != tc.flagged {
+				t.Errorf("OVERDUE_COMPLETION reported = %v, want %v (err %v)", flagged, tc.flagged, err)
+			}
+		})
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	"buf.build/go/protovalidate"
 	errorspb "github.com/bhatti/todo-api-errors/api/proto/errors/v1"
 	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/duedate"
 	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
 	"google.golang.org/protobuf/proto"
 )
@@ -52,36 +54,60 @@ func ValidateRequest(req proto.Message, traceID string) error {
 	return nil
 }
 
+// Thresholds for advisory warnings. Tasks beyond them are still accepted.
+const (
+	dueSoonWindow     = 24 * time.Hour
+	longTitleWarnSize = 150
+)
+
 // ValidateTask performs additional business logic validation
 func ValidateTask(task *todopb.Task, traceID string) error {
-	var violations []*errorspb.FieldViolation
+	_, err := ValidateTaskWithWarnings(task, nil, traceID)
+	return err
+}
+
+// ValidateTaskWithWarnings validates a task like ValidateTask and also returns
+// soft warnings: advisories that clients may surface but that never fail the
+// request. Warnings are only returned when the task is otherwise valid. A
+// non-nil allowlist rejects statuses and priorities the tenant doesn't use.
+func ValidateTaskWithWarnings(task *todopb.Task, allowlist *Allowlist, traceID string) ([]*errorspb.FieldViolation, error) {
+	violations := apperrors.NewViolations()
 
 	// Proto validation first
 	if err := ValidateRequest(task, traceID); err != nil {
 		if appErr, ok := err.(*apperrors.AppError); ok {
-			violations = append(violations, appErr.FieldViolations...)
+			violations.Append(appErr.FieldViolations...)
+		}
+	}
+
+	// Deployment length limits, stricter than the proto rules
+	violations.Append(limitViolations(task, violations.List())...)
+
+	// Tenant workflow restrictions
+	violations.Append(allowlist.violations(task)...)
+
+	// Deployment word list
+	violations.Append(contentViolations(task)...)
+
+	if task.TimeZone != "" {
+		if _, err := time.LoadLocation(task.TimeZone); err != nil {
+			violations.Addf("time_zone", errorspb.AppErrorCode_INVALID_VALUE,
+				"Time zone '%s' is not a known IANA time zone, e.g. America/Los_Angeles", task.TimeZone)
 		}
 	}
 
 	// Additional business rules
 	if task.Status == todopb.Status_STATUS_COMPLETED && task.DueDate != nil {
-		if task.UpdateTime != nil && task.UpdateTime.AsTime().After(task.DueDate.AsTime()) {
-			violations = append(violations, &errorspb.FieldViolation{
-				Field:       "due_date",
-				Code:        errorspb.AppErrorCode_OVERDUE_COMPLETION.String(),
-				Description: "Task was completed after the due date",
-			})
+		if task.UpdateTime != nil && task.UpdateTime.AsTime().After(duedate.Deadline(task).Add(currentLimits().OverdueGrace)) {
+			violations.Add("due_date", errorspb.AppErrorCode_OVERDUE_COMPLETION, "Task was completed after the due date")
 		}
 	}
 
 	// Validate tags format
 	for i, tag := range task.Tags {
 		if !isValidTag(tag) {
-			violations = append(violations, &errorspb.FieldViolation{
-				Field:       fmt.Sprintf("tags[%d]", i),
-				Code:        errorspb.AppErrorCode_INVALID_TAG_FORMAT.String(),
-				Description: fmt.Sprintf("Tag '%s' must be lowercase letters, numbers, and hyphens only", tag),
-			})
+			violations.Addf(apperrors.IndexedField("tags", i), errorspb.AppErrorCode_INVALID_TAG_FORMAT,
+				"Tag '%s' must be lowercase letters, numbers, and hyphens only", tag)
 		}
 	}
 
@@ -89,15 +115,113 @@ func ValidateTask(task *todopb.Task, traceID string) error {
 	tagMap := make(map[string]bool)
 	for i, tag := range task.Tags {
 		if tagMap[tag] {
-			violations = append(violations, &errorspb.FieldViolation{
-				Field:       fmt.Sprintf("tags[%d]", i),
-				Code:        errorspb.AppErrorCode_DUPLICATE_TAG.String(),
-				Description: fmt.Sprintf("Tag '%s' appears multiple times", tag),
-			})
+			violations.Addf(apperrors.IndexedField("tags", i), errorspb.AppErrorCode_DUPLICATE_TAG,
+				"Tag '%s' appears multiple times", tag)
 		}
 		tagMap[tag] = true
 	}
 
+	echoTaskValues(task, violations.List())
+
+	if err := violations.Err(traceID); err != nil {
+		return nil, err
+	}
+
+	return taskWarnings(task), nil
+}
+
+// echoTaskValues echoes the rejected title, tags and priority so clients can
+// point at the offending input
+func echoTaskValues(task *todopb.Task, violations []*errorspb.FieldViolation) {
+	for _, fv := range violations {
+		var index int
+		switch {
+		case fv.Field == "title":
+			apperrors.EchoRejectedValue(fv, task.Title)
+		case fv.Field == "priority":
+			apperrors.EchoRejectedValue(fv, task.Priority.String())
+		case strings.HasPrefix(fv.Field, "tags["):
+			if _, err := fmt.Sscanf(fv.Field, "tags[%d]", &index); err == nil && index >= 0 && index < len(task.Tags) {
+				apperrors.EchoRejectedValue(fv, task.Tags[index])
+			}
+		}
+	}
+}
+
+func taskWarnings(task *todopb.Task) []*errorspb.FieldViolation {
+	var warnings []*errorspb.FieldViolation
+
+	if task.DueDate != nil && task.Status != todopb.Status_STATUS_COMPLETED {
+		if until := time.Until(duedate.Deadline(task)); until > 0 && until < dueSoonWindow {
+			warnings = append(warnings, &errorspb.FieldViolation{
+				Field:       "due_date",
+				Code:        errorspb.AppErrorCode_DUE_SOON.String(),
+				Description: fmt.Sprintf("Task is due in less than %s", dueSoonWindow),
+			})
+		}
+	}
+
+	if len(task.Title) > longTitleWarnSize {
+		warnings = append(warnings, &errorspb.FieldViolation{
+			Field:       "title",
+			Code:        errorspb.AppErrorCode_LONG_TITLE.String(),
+			Description: fmt.Sprintf("Title is %d characters; consider keeping it under %d", len(task.Title), longTitleWarnSize),
+		})
+	}
+
+	return warnings
+}
+
+// ValidateTaskID checks a client-provided task ID. IDs follow AIP-122: 1-63
+// lowercase letters, digits and hyphens, starting and ending with a letter or
+// digit, so server-generated UUIDs are valid too.
+func ValidateTaskID(taskID string, traceID string) error {
+	if validTaskIDPattern.MatchString(taskID) {
+		return nil
+	}
+	return apperrors.NewValidationFailed([]*errorspb.FieldViolation{
+		{
+			Field:       "task_id",
+			Code:        errorspb.AppErrorCode_INVALID_FORMAT.String(),
+			Description: fmt.Sprintf("Task ID '%s' must be 1-63 lowercase letters, numbers, and hyphens, and cannot start or end with a hyphen", taskID),
+		},
+	}, traceID)
+}
+
+// ValidateRenameTag checks that a tag rename names an existing tag and a
+// well-formed, different target
+func ValidateRenameTag(req *todopb.RenameTagRequest, traceID string) error {
+	var violations []*errorspb.FieldViolation
+
+	if req.From == "" {
+		violations = append(violations, &errorspb.FieldViolation{
+			Field:       "from",
+			Code:        errorspb.AppErrorCode_REQUIRED_FIELD.String(),
+			Description: "Tag to rename is required",
+		})
+	}
+
+	switch {
+	case req.To == "":
+		violations = append(violations, &errorspb.FieldViolation{
+			Field:       "to",
+			Code:        errorspb.AppErrorCode_REQUIRED_FIELD.String(),
+			Description: "New tag name is required",
+		})
+	case !isValidTag(req.To):
+		violations = append(violations, &errorspb.FieldViolation{
+			Field:       "to",
+			Code:        errorspb.AppErrorCode_INVALID_TAG_FORMAT.String(),
+			Description: fmt.Sprintf("Tag '%s' must be lowercase letters, numbers, and hyphens only", req.To),
+		})
+	case req.To == req.From:
+		violations = append(violations, &errorspb.FieldViolation{
+			Field:       "to",
+			Code:        errorspb.AppErrorCode_INVALID_VALUE.String(),
+			Description: "New tag name must differ from the tag being renamed",
+		})
+	}
+
 	if len(violations) > 0 {
 		return apperrors.NewValidationFailed(violations, traceID)
 	}
@@ -105,8 +229,9 @@ func ValidateTask(task *todopb.Task, traceID string) error {
 	return nil
 }
 
-// ValidateBatchCreateTasks validates batch operations
-func ValidateBatchCreateTasks(req *todopb.BatchCreateTasksRequest, traceID string) error {
+// ValidateBatchCreateTasks validates batch operations. Repeated titles within
+// the batch are only rejected when uniqueTitles is set.
+func ValidateBatchCreateTasks(req *todopb.BatchCreateTasksRequest, uniqueTitles bool, traceID string) error {
 	var violations []*errorspb.FieldViolation
 
 	// Check batch size
@@ -118,11 +243,11 @@ func ValidateBatchCreateTasks(req *todopb.BatchCreateTasksRequest, traceID strin
 		})
 	}
 
-	if len(req.Requests) > 100 {
+	if len(req.Requests) > MaxBatchSize {
 		violations = append(violations, &errorspb.FieldViolation{
 			Field:       "requests",
 			Code:        errorspb.AppErrorCode_BATCH_TOO_LARGE.String(),
-			Description: fmt.Sprintf("Batch size %d exceeds maximum of 100", len(req.Requests)),
+			Description: fmt.Sprintf("Batch size %d exceeds maximum of %d", len(req.Requests), MaxBatchSize),
 		})
 	}
 
@@ -151,7 +276,7 @@ func ValidateBatchCreateTasks(req *todopb.BatchCreateTasksRequest, traceID strin
 	// Check for duplicate titles
 	titleMap := make(map[string][]int)
 	for i, createReq := range req.Requests {
-		if createReq.Task != nil && createReq.Task.Title != "" {
+		if uniqueTitles && createReq.Task != nil && createReq.Task.Title != "" {
 			titleMap[createReq.Task.Title] = append(titleMap[createReq.Task.Title], i)
 		}
 	}
@@ -169,12 +294,47 @@ func ValidateBatchCreateTasks(req *todopb.BatchCreateTasksRequest, traceID strin
 	}
 
 	if len(violations) > 0 {
-		return apperrors.NewValidationFailed(violations, traceID)
+		return withBatchSummary(apperrors.NewValidationFailed(violations, traceID), "requests", len(req.Requests))
 	}
 
 	return nil
 }
 
+// ValidateBatchGetTasks checks that a batch get names between 1 and 100 tasks,
+// each in the tasks/{id} format
+func ValidateBatchGetTasks(req *todopb.BatchGetTasksRequest, traceID string) error {
+	violations := apperrors.NewViolations()
+	addBatchSizeViolations(violations, "names", len(req.Names), "task name")
+
+	for i, name := range req.Names {
+		id := strings.TrimPrefix(name, "tasks/")
+		if id == name || !validTaskIDPattern.MatchString(id) {
+			violations.Addf(apperrors.IndexedField("names", i), errorspb.AppErrorCode_INVALID_FORMAT,
+				"Task name '%s' must be in format 'tasks/{id}'", name)
+		}
+	}
+
+	return violations.Err(traceID)
+}
+
+// ValidateReassignUserTasks checks that a reassignment names two different users
+func ValidateReassignUserTasks(req *todopb.ReassignUserTasksRequest, traceID string) error {
+	violations := apperrors.NewViolations()
+
+	if req.FromUser == "" {
+		violations.Add("from_user", errorspb.AppErrorCode_REQUIRED_FIELD, "User to move tasks from is required")
+	}
+
+	switch {
+	case req.ToUser == "":
+		violations.Add("to_user", errorspb.AppErrorCode_REQUIRED_FIELD, "User to move tasks to is required")
+	case req.ToUser == req.FromUser:
+		violations.Add("to_user", errorspb.AppErrorCode_INVALID_VALUE, "Tasks must be moved to a different user")
+	}
+
+	return violations.Err(traceID)
+}
+
 // Helper functions
 func formatFieldPath(fieldPath *validate.FieldPath) string {
 	if fieldPath == nil {
@@ -213,7 +373,10 @@ func mapConstraintToCode(ruleId string) string {
 	}
 }
 
-var validTagPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+var (
+	validTagPattern    = regexp.MustCompile(`^[a-z0-9-]+$`)
+	validTaskIDPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+)
 
 func isValidTag(tag string) bool {
 	return len(tag) <= 50 && validTagPattern.MatchString(tag)
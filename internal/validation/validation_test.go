@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestValidateTaskID(t *testing.T) {
+	for _, id := range []string{
+		"a",
+		"weekly-review",
+		"2026-q1",
+		"3f1c9e2a-7b4d-4c1e-9a8f-0d2b6c5e4a13",
+		strings.Repeat("a", 63),
+	} {
+		if err := ValidateTaskID(id, ""); err != nil {
+			t.Errorf("ValidateTaskID(%q) = %v, want nil", id, err)
+		}
+	}
+
+	for _, id := range []string{
+		"",
+		"-leading",
+		"trailing-",
+		"Upper",
+		"with space",
+		"tasks/abc",
+		strings.Repeat("a", 64),
+	} {
+		if err := ValidateTaskID(id, ""); err == nil {
+			t.Errorf("ValidateTaskID(%q) = nil, want an error", id)
+		}
+	}
+}
+
+func TestValidateTaskWithWarnings(t *testing.T) {
+	soon := timestamppb.New(time.Now().Add(2 * time.Hour))
+	later := timestamppb.New(time.Now().Add(7 * 24 * time.Hour))
+	longTitle := strings.Repeat("t", longTitleWarnSize+1)
+
+	for _, tc := range []struct {
+		name string
+		task *todopb.Task
+		want []string
+	}{
+		{"plain", &todopb.Task{Title: "Plain"}, nil},
+		{"due soon", &todopb.Task{Title: "Soon", DueDate: soon}, []string{"due_date"}},
+		{"due soon but completed", &todopb.Task{Title: "Done", DueDate: soon, Status: todopb.Status_STATUS_COMPLETED}, nil},
+		{"due later", &todopb.Task{Title: "Later", DueDate: later}, nil},
+		{"long title", &todopb.Task{Title: longTitle}, []string{"title"}},
+		{"both", &todopb.Task{Title: longTitle, DueDate: soon}, []string{"due_date", "title"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := ValidateTaskWithWarnings(tc.task, nil, "")
+			if err != nil {
+				t.Fatalf("valid task rejected: %v", err)
+			}
+			var fields []string
+			for _, w := range warnings {
+				fields = append(fields, w.Field)
+			}
+			if !reflect.DeepEqual(fields, tc.want) {
+				t.Errorf("warnings on %v, want %v", fields, tc.want)
+			}
+		})
+	}
+
+	// A task that fails validation returns the error and no warnings
+	warnings, err := ValidateTaskWithWarnings(&todopb.Task{Title: "", DueDate: soon}, nil, "")
+	if err == nil || warnings != nil {
+		t.Errorf("invalid task = %v, %v; want an error and no warnings", warnings, err)
+	}
+}
+
+func TestValidateBatchGetTasks(t *testing.T) {
+	if err := ValidateBatchGetTasks(&todopb.BatchGetTasksRequest{Names: []string{"tasks/a", "tasks/weekly-review"}}, ""); err != nil {
+		t.Errorf("valid names: %v", err)
+	}
+
+	tooMany := make([]string, MaxBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = "tasks/a"
+	}
+	for name, names := range map[string][]string{
+		"empty":      nil,
+		"too many":   tooMany,
+		"bad format": {"tasks/a", "a"},
+	} {
+		if err := ValidateBatchGetTasks(&todopb.BatchGetTasksRequest{Names: names}, ""); err == nil {
+			t.Errorf("%s: got nil, want an error", name)
+		}
+	}
+}
@@ -2,23 +2,34 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/config"
+	apperrors "github.com/bhatti/todo-api-errors/internal/errors"
 	"github.com/bhatti/todo-api-errors/internal/middleware"
 	"github.com/bhatti/todo-api-errors/internal/monitoring"
 	"github.com/bhatti/todo-api-errors/internal/repository"
 	"github.com/bhatti/todo-api-errors/internal/service"
+	"github.com/bhatti/todo-api-errors/internal/validation"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
@@ -28,14 +39,30 @@ import (
 )
 
 func main() {
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize tracing
+	shutdownTracing, err := monitoring.InitOpenTelemetryTracing(cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to initialize OpenTelemetry tracing: %v", err)
+	}
+
 	// Initialize monitoring
 	if err := monitoring.InitOpenTelemetryMetrics(); err != nil {
 		log.Printf("Failed to initialize OpenTelemetry metrics: %v", err)
 		// Continue without OpenTelemetry - Prometheus will still work
 	}
 
-	// Initialize repository
-	repo := repository.NewInMemoryRepository()
+	// Propagate W3C trace context and baggage across service boundaries
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	// Initialize repository; concurrent reads of the same task share one lookup
+	store := repository.NewInMemoryRepository(cfg.Validation.UniqueTitles)
+	repo := repository.NewCoalescingRepository(store)
 
 	// Initialize service
 	todoService, err := service.NewTodoService(repo)
@@ -43,10 +70,87 @@ func main() {
 		log.Fatalf("Failed to create service: %v", err)
 	}
 
+	// Keep recent writes for debugging vanished-task reports
+	if cfg.Features.MutationLogSize > 0 {
+		mutationLog := repository.NewMutationLog(cfg.Features.MutationLogSize)
+		store.SetMutationLog(mutationLog)
+		todoService.SetMutationLog(mutationLog)
+	}
+
+	// Deployment-specific length limits on top of the proto rules
+	validation.SetLimits(validation.Limits{
+		MaxTitleLength:       cfg.Validation.MaxTitleLength,
+		MaxDescriptionLength: cfg.Validation.MaxDescriptionLength,
+		OverdueGrace:         cfg.Validation.OverdueGrace,
+	})
+	validation.SetContentFilter(validation.NewWordListFilter(cfg.Validation.BlockedWords))
+
+	// Field violation analytics are logged at debug
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(cfg.Logging.Level)); err != nil {
+		log.Fatalf("Invalid log level %q: %v", cfg.Logging.Level, err)
+	}
+	middleware.SetViolationLogLevel(logLevel)
+	if cfg.Logging.ViolationHashKey != "" {
+		middleware.SetViolationHashKey([]byte(cfg.Logging.ViolationHashKey))
+	}
+	requestLogger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+
+	// Match error body keys to the proto names used in success responses
+	middleware.SetSnakeCaseErrorFields(cfg.Errors.SnakeCaseFields)
+	middleware.SetRedactInternalDetails(cfg.Errors.RedactInternal)
+	if err := middleware.SetTrustedProxies(cfg.Errors.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TODO_TRUSTED_PROXIES: %v", err)
+	}
+
+	// Restrict statuses and priorities for tenants with their own workflow
+	allowlists := make(map[string]*validation.Allowlist)
+	defaults := make(map[string]service.TaskDefaults)
+	for tenant, tenantCfg := range cfg.Tenants {
+		allowlist, err := validation.NewAllowlist(tenantCfg.AllowedStatuses, tenantCfg.AllowedPriorities)
+		if err != nil {
+			log.Fatalf("Invalid settings for tenant %s: %v", tenant, err)
+		}
+		allowlists[tenant] = allowlist
+
+		tenantDefaults, err := taskDefaults(tenantCfg, allowlist)
+		if err != nil {
+			log.Fatalf("Invalid settings for tenant %s: %v", tenant, err)
+		}
+		defaults[tenant] = tenantDefaults
+	}
+	todoService.SetTenantAllowlists(allowlists)
+	todoService.SetTenantDefaults(defaults)
+	todoService.SetUniqueTitles(cfg.Validation.UniqueTitles)
+	todoService.SetMaxResponseBytes(cfg.Validation.MaxResponseBytes)
+	if err := todoService.SetDefaultOrderBy(cfg.Validation.DefaultOrderBy); err != nil {
+		log.Fatalf("Invalid TODO_DEFAULT_ORDER_BY: %v", err)
+	}
+
+	anonymousPolicy, err := service.ParseAnonymousPolicy(cfg.Auth.AnonymousPolicy)
+	if err != nil {
+		log.Fatalf("Invalid TODO_ANONYMOUS_POLICY: %v", err)
+	}
+	todoService.SetAnonymousPolicy(anonymousPolicy)
+
+	// Requests are scoped to the tenant of the authenticated user
+	usersByTenant := make(map[string][]string)
+	for tenant, tenantCfg := range cfg.Tenants {
+		usersByTenant[tenant] = tenantCfg.Users
+	}
+	tenantsByUser, err := middleware.TenantsByUser(usersByTenant)
+	if err != nil {
+		log.Fatalf("Invalid tenant users: %v", err)
+	}
+
+	// One chain for RPCs and the gateway's HTTP-only routes, so they share the
+	// concurrency limit and replay nonces
+	interceptors := unaryInterceptors(tenantsByUser, cfg)
+
 	// Start gRPC server
 	grpcPort := ":50051"
 	go func() {
-		if err := startGRPCServer(grpcPort, todoService); err != nil {
+		if err := startGRPCServer(grpcPort, todoService, interceptors); err != nil {
 			log.Fatalf("Failed to start gRPC server: %v", err)
 		}
 	}()
@@ -54,7 +158,7 @@ func main() {
 	// Start HTTP gateway
 	httpPort := ":8080"
 	go func() {
-		if err := startHTTPGateway(httpPort, grpcPort); err != nil {
+		if err := startHTTPGateway(httpPort, grpcPort, todoService, tenantsByUser, interceptors, cfg); err != nil {
 			log.Fatalf("Failed to start HTTP gateway: %v", err)
 		}
 	}()
@@ -62,6 +166,7 @@ func main() {
 	// Start metrics server
 	go func() {
 		http.Handle("/metrics", promhttp.Handler())
+		http.HandleFunc("/readyz", readyzHandler(todoService))
 		if err := http.ListenAndServe(":9090", nil); err != nil {
 			log.Printf("Failed to start metrics server: %v", err)
 		}
@@ -71,6 +176,10 @@ func main() {
 	log.Printf("gRPC server listening on %s", grpcPort)
 	log.Printf("HTTP gateway listening on %s", httpPort)
 	log.Printf("Metrics available at :9090/metrics")
+	log.Printf("Readiness probe available at :9090/readyz")
+	if cfg.Maintenance.ReadOnly {
+		log.Printf("Read-only mode enabled: writes are rejected")
+	}
 
 	// Wait for interrupt signal
 	sigCh := make(chan os.Signal, 1)
@@ -78,21 +187,71 @@ func main() {
 	<-sigCh
 
 	log.Println("Shutting down...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to shut down tracing: %v", err)
+	}
 }
 
-func startGRPCServer(port string, todoService todopb.TodoServiceServer) error {
+// taskDefaults parses a tenant's default status and priority, rejecting
+// defaults the tenant's own allowlist would not accept
+func taskDefaults(tenantCfg config.TenantConfig, allowlist *validation.Allowlist) (service.TaskDefaults, error) {
+	var defaults service.TaskDefaults
+	if name := tenantCfg.DefaultStatus; name != "" {
+		value, ok := todopb.Status_value[name]
+		if !ok {
+			return defaults, fmt.Errorf("unknown default status %q", name)
+		}
+		defaults.Status = todopb.Status(value)
+		if len(allowlist.Statuses) > 0 && !allowlist.Statuses[defaults.Status] {
+			return defaults, fmt.Errorf("default status %s is not in allowed_statuses", name)
+		}
+	}
+	if name := tenantCfg.DefaultPriority; name != "" {
+		value, ok := todopb.Priority_value[name]
+		if !ok {
+			return defaults, fmt.Errorf("unknown default priority %q", name)
+		}
+		defaults.Priority = todopb.Priority(value)
+		if len(allowlist.Priorities) > 0 && !allowlist.Priorities[defaults.Priority] {
+			return defaults, fmt.Errorf("default priority %s is not in allowed_priorities", name)
+		}
+	}
+	return defaults, nil
+}
+
+// unaryInterceptors is the chain every RPC runs through. The gateway runs its
+// HTTP-only routes through the same chain.
+func unaryInterceptors(tenantsByUser map[string]string, cfg *config.Config) []grpc.UnaryServerInterceptor {
+	// Create gRPC server with interceptors - now using the new UnaryErrorInterceptor
+	interceptors := []grpc.UnaryServerInterceptor{
+		middleware.UnaryTraceIDInterceptor, // Resolve the trace ID once for logs and errors
+		middleware.AuthInterceptor(tenantsByUser),
+		middleware.UnaryErrorInterceptor,    // Using new protobuf-based error interceptor
+		middleware.UnaryRecoveryInterceptor, // Inside the error interceptor so panics get the error envelope
+		middleware.ConcurrencyLimitInterceptor(cfg.Concurrency.MaxInFlight, cfg.Concurrency.QueueTimeout),
+		loggingInterceptor(cfg.Logging.SlowRequestThreshold),
+		deadlineInterceptor(cfg.RequestTimeout),
+		middleware.FeatureFlagInterceptor(cfg.Features.Methods),
+		middleware.ReadOnlyInterceptor(cfg.Maintenance.ReadOnly, cfg.Maintenance.RetryAfter),
+	}
+	if cfg.Replay.Secret != "" {
+		interceptors = append(interceptors, middleware.ReplayProtectionInterceptor([]byte(cfg.Replay.Secret), cfg.Replay.Window))
+	}
+	return interceptors
+}
+
+func startGRPCServer(port string, todoService todopb.TodoServiceServer, interceptors []grpc.UnaryServerInterceptor) error {
 	lis, err := net.Listen("tcp", port)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
-	// Create gRPC server with interceptors - now using the new UnaryErrorInterceptor
 	opts := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(
-			middleware.UnaryErrorInterceptor, // Using new protobuf-based error interceptor
-			loggingInterceptor(),
-			recoveryInterceptor(),
-		),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()), // Continue traces started by callers
+		grpc.ChainUnaryInterceptor(interceptors...),
 	}
 
 	server := grpc.NewServer(opts...)
@@ -106,7 +265,7 @@ func startGRPCServer(port string, todoService todopb.TodoServiceServer) error {
 	return server.Serve(lis)
 }
 
-func startHTTPGateway(httpPort, grpcPort string) error {
+func startHTTPGateway(httpPort, grpcPort string, todoService *service.TodoService, tenantsByUser map[string]string, interceptors []grpc.UnaryServerInterceptor, cfg *config.Config) error {
 	ctx := context.Background()
 
 	// Create gRPC connection
@@ -114,23 +273,73 @@ func startHTTPGateway(httpPort, grpcPort string) error {
 		ctx,
 		"localhost"+grpcPort,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()), // Propagate HTTP trace context to gRPC
 	)
 	if err != nil {
 		return fmt.Errorf("failed to dial gRPC server: %w", err)
 	}
 
+	var marshaler runtime.Marshaler = &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames:   true,
+			EmitUnpopulated: false,
+		},
+		UnmarshalOptions: protojson.UnmarshalOptions{
+			DiscardUnknown: true,
+		},
+	}
+	// Report undecodable request bodies as 400 INVALID_FORMAT with a position
+	marshaler = &middleware.MalformedJSONMarshaler{Marshaler: marshaler}
+	if cfg.Envelope.Enabled {
+		marshaler = &middleware.EnvelopeMarshaler{Marshaler: marshaler, DataKey: cfg.Envelope.DataKey, MetaKey: cfg.Envelope.MetaKey}
+	}
+
+	// httpOnlyRoutes are served next to the generated gateway routes. Both are
+	// fed to the route table behind 405 Allow headers and metric labels, so it
+	// always matches what is registered. Each runs through the gRPC interceptor
+	// chain as the RPC named by method, so auth, feature flags, read-only mode,
+	// limits and error logging apply as they do to gateway routes.
+	httpOnlyRoutes := []struct {
+		route   middleware.Route
+		method  string
+		handler middleware.HTTPOnlyHandler
+	}{
+		// Describe the filter syntax ListTasks accepts
+		{middleware.Route{Method: http.MethodGet, Template: "/v1/tasks:filterSchema"}, "TaskFilterSchema", filterSchemaHandler},
+
+		// iCalendar feed of tasks with due dates
+		{middleware.Route{Method: http.MethodGet, Template: "/v1/tasks:calendar"}, "ExportCalendar", calendarHandler(todoService)},
+
+		// CSV export for spreadsheets, scoped the same way as the calendar feed
+		{middleware.Route{Method: http.MethodGet, Template: "/v1/tasks:csv"}, "ExportCSV", csvHandler(todoService)},
+
+		// Report stored tasks that today's validation rules would reject
+		{middleware.Route{Method: http.MethodGet, Template: "/v1/tasks:validateAll"}, "ValidateAllTasks", validateAllHandler(todoService)},
+
+		// Field-level change history of a task, oldest first
+		{middleware.Route{Method: http.MethodGet, Template: "/v1/tasks/{id}:history"}, "GetTaskHistory", taskHistoryHandler(todoService)},
+
+		// Sample error responses for client developers; answers 501 unless
+		// enabled. Exempt from the chain: the samples are not real failures and
+		// must not be logged, counted or redacted like one.
+		{middleware.Route{Method: http.MethodGet, Template: "/v1/debug/errors/{code}"}, "", debugErrorsHandler(cfg.Features.DebugErrors)},
+
+		// Recent task writes for admins; answers 501 unless the log is enabled
+		{middleware.Route{Method: http.MethodGet, Template: "/v1/debug/mutations"}, "RecentMutations", mutationsHandler(todoService)},
+	}
+	routes := middleware.ServiceRoutes(todopb.File_api_proto_todo_v1_todo_proto.Services().ByName("TodoService"))
+	for _, r := range httpOnlyRoutes {
+		routes = append(routes, r.route)
+	}
+	routeTable := middleware.NewRouteTable(routes...)
+
 	// Create gateway mux with custom error handler
 	mux := runtime.NewServeMux(
 		runtime.WithErrorHandler(middleware.CustomHTTPError), // Using new protobuf-based error handler
-		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-			MarshalOptions: protojson.MarshalOptions{
-				UseProtoNames:   true,
-				EmitUnpopulated: false,
-			},
-			UnmarshalOptions: protojson.UnmarshalOptions{
-				DiscardUnknown: true,
-			},
-		}),
+		runtime.WithMetadata(middleware.GatewayTraceIDMetadata),
+		runtime.WithIncomingHeaderMatcher(middleware.GatewayHeaderMatcher),
+		runtime.WithRoutingErrorHandler(middleware.RoutingErrorHandler(routeTable.AllowedMethods)),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, marshaler),
 	)
 
 	// Register service handler
@@ -138,11 +347,25 @@ func startHTTPGateway(httpPort, grpcPort string) error {
 		return fmt.Errorf("failed to register service handler: %w", err)
 	}
 
+	// Register the HTTP-only endpoints from the same table the route matching uses
+	chain := middleware.ChainUnaryInterceptors(interceptors...)
+	for _, r := range httpOnlyRoutes {
+		handler := middleware.InterceptedHTTPHandler(mux, nil, "", r.handler)
+		if r.method != "" {
+			handler = middleware.InterceptedHTTPHandler(mux, chain, httpOnlyFullMethod(r.method), r.handler)
+		}
+		if err := mux.HandlePath(r.route.Method, r.route.Template, handler); err != nil {
+			return fmt.Errorf("failed to register %s %s: %w", r.route.Method, r.route.Template, err)
+		}
+	}
+
 	// Create HTTP server with middleware
 	handler := middleware.HTTPErrorHandler( // Using new protobuf-based HTTP error handler
-		corsMiddleware(
-			authMiddleware(
-				loggingHTTPMiddleware(mux),
+		middleware.HTTPMetricsMiddleware(routeTable.Template,
+			corsMiddleware(cfg.CORS,
+				middleware.AuthMiddleware(tenantsByUser,
+					loggingHTTPMiddleware(cfg.Logging.SlowRequestThreshold, mux),
+				),
 			),
 		),
 	)
@@ -158,9 +381,179 @@ func startHTTPGateway(httpPort, grpcPort string) error {
 	return server.ListenAndServe()
 }
 
+// httpOnlyFullMethod names an HTTP-only route as if it were a TodoService RPC,
+// e.g. "/todo.v1.TodoService/ExportCSV", so method-keyed middleware such as
+// feature flags can address it
+func httpOnlyFullMethod(method string) string {
+	return "/" + todopb.TodoService_ServiceDesc.ServiceName + "/" + method
+}
+
+func filterSchemaHandler(w http.ResponseWriter, _ *http.Request, _ map[string]string) error {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"fields": service.TaskFilterSchema(),
+	}); err != nil {
+		log.Printf("Failed to encode filter schema: %v", err)
+	}
+	return nil
+}
+
+// calendarHandler serves the caller's tasks with due dates as an .ics document,
+// honoring the same filter query parameter as ListTasks
+func calendarHandler(todoService *service.TodoService) middleware.HTTPOnlyHandler {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+		ics, err := todoService.ExportCalendar(r.Context(), r.URL.Query().Get("filter"))
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", service.CalendarContentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="tasks.ics"`)
+		if _, err := w.Write(ics); err != nil {
+			log.Printf("Failed to write calendar: %v", err)
+		}
+		return nil
+	}
+}
+
+// csvHandler serves the caller's tasks as a CSV download, honoring the same
+// filter query parameter as ListTasks
+func csvHandler(todoService *service.TodoService) middleware.HTTPOnlyHandler {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+		data, err := todoService.ExportCSV(r.Context(), r.URL.Query().Get("filter"))
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", service.CSVContentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="tasks.csv"`)
+		if _, err := w.Write(data); err != nil {
+			log.Printf("Failed to write CSV export: %v", err)
+		}
+		return nil
+	}
+}
+
+// writeServiceError renders an error returned by a direct service call the
+// same way the gateway renders errors coming back over gRPC
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		err = appErr.ToGRPCStatus().Err()
+	}
+	middleware.CustomHTTPError(r.Context(), nil, nil, w, r, err)
+}
+
+// readyzHandler answers readiness probes: 200 while storage is reachable,
+// otherwise the service's error, e.g. 503 SERVICE_UNAVAILABLE
+func readyzHandler(todoService *service.TodoService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := todoService.Ready(r.Context()); err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	}
+}
+
+// validateAllHandler lists the tenant's stored tasks that fail validation
+func validateAllHandler(todoService *service.TodoService) middleware.HTTPOnlyHandler {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+		tasks, err := todoService.ValidateAllTasks(r.Context())
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"tasks": tasks}); err != nil {
+			log.Printf("Failed to encode validation results: %v", err)
+		}
+		return nil
+	}
+}
+
+// taskHistoryHandler pages through a task's recorded field changes
+func taskHistoryHandler(todoService *service.TodoService) middleware.HTTPOnlyHandler {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) error {
+		query := r.URL.Query()
+		req := &service.GetTaskHistoryRequest{
+			Name:      "tasks/" + pathParams["id"],
+			PageToken: query.Get("page_token"),
+		}
+		if raw := query.Get("page_size"); raw != "" {
+			size, err := strconv.Atoi(raw)
+			if err != nil {
+				return apperrors.NewInvalidArgument(fmt.Sprintf("Invalid page_size %q", raw), monitoring.TraceIDFromContext(r.Context()))
+			}
+			req.PageSize = size
+		}
+
+		resp, err := todoService.GetTaskHistory(r.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("Failed to encode task history: %v", err)
+		}
+		return nil
+	}
+}
+
+// mutationsHandler lists the caller's tenant's recent task writes, oldest first
+func mutationsHandler(todoService *service.TodoService) middleware.HTTPOnlyHandler {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+		mutations, err := todoService.RecentMutations(r.Context())
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"mutations": mutations}); err != nil {
+			log.Printf("Failed to encode mutations: %v", err)
+		}
+		return nil
+	}
+}
+
+// debugErrorsHandler renders the error response clients would receive for a
+// gRPC status code (e.g. NOT_FOUND), using the gateway's own error handler
+func debugErrorsHandler(enabled bool) middleware.HTTPOnlyHandler {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) error {
+		if !enabled {
+			return apperrors.NewFeatureDisabled("debug errors", "")
+		}
+
+		name := pathParams["code"]
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil || code == codes.OK {
+			return apperrors.NewNotFound("Error code", name, "")
+		}
+
+		return status.Errorf(code, "Sample %s error", name)
+	}
+}
+
 // Middleware implementations
 
-func loggingInterceptor() grpc.UnaryServerInterceptor {
+// requestLogger writes per-request logs at the configured log level
+var requestLogger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+// logRequest logs a finished request at debug, or at warn with slow=true
+// once it took longer than slowThreshold
+func logRequest(ctx context.Context, slowThreshold time.Duration, duration time.Duration, msg string, attrs ...any) {
+	attrs = append(attrs, "duration", duration)
+	if slowThreshold > 0 && duration > slowThreshold {
+		requestLogger.WarnContext(ctx, msg, append(attrs, "slow", true)...)
+		return
+	}
+	requestLogger.DebugContext(ctx, msg, attrs...)
+}
+
+func loggingInterceptor(slowThreshold time.Duration) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 
@@ -174,27 +567,33 @@ func loggingInterceptor() grpc.UnaryServerInterceptor {
 			statusCode = status.Code(err).String()
 		}
 
-		log.Printf("gRPC: %s %s %s %v", info.FullMethod, statusCode, duration, err)
+		logRequest(ctx, slowThreshold, duration, "gRPC request",
+			"method", info.FullMethod, "status", statusCode, "trace_id", monitoring.TraceIDFromContext(ctx))
 
 		return resp, err
 	}
 }
 
-func recoveryInterceptor() grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-		defer func() {
-			if r := recover(); r != nil {
-				log.Printf("Recovered from panic: %v", r)
-				monitoring.RecordPanicRecovery(ctx)
-				err = status.Error(codes.Internal, "Internal server error")
-			}
-		}()
+func deadlineInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		resp, err := handler(ctx, req)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			traceID := monitoring.TraceIDFromContext(ctx)
+			return nil, apperrors.NewTimeout(traceID)
+		}
 
-		return handler(ctx, req)
+		return resp, err
 	}
 }
 
-func loggingHTTPMiddleware(next http.Handler) http.Handler {
+func loggingHTTPMiddleware(slowThreshold time.Duration, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
@@ -206,15 +605,35 @@ func loggingHTTPMiddleware(next http.Handler) http.Handler {
 
 		// Log request
 		duration := time.Since(start)
-		log.Printf("HTTP: %s %s %d %v", r.Method, r.URL.Path, wrapped.statusCode, duration)
+		logRequest(r.Context(), slowThreshold, duration, "HTTP request",
+			"method", r.Method, "path", r.URL.Path, "status", wrapped.statusCode)
 	})
 }
 
-func corsMiddleware(next http.Handler) http.Handler {
+// corsRestrictedPrefixes are operator-facing routes that browsers on other
+// origins should never call; they get no CORS headers at all
+var corsRestrictedPrefixes = []string{"/v1/debug/"}
+
+// corsMiddleware applies the configured CORS policy to public routes and
+// leaves restricted routes without CORS headers, so cross-origin browser
+// calls to them fail
+func corsMiddleware(cfg config.CORSConfig, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Trace-ID")
+		for _, prefix := range corsRestrictedPrefixes {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		if origin := allowedOrigin(cfg.AllowedOrigins, r.Header.Get("Origin")); origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if origin != "*" {
+				w.Header().Add("Vary", "Origin")
+			}
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Trace-ID, X-Request-Nonce, X-Request-Timestamp, X-Request-Signature, traceparent, tracestate")
+		}
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -225,24 +644,18 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func authMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Simple auth for demo - in production use proper authentication
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			authHeader = "Bearer anonymous"
+// allowedOrigin returns the Access-Control-Allow-Origin value for a request
+// origin, or "" if the origin is not allowed
+func allowedOrigin(allowed []string, origin string) string {
+	for _, o := range allowed {
+		if o == "*" {
+			return "*"
 		}
-
-		// Extract user from token
-		user := "anonymous"
-		if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-			user = authHeader[7:]
+		if origin != "" && o == origin {
+			return origin
 		}
-
-		// Add user to context
-		ctx := context.WithValue(r.Context(), "user", user)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+	}
+	return ""
 }
 
 type statusResponseWriter struct {
@@ -0,0 +1,487 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	todopb "github.com/bhatti/todo-api-errors/api/proto/todo/v1"
+	"github.com/bhatti/todo-api-errors/internal/config"
+	"github.com/bhatti/todo-api-errors/internal/middleware"
+	"github.com/bhatti/todo-api-errors/internal/repository"
+	"github.com/bhatti/todo-api-errors/internal/service"
+	"github.com/bhatti/todo-api-errors/internal/validation"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type traceRecordingService struct {
+	todopb.UnimplementedTodoServiceServer
+	span trace.SpanContext
+}
+
+func (s *traceRecordingService) CreateTask(ctx context.Context, req *todopb.CreateTaskRequest) (*todopb.Task, error) {
+	s.span = trace.SpanContextFromContext(ctx)
+	return req.Task, nil
+}
+
+func TestGatewaySpanIsParentOfServiceSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	// Same stats handlers as startGRPCServer and startHTTPGateway
+	lis := bufconn.Listen(1 << 20)
+	svc := &traceRecordingService{}
+	server := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	todopb.RegisterTodoServiceServer(server, svc)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, gatewaySpan := tp.Tracer("test").Start(context.Background(), "POST /v1/tasks")
+	_, err = todopb.NewTodoServiceClient(conn).CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{Title: "traced"}})
+	gatewaySpan.End()
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	if svc.span.TraceID() != gatewaySpan.SpanContext().TraceID() {
+		t.Fatalf("service trace ID = %s, want %s", svc.span.TraceID(), gatewaySpan.SpanContext().TraceID())
+	}
+
+	// gateway span -> client span -> server span
+	spans := make(map[trace.SpanID]sdktrace.ReadOnlySpan)
+	var serverSpan sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		spans[s.SpanContext().SpanID()] = s
+		if s.SpanKind() == trace.SpanKindServer {
+			serverSpan = s
+		}
+	}
+	if serverSpan == nil {
+		t.Fatal("no server span recorded")
+	}
+	clientSpan, ok := spans[serverSpan.Parent().SpanID()]
+	if !ok || clientSpan.SpanKind() != trace.SpanKindClient {
+		t.Fatalf("server span parent %s is not the client span", serverSpan.Parent().SpanID())
+	}
+	if clientSpan.Parent().SpanID() != gatewaySpan.SpanContext().SpanID() {
+		t.Errorf("client span parent = %s, want the gateway span %s", clientSpan.Parent().SpanID(), gatewaySpan.SpanContext().SpanID())
+	}
+}
+
+func TestDeadlineInterceptorRendersTimeoutAs504(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: todopb.TodoService_ListTasks_FullMethodName}
+	slow := func(ctx context.Context, req interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	// Same nesting as the server's interceptor chain
+	_, err := middleware.UnaryErrorInterceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return deadlineInterceptor(10*time.Millisecond)(ctx, req, info, slow)
+	})
+
+	rec := httptest.NewRecorder()
+	middleware.CustomHTTPError(context.Background(), nil, nil, rec, httptest.NewRequest(http.MethodGet, "/v1/tasks", nil), err)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status = %d, want 504; body %s", rec.Code, rec.Body.String())
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["type"] != "https://api.example.com/errors/timeout" {
+		t.Errorf("type = %v, want timeout", body["type"])
+	}
+}
+
+func TestDeadlineInterceptorPassesFastRequests(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: todopb.TodoService_ListTasks_FullMethodName}
+	fast := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	for _, timeout := range []time.Duration{0, time.Second} {
+		resp, err := deadlineInterceptor(timeout)(context.Background(), nil, info, fast)
+		if err != nil || resp != "ok" {
+			t.Errorf("timeout %v: got %v, %v; want ok", timeout, resp, err)
+		}
+	}
+}
+
+func TestDebugErrorsHandler(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		enabled  bool
+		code     string
+		want     int
+		wantType string
+	}{
+		{"disabled", false, "NOT_FOUND", http.StatusNotImplemented, "https://api.example.com/errors/feature-disabled"},
+		{"sample not found", true, "NOT_FOUND", http.StatusNotFound, ""},
+		{"sample permission denied", true, "PERMISSION_DENIED", http.StatusForbidden, ""},
+		{"unknown code", true, "NOPE", http.StatusNotFound, "https://api.example.com/errors/resource-not-found"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/v1/debug/errors/"+tc.code, nil)
+			// Served without the interceptor chain, as startHTTPGateway does
+			middleware.InterceptedHTTPHandler(runtime.NewServeMux(), nil, "", debugErrorsHandler(tc.enabled))(rec, req, map[string]string{"code": tc.code})
+
+			if rec.Code != tc.want {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tc.want, rec.Body.String())
+			}
+			if tc.wantType == "" {
+				return
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["type"] != tc.wantType {
+				t.Errorf("type = %v, want %s", body["type"], tc.wantType)
+			}
+		})
+	}
+}
+
+func TestCalendarHandler(t *testing.T) {
+	todoService, err := service.NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), "user", "bob")
+	if _, err := todoService.CreateTask(ctx, &todopb.CreateTaskRequest{Task: &todopb.Task{
+		Title:   "Pay rent",
+		DueDate: timestamppb.New(time.Now().Add(24 * time.Hour)),
+	}}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	// Behind the chain's auth, the way startHTTPGateway serves it
+	handler := middleware.InterceptedHTTPHandler(runtime.NewServeMux(), middleware.ChainUnaryInterceptors(
+		middleware.UnaryTraceIDInterceptor,
+		middleware.AuthInterceptor(nil),
+		middleware.UnaryErrorInterceptor,
+	), httpOnlyFullMethod("ExportCalendar"), calendarHandler(todoService))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/tasks:calendar", nil)
+	req.Header.Set("Authorization", "Bearer bob")
+	handler(rec, req, nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != service.CalendarContentType {
+		t.Errorf("Content-Type = %q, want %q", got, service.CalendarContentType)
+	}
+	if got := strings.Count(rec.Body.String(), "BEGIN:VEVENT"); got != 1 {
+		t.Errorf("%d VEVENTs, want 1", got)
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/v1/tasks:calendar?filter=color%3Dred", nil)
+	req.Header.Set("Authorization", "Bearer bob")
+	handler(rec, req, nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid filter status = %d, want 400", rec.Code)
+	}
+}
+
+// newTestGateway serves svc over bufconn behind the given interceptors and
+// returns the HTTP gateway in front of it, wired like startHTTPGateway
+func newTestGateway(t *testing.T, svc todopb.TodoServiceServer, interceptors ...grpc.UnaryServerInterceptor) http.Handler {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	todopb.RegisterTodoServiceServer(server, svc)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	mux := runtime.NewServeMux(
+		runtime.WithErrorHandler(middleware.CustomHTTPError),
+		runtime.WithMetadata(middleware.GatewayTraceIDMetadata),
+	)
+	if err := todopb.RegisterTodoServiceHandler(context.Background(), mux, conn); err != nil {
+		t.Fatal(err)
+	}
+	return middleware.HTTPErrorHandler(mux)
+}
+
+func TestTraceIDInLogsMatchesResponse(t *testing.T) {
+	todoService, err := service.NewTodoService(repository.NewInMemoryRepository(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := newTestGateway(t, todoService, middleware.UnaryTraceIDInterceptor, middleware.UnaryErrorInterceptor)
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	loggedTrace := regexp.MustCompile(`method=\S*/GetTask code=RESOURCE_NOT_FOUND trace_id=(\S+)`)
+
+	for _, tc := range []struct {
+		name    string
+		traceID string
+	}{
+		{"client supplied", "client-trace-1"},
+		{"generated", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logs.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/v1/tasks/missing", nil)
+			if tc.traceID != "" {
+				req.Header.Set("X-Trace-ID", tc.traceID)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			var body struct {
+				TraceID string `json:"traceId"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v; %s", err, rec.Body.String())
+			}
+			m := loggedTrace.FindStringSubmatch(logs.String())
+			if m == nil {
+				t.Fatalf("no error logged with a trace ID:\n%s", logs.String())
+			}
+			if body.TraceID == "" || m[1] != body.TraceID {
+				t.Errorf("logged trace ID %q, response trace ID %q; want them equal", m[1], body.TraceID)
+			}
+			if tc.traceID != "" && body.TraceID != tc.traceID {
+				t.Errorf("response trace ID = %q, want the client's %q", body.TraceID, tc.traceID)
+			}
+		})
+	}
+}
+
+func TestCORSMiddlewareIsRouteAware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	for _, tc := range []struct {
+		name    string
+		origins []string
+		path    string
+		origin  string
+		want    string
+	}{
+		{"public route, any origin", []string{"*"}, "/v1/tasks", "https://app.example.com", "*"},
+		{"debug route omits CORS", []string{"*"}, "/v1/debug/errors/NOT_FOUND", "https://app.example.com", ""},
+		{"allowed origin echoed", []string{"https://app.example.com"}, "/v1/tasks", "https://app.example.com", "https://app.example.com"},
+		{"other origin refused", []string{"https://app.example.com"}, "/v1/tasks", "https://evil.example.com", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("Origin", tc.origin)
+			rec := httptest.NewRecorder()
+			corsMiddleware(config.CORSConfig{AllowedOrigins: tc.origins}, next).ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tc.want {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tc.want)
+			}
+			if tc.want == "" && rec.Header().Get("Access-Control-Allow-Methods") != "" {
+				t.Error("Access-Control-Allow-Methods set without an allowed origin")
+			}
+		})
+	}
+}
+
+func TestTaskDefaults(t *testing.T) {
+	noLow, err := validation.NewAllowlist(nil, []string{"PRIORITY_MEDIUM", "PRIORITY_HIGH"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaults, err := taskDefaults(config.TenantConfig{DefaultStatus: "STATUS_IN_PROGRESS", DefaultPriority: "PRIORITY_HIGH"}, noLow)
+	if err != nil {
+		t.Fatalf("taskDefaults: %v", err)
+	}
+	if defaults.Status != todopb.Status_STATUS_IN_PROGRESS || defaults.Priority != todopb.Priority_PRIORITY_HIGH {
+		t.Errorf("defaults = %+v", defaults)
+	}
+
+	for _, tenantCfg := range []config.TenantConfig{
+		{DefaultPriority: "HIGH"},
+		{DefaultStatus: "STATUS_ARCHIVED"},
+		{DefaultPriority: "PRIORITY_LOW"}, // excluded by the tenant's allowlist
+	} {
+		if _, err := taskDefaults(tenantCfg, noLow); err == nil {
+			t.Errorf("taskDefaults(%+v) accepted an invalid default", tenantCfg)
+		}
+	}
+}
+
+type panickingService struct {
+	todopb.UnimplementedTodoServiceServer
+}
+
+func (panickingService) GetTask(context.Context, *todopb.GetTaskRequest) (*todopb.Task, error) {
+	panic("nil map write")
+}
+
+func TestRecoveredPanicRendersInternalErrorWithTraceID(t *testing.T) {
+	handler := newTestGateway(t, panickingService{},
+		middleware.UnaryTraceIDInterceptor, middleware.UnaryErrorInterceptor, middleware.UnaryRecoveryInterceptor)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tasks/1", nil)
+	req.Header.Set("X-Trace-ID", "panic-trace-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500; body %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["type"] != "https://api.example.com/errors/internal-error" || body["traceId"] != "panic-trace-1" {
+		t.Errorf("type/traceId = %v/%v, want internal-error/panic-trace-1", body["type"], body["traceId"])
+	}
+	if strings.Contains(rec.Body.String(), "nil map write") {
+		t.Error("panic value leaked to the client")
+	}
+}
+
+func TestUnimplementedMethodRendersEnvelope(t *testing.T) {
+	// panickingService only implements GetTask; ListTasks falls through to
+	// the embedded UnimplementedTodoServiceServer
+	handler := newTestGateway(t, panickingService{}, middleware.UnaryTraceIDInterceptor, middleware.UnaryErrorInterceptor)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tasks", nil)
+	req.Header.Set("X-Trace-ID", "unimplemented-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("status = %d, want 501; body %s", rec.Code, rec.Body.String())
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["type"] != "https://api.example.com/errors/not-implemented" || body["traceId"] != "unimplemented-1" {
+		t.Errorf("type/traceId = %v/%v, want not-implemented/unimplemented-1", body["type"], body["traceId"])
+	}
+	if detail, _ := body["detail"].(string); !strings.Contains(detail, "ListTasks") {
+		t.Errorf("detail = %q, want it to name ListTasks", detail)
+	}
+}
+
+// unreachableRepository is a store whose backend cannot be reached
+type unreachableRepository struct {
+	repository.TodoRepository
+}
+
+func (unreachableRepository) Ping(context.Context) error {
+	return repository.ErrConnection
+}
+
+func TestReadyzHandler(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		repo repository.TodoRepository
+		want int
+	}{
+		{"in-memory store", repository.NewInMemoryRepository(true), http.StatusOK},
+		{"unreachable store", unreachableRepository{}, http.StatusServiceUnavailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			todoService, err := service.NewTodoService(tc.repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			readyzHandler(todoService)(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tc.want {
+				t.Errorf("status = %d, want %d; body %s", rec.Code, tc.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestLoggingInterceptorFlagsSlowRequests(t *testing.T) {
+	var buf bytes.Buffer
+	previous := requestLogger
+	requestLogger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	t.Cleanup(func() { requestLogger = previous })
+
+	interceptor := loggingInterceptor(20 * time.Millisecond)
+	info := &grpc.UnaryServerInfo{FullMethod: "/todo.v1.TodoService/GetTask"}
+
+	for _, tc := range []struct {
+		name      string
+		delay     time.Duration
+		wantLevel string
+		wantSlow  bool
+	}{
+		{"fast", 0, "level=DEBUG", false},
+		{"slow", 50 * time.Millisecond, "level=WARN", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				time.Sleep(tc.delay)
+				return "ok", nil
+			})
+
+			line := buf.String()
+			if !strings.Contains(line, tc.wantLevel) || !strings.Contains(line, "method=/todo.v1.TodoService/GetTask") {
+				t.Errorf("log = %q, want %s for the method", line, tc.wantLevel)
+			}
+			if strings.Contains(line, "slow=true") != tc.wantSlow {
+				t.Errorf("log = %q, want slow=true %v", line, tc.wantSlow)
+			}
+		})
+	}
+}